@@ -0,0 +1,417 @@
+// cmd/emotion-explorer/cli.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/itsforsxm123/emotion-explorer/internal/diagnostics"
+	"github.com/itsforsxm123/emotion-explorer/internal/ipc"
+	"github.com/itsforsxm123/emotion-explorer/internal/journal"
+	"github.com/itsforsxm123/emotion-explorer/internal/learn"
+	"github.com/itsforsxm123/emotion-explorer/internal/logging"
+	"github.com/itsforsxm123/emotion-explorer/internal/paths"
+	"github.com/itsforsxm123/emotion-explorer/internal/settings"
+	"github.com/itsforsxm123/emotion-explorer/internal/ui"
+)
+
+// --- Command Line Tools ---
+//
+// Besides starting the GUI, the binary understands a few maintenance
+// subcommands. They never open a window:
+//
+//	emotion-explorer journal check  [--file PATH]
+//	emotion-explorer journal repair [--file PATH]
+//	emotion-explorer dataset export   [--format FORMAT] [--out PATH]
+//	emotion-explorer dataset convert  [--from FORMAT] [--to FORMAT] INPUT OUTPUT
+//	emotion-explorer dataset validate [--format FORMAT] FILE
+//	emotion-explorer diagnostics [--out PATH]
+//
+// Dataset formats are json, csv, yaml and toml; by default they follow the
+// file extension. "-" as OUTPUT (or --out) writes to stdout.
+//
+// Otherwise the arguments say what the launched app should do first; if an
+// instance is already running the request is forwarded to it instead:
+//
+//	emotion-explorer [--debug] [--log | --log-emotion ID | --history | --open ROUTE]
+//
+// --debug turns on debug logging for this launch (see internal/logging).
+//
+// ROUTE names a place in the app, e.g. browse/happy/playful, log/sad,
+// history?range=7d or emotion/aroused (see ui.ParseRoute).
+
+// runCLI handles command line subcommands. It returns handled=false when the
+// arguments don't name a subcommand, in which case the GUI should start.
+func runCLI(args []string, stdout, stderr io.Writer) (handled bool, exitCode int) {
+	if len(args) == 0 {
+		return false, 0
+	}
+	switch args[0] {
+	case "journal":
+		return true, runJournalCommand(args[1:], stdout, stderr)
+	case "dataset":
+		return true, runDatasetCommand(args[1:], stdout, stderr)
+	case "diagnostics":
+		return true, runDiagnosticsCommand(args[1:], stdout, stderr)
+	}
+	return false, 0
+}
+
+// runJournalCommand implements "journal check" and "journal repair".
+// check exits with 1 if the journal needs a repair; repair keeps the
+// original file as a timestamped .bak next to it.
+func runJournalCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || (args[0] != "check" && args[0] != "repair") {
+		fmt.Fprintln(stderr, "usage: emotion-explorer journal check|repair [--file PATH]")
+		return 2
+	}
+	action := args[0]
+
+	flags := flag.NewFlagSet("journal "+action, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	path := flags.String("file", journal.FilePath(), "journal file to "+action)
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	// Entries are checked against the dataset the app uses, personal words included
+	emotionData, _, _, _, err := loadCLIDataset(stderr)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error loading emotion data: %v\n", err)
+		return 1
+	}
+	lookup := journalLookup(core.NewIDResolver(emotionData))
+
+	if action == "check" {
+		report, err := journal.CheckFile(*path, lookup, time.Now())
+		if err != nil {
+			fmt.Fprintf(stderr, "Error checking journal: %v\n", err)
+			return 1
+		}
+		fmt.Fprint(stdout, report.String())
+		if report.NeedsRepair() {
+			fmt.Fprintln(stdout, "Run 'emotion-explorer journal repair' to fix the problems that can be fixed automatically.")
+			return 1
+		}
+		return 0
+	}
+
+	result, err := journal.RepairFile(*path, lookup, time.Now())
+	if err != nil {
+		fmt.Fprintf(stderr, "Error repairing journal: %v\n", err)
+		return 1
+	}
+	if result.BackupPath == "" {
+		fmt.Fprintf(stdout, "Journal '%s' does not exist, nothing to repair.\n", *path)
+		return 0
+	}
+	fmt.Fprint(stdout, result.Report.String())
+	fmt.Fprintf(stdout, "Original kept at: %s\n", result.BackupPath)
+	fmt.Fprintf(stdout, "Repaired journal written with %d entries.\n", result.Written)
+	return 0
+}
+
+// runDatasetCommand implements "dataset export", "dataset convert" and
+// "dataset validate", so vocabularies can be maintained in a spreadsheet
+// (CSV) or other formats and checked before the app loads them.
+func runDatasetCommand(args []string, stdout, stderr io.Writer) int {
+	const usage = "usage: emotion-explorer dataset export|convert|validate [flags] (see --help)"
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, usage)
+		return 2
+	}
+	action := args[0]
+	flags := flag.NewFlagSet("dataset "+action, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	switch action {
+	case "export":
+		// The built-in dataset, as a starting point for a custom one
+		formatName := flags.String("format", "", "output format (default: from --out's extension, or json)")
+		out := flags.String("out", "-", "file to write")
+		if err := flags.Parse(args[1:]); err != nil {
+			return 2
+		}
+		if flags.NArg() > 0 {
+			fmt.Fprintln(stderr, "usage: emotion-explorer dataset export [--format FORMAT] [--out PATH]")
+			return 2
+		}
+		format, err := datasetFormat(*formatName, *out, data.FormatJSON)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 2
+		}
+		emotionData, err := data.LoadEmotions()
+		if err != nil {
+			fmt.Fprintf(stderr, "Error loading emotion data: %v\n", err)
+			return 1
+		}
+		return writeDataset(*out, emotionData, format, stdout, stderr)
+
+	case "convert":
+		from := flags.String("from", "", "input format (default: from INPUT's extension)")
+		to := flags.String("to", "", "output format (default: from OUTPUT's extension)")
+		if err := flags.Parse(args[1:]); err != nil {
+			return 2
+		}
+		if flags.NArg() != 2 {
+			fmt.Fprintln(stderr, "usage: emotion-explorer dataset convert [--from FORMAT] [--to FORMAT] INPUT OUTPUT")
+			return 2
+		}
+		emotionData, code := readDataset(flags.Arg(0), *from, stderr)
+		if code != 0 {
+			return code
+		}
+		format, err := datasetFormat(*to, flags.Arg(1), "")
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 2
+		}
+		if reportDatasetProblems(emotionData, stderr) {
+			return 1 // Never hand the app a dataset it would reject
+		}
+		return writeDataset(flags.Arg(1), emotionData, format, stdout, stderr)
+
+	case "validate":
+		formatName := flags.String("format", "", "input format (default: from FILE's extension)")
+		if err := flags.Parse(args[1:]); err != nil {
+			return 2
+		}
+		if flags.NArg() != 1 {
+			fmt.Fprintln(stderr, "usage: emotion-explorer dataset validate [--format FORMAT] FILE")
+			return 2
+		}
+		emotionData, code := readDataset(flags.Arg(0), *formatName, stderr)
+		if code != 0 {
+			return code
+		}
+		if reportDatasetProblems(emotionData, stderr) {
+			return 1
+		}
+		fmt.Fprintf(stdout, "%s is valid: %d emotions, %d aliases.\n", flags.Arg(0), len(emotionData.Emotions), len(emotionData.Aliases))
+		return 0
+	}
+	fmt.Fprintln(stderr, usage)
+	return 2
+}
+
+// datasetFormat resolves a --format style flag: the named format if given,
+// otherwise the one path's extension names, otherwise fallback ("" means the
+// format is required).
+func datasetFormat(name, path string, fallback data.Format) (data.Format, error) {
+	if name != "" {
+		return data.ParseFormat(name)
+	}
+	if path != "-" {
+		if format, err := data.FormatFromPath(path); err == nil || fallback == "" {
+			return format, err
+		}
+	}
+	if fallback == "" {
+		return "", fmt.Errorf("use a format flag to choose the format for stdout")
+	}
+	return fallback, nil
+}
+
+// readDataset reads a dataset file for the dataset subcommands. It returns a
+// non-zero exit code (after printing the error) on failure.
+func readDataset(path, formatName string, stderr io.Writer) (data.EmotionData, int) {
+	format, err := datasetFormat(formatName, path, "")
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return data.EmotionData{}, 2
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return data.EmotionData{}, 1
+	}
+	defer file.Close()
+	emotionData, err := data.Decode(file, format)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error reading %s: %v\n", path, err)
+		return data.EmotionData{}, 1
+	}
+	return emotionData, 0
+}
+
+// writeDataset writes a dataset to path ("-" for stdout) in the given format.
+func writeDataset(path string, emotionData data.EmotionData, format data.Format, stdout, stderr io.Writer) int {
+	out := stdout
+	if path != "-" {
+		file, err := os.Create(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		defer file.Close()
+		out = file
+	}
+	if err := data.Encode(out, emotionData, format); err != nil {
+		fmt.Fprintf(stderr, "Error writing %s dataset: %v\n", format, err)
+		return 1
+	}
+	if path != "-" {
+		fmt.Fprintf(stderr, "Wrote %d emotions to %s.\n", len(emotionData.Emotions), path)
+	}
+	return 0
+}
+
+// reportDatasetProblems prints data.Validate's findings and reports whether
+// there were any.
+func reportDatasetProblems(emotionData data.EmotionData, stderr io.Writer) bool {
+	problems := data.Validate(emotionData)
+	for _, problem := range problems {
+		fmt.Fprintf(stderr, "Invalid dataset: %v\n", problem)
+	}
+	return len(problems) > 0
+}
+
+// runDiagnosticsCommand implements "diagnostics": it writes a bundle for bug
+// reports (see internal/diagnostics), e.g. when the app doesn't start.
+func runDiagnosticsCommand(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("diagnostics", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	now := time.Now()
+	out := flags.String("out", diagnostics.FileName(now), "zip file to write")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintln(stderr, "usage: emotion-explorer diagnostics [--out PATH]")
+		return 2
+	}
+
+	// Gather what the app would load, without giving up on broken files:
+	// they are what the bundle is for
+	emotionData, datasetPath, conflicts, userSettings, err := loadCLIDataset(stderr)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error loading emotion data: %v\n", err)
+		return 1
+	}
+
+	report := diagnostics.Collect(diagnosticsSources(emotionData, datasetPath, conflicts, userSettings, "none (command line)"), now)
+	if err := diagnostics.WriteFile(*out, report); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Diagnostics written to %s. It holds no notes or logged emotions; please attach it to your bug report.\n", *out)
+	return 0
+}
+
+// loadCLIDataset loads the dataset the app would use: the custom dataset
+// from the settings, or the built-in one if there is none or it can't be
+// loaded, with the user's overlay on top. Unusable settings and custom
+// datasets are warned about on stderr. datasetPath is the custom dataset in
+// use, "" for the built-in one.
+func loadCLIDataset(stderr io.Writer) (emotionData data.EmotionData, datasetPath string, conflicts []data.OverlayConflict, userSettings settings.Settings, err error) {
+	userSettings, err = settings.Load()
+	if err != nil {
+		fmt.Fprintf(stderr, "Warning: Ignoring settings: %v\n", err)
+	}
+	datasetPath = userSettings.DatasetPath
+	if datasetPath != "" {
+		if emotionData, err = data.LoadEmotionsFile(datasetPath); err != nil {
+			fmt.Fprintf(stderr, "Warning: Custom dataset unusable, using the built-in one: %v\n", err)
+			datasetPath = ""
+		}
+	}
+	if datasetPath == "" {
+		if emotionData, err = data.LoadEmotions(); err != nil {
+			return data.EmotionData{}, "", nil, userSettings, err
+		}
+	}
+	emotionData, conflicts = applyUserOverlay(emotionData)
+	return emotionData, datasetPath, conflicts, userSettings, nil
+}
+
+// diagnosticsSources describes the app's files and the dataset in use for a
+// diagnostics bundle. driver names the Fyne driver.
+func diagnosticsSources(emotionData data.EmotionData, datasetPath string, conflicts []data.OverlayConflict, userSettings settings.Settings, driver string) diagnostics.Sources {
+	return diagnostics.Sources{
+		App: diagnostics.AppInfo{
+			Name:      appName,
+			Version:   appVersion(),
+			GoVersion: runtime.Version(),
+			OS:        runtime.GOOS,
+			Arch:      runtime.GOARCH,
+			Driver:    driver,
+		},
+		Dataset:          emotionData,
+		DatasetPath:      datasetPath,
+		OverlayConflicts: conflicts,
+		Lookup:           journalLookup(core.NewIDResolver(emotionData)),
+		Settings:         userSettings,
+		LogPath:          paths.File(logging.FileName),
+		Paths: map[string]string{
+			"dataDir":  paths.DataDir(),
+			"settings": settings.FilePath(),
+			"overlay":  overlayPath(),
+			"learning": learn.FilePath(),
+		},
+	}
+}
+
+// parseLaunchRequest turns GUI launch flags into the request a running
+// instance (or this one, if it is the first) should carry out.
+// With no flags the request is to show the window. debug reports --debug,
+// which only affects this launch's logging.
+func parseLaunchRequest(args []string, stderr io.Writer) (request ipc.Request, debug bool, err error) {
+	flags := flag.NewFlagSet("emotion-explorer", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	debugFlag := flags.Bool("debug", false, "log debug messages (console and log file)")
+	startLogging := flags.Bool("log", false, "start logging a feeling")
+	logEmotion := flags.String("log-emotion", "", "log the emotion with this ID right away")
+	history := flags.Bool("history", false, "open the journal history")
+	open := flags.String("open", "", "open a route, e.g. browse/happy/playful or history?range=7d")
+	if err := flags.Parse(args); err != nil {
+		return ipc.Request{}, false, err
+	}
+	if flags.NArg() > 0 {
+		return ipc.Request{}, false, fmt.Errorf("unexpected argument '%s'", flags.Arg(0))
+	}
+
+	requests := []ipc.Request{}
+	if *startLogging {
+		requests = append(requests, ipc.Request{Command: ipc.CommandLog})
+	}
+	if *logEmotion != "" {
+		requests = append(requests, ipc.Request{Command: ipc.CommandLogEmotion, EmotionID: *logEmotion})
+	}
+	if *history {
+		requests = append(requests, ipc.Request{Command: ipc.CommandHistory})
+	}
+	if *open != "" {
+		if _, err := ui.ParseRoute(*open); err != nil {
+			return ipc.Request{}, false, err // Fail here rather than in the running instance
+		}
+		requests = append(requests, ipc.Request{Command: ipc.CommandOpen, Route: *open})
+	}
+	switch len(requests) {
+	case 0:
+		return ipc.Request{Version: ipc.ProtocolVersion, Command: ipc.CommandShow}, *debugFlag, nil
+	case 1:
+		requests[0].Version = ipc.ProtocolVersion
+		return requests[0], *debugFlag, nil
+	}
+	return ipc.Request{}, false, fmt.Errorf("--log, --log-emotion, --history and --open cannot be combined")
+}
+
+// journalLookup adapts an IDResolver to the journal integrity checker.
+// Names are the dataset's default-language names, matching what new
+// entries store.
+func journalLookup(resolver *core.IDResolver) journal.EmotionLookup {
+	return func(emotionID string) (string, string, bool) {
+		emotion, ok := resolver.Lookup(emotionID)
+		if !ok {
+			return "", "", false
+		}
+		return emotion.ID, emotion.Name, true
+	}
+}
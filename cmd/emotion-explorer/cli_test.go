@@ -0,0 +1,63 @@
+// cmd/emotion-explorer/cli_test.go
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/settings"
+)
+
+// TestJournalCommandCustomDataset tests that the journal commands check
+// entries against the custom dataset in the settings, not the built-in one.
+func TestJournalCommandCustomDataset(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir) // The overlay and other data files live in the working directory
+
+	datasetPath := filepath.Join(dir, "words.json")
+	dataset := `{"emotions": {
+  "zest":   {"id": "zest", "name": "Zest"},
+  "gusto":  {"id": "gusto", "name": "Gusto", "parentId": "zest"}
+}}`
+	if err := os.WriteFile(datasetPath, []byte(dataset), 0644); err != nil {
+		t.Fatal(err)
+	}
+	previousSettings := settings.FilePath()
+	defer settings.SetFilePath(previousSettings)
+	settings.SetFilePath(filepath.Join(dir, "settings.json"))
+	if err := settings.Save(settings.Settings{DatasetPath: datasetPath}); err != nil {
+		t.Fatal(err)
+	}
+
+	journalPath := filepath.Join(dir, "journal.json")
+	entries := `[
+  {"id": "a", "schema_version": 1, "timestamp": "2026-10-01T09:00:00Z", "emotion_id": "gusto", "emotion_name": "Gusto"}
+]`
+	if err := os.WriteFile(journalPath, []byte(entries), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	handled, code := runCLI([]string{"journal", "check", "--file", journalPath}, &stdout, &stderr)
+	if !handled || code != 0 {
+		t.Fatalf("journal check = %v, %d; want handled, 0\nstdout:\n%s\nstderr:\n%s", handled, code, stdout.String(), stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "No problems found.") {
+		t.Errorf("Expected no problems with the custom dataset, got:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	if _, code := runCLI([]string{"journal", "repair", "--file", journalPath}, &stdout, &stderr); code != 0 {
+		t.Fatalf("journal repair exited with %d:\n%s", code, stderr.String())
+	}
+	repaired, err := os.ReadFile(journalPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(repaired), `"emotion_id": "gusto"`) || !strings.Contains(string(repaired), `"emotion_name": "Gusto"`) {
+		t.Errorf("Repair changed entries of the custom dataset:\n%s", repaired)
+	}
+}
@@ -2,415 +2,1019 @@
 package main
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"io/fs"
+	"log/slog"
 	"os"
+	"runtime/debug"
+	"strings"
+	"sync"
 	"time" // Make sure time is imported
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
-	"fyne.io/fyne/v2/container" // Import container
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/driver/desktop"
-	"fyne.io/fyne/v2/layout" // Import layout
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/theme"
-	"fyne.io/fyne/v2/widget" // Import widget
 
 	// Use your actual module path here
+	"github.com/itsforsxm123/emotion-explorer/internal/analytics"
 	"github.com/itsforsxm123/emotion-explorer/internal/core"
 	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/itsforsxm123/emotion-explorer/internal/diagnostics"
+	"github.com/itsforsxm123/emotion-explorer/internal/i18n"
+	"github.com/itsforsxm123/emotion-explorer/internal/ipc"
 	"github.com/itsforsxm123/emotion-explorer/internal/journal"
+	"github.com/itsforsxm123/emotion-explorer/internal/logging"
+	"github.com/itsforsxm123/emotion-explorer/internal/paths"
+	"github.com/itsforsxm123/emotion-explorer/internal/settings"
 	"github.com/itsforsxm123/emotion-explorer/internal/ui"
+	"github.com/itsforsxm123/emotion-explorer/internal/watch"
 )
 
 // --- Application State ---
 
-// AppMode defines the current operational mode of the application.
-type AppMode int // Use int for enums, it's more idiomatic Go
+const appName = "Emotion Explorer" // Product name, not translated
 
-const (
-	ModeBrowsing AppMode = iota // Default mode: exploring emotions.
-	ModeLogging                 // Mode for selecting an emotion to log.
-)
-
-const (
-	appName         = "Emotion Explorer"
-	logModeTitle    = appName + " - Logging..."
-	browseModeTitle = appName
-)
+// version is the release version, set at build time with
+// -ldflags "-X main.version=1.2.0"; see appVersion.
+var version = "dev"
 
 var (
 	// Core App Components
 	myApp      fyne.App
 	mainWindow fyne.Window
 
-	// Data
-	emotionData     data.EmotionData // Consider if this needs to be global or passed around
-	primaryEmotions []data.Emotion   // Cache primary emotions
+	// Data, reloaded on the file watcher's and the instance server's
+	// goroutines: use the accessors under Shared State, never the variables
+	emotionData   data.EmotionData       // Consider if this needs to be global or passed around
+	baseData      data.EmotionData       // emotionData before the user's overlay; what the dataset editor edits
+	overlayIssues []data.OverlayConflict // Overlay changes that couldn't be applied as written
+	idResolver    *core.IDResolver       // Maps journal emotion IDs (including legacy ones) to the dataset
+	emotionUsage  core.UsageStats        // How often and how recently each emotion was logged, for the sort modes
+	appSettings   settings.Settings      // User preferences loaded at startup
+
+	// UI: the main window's content (back button and the screen on top) and
+	// its controller (modes, navigation stacks and the actions between them)
+	shell      *ui.Shell
+	controller *ui.AppController
 
-	// UI Elements
-	backButton       *widget.Button
-	mainContentArea  *fyne.Container // The container holding the current view (center of border)
-	mainBorderLayout *fyne.Container
+	// Live reload of files edited outside the app (nil if unavailable)
+	fileWatcher *watch.Watcher
 
-	// State Management
-	currentMode            AppMode              = ModeBrowsing
-	navigationStack        *[]fyne.CanvasObject // Stack for browsing views
-	loggingNavigationStack *[]fyne.CanvasObject // Stack for logging views
+	// Receives requests from later launches of the app (nil if unavailable)
+	instanceServer *ipc.Server
 )
 
+// --- Shared State ---
+
+// stateMutex guards the data and settings above. Live reloads and forwarded
+// launch requests run on their own goroutines while the UI reads the same
+// state, so it is only touched through the helpers below. The lock is never
+// held while calling into the UI.
+var stateMutex sync.RWMutex
+
+// reloadMutex makes dataset reloads one at a time, so a reload from the
+// watcher can't replace a dataset the user just switched to.
+var reloadMutex sync.Mutex
+
+// currentData returns the dataset with the user's overlay applied.
+func currentData() data.EmotionData {
+	stateMutex.RLock()
+	defer stateMutex.RUnlock()
+	return emotionData
+}
+
+// currentBaseData returns the dataset before the user's overlay.
+func currentBaseData() data.EmotionData {
+	stateMutex.RLock()
+	defer stateMutex.RUnlock()
+	return baseData
+}
+
+// currentOverlayIssues returns the overlay changes the dataset didn't accept.
+func currentOverlayIssues() []data.OverlayConflict {
+	stateMutex.RLock()
+	defer stateMutex.RUnlock()
+	return overlayIssues
+}
+
+// currentResolver returns the resolver of the current dataset.
+func currentResolver() *core.IDResolver {
+	stateMutex.RLock()
+	defer stateMutex.RUnlock()
+	return idResolver
+}
+
+// currentUsage returns the usage statistics for the sort modes.
+func currentUsage() core.UsageStats {
+	stateMutex.RLock()
+	defer stateMutex.RUnlock()
+	return emotionUsage
+}
+
+// currentSettings returns a copy of the user's settings.
+func currentSettings() settings.Settings {
+	stateMutex.RLock()
+	defer stateMutex.RUnlock()
+	return appSettings
+}
+
+// updateSettings applies change to the user's settings and returns the
+// result, e.g. to save it.
+func updateSettings(change func(s *settings.Settings)) settings.Settings {
+	stateMutex.Lock()
+	defer stateMutex.Unlock()
+	change(&appSettings)
+	return appSettings
+}
+
 // --- Initialization ---
 
 func main() {
+	// 0. Until logging is set up below, only warnings reach the console, so
+	// subcommands and forwarded launches print just their own output
+	slog.SetDefault(slog.New(logging.NewHandler(os.Stderr, slog.LevelWarn)))
+
+	// 0a. Command line subcommands (e.g. "journal check") run without the GUI
+	if handled, exitCode := runCLI(os.Args[1:], os.Stdout, os.Stderr); handled {
+		os.Exit(exitCode)
+	}
+
+	// 0b. Single instance: forward this launch to a running instance, if any
+	launchRequest, debugLogging, err := parseLaunchRequest(os.Args[1:], os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	socketPath := ipc.SocketPath(paths.DataDir())
+	if err := ipc.Send(socketPath, launchRequest); err == nil {
+		slog.Info("Forwarded launch request to the running instance; exiting", "command", launchRequest.Command)
+		os.Exit(0)
+	} else if !errors.Is(err, ipc.ErrNotRunning) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 0c. Log to the console and a rotating file in the data directory; only
+	// the instance that stays writes to it
+	logFile, err := logging.Setup(logging.Options{Debug: debugLogging, Dir: paths.DataDir(), Stderr: os.Stderr})
+	if err != nil {
+		slog.Warn("Logging to the console only", "err", err)
+	}
+	defer logFile.Close()
+
+	// 0d. Upgrade an older journal once, so the IDs it gets stay stable; a
+	// damaged one is offered for repair below
+	if _, err := journal.Migrate(); err != nil {
+		slog.Warn("Journal not upgraded", "err", err)
+	}
+
 	// 1. Initialize App and Load Data
 	myApp = app.New()
-	mainWindow = myApp.NewWindow(browseModeTitle) // Initial title
+	mainWindow = myApp.NewWindow(appName) // Initial title
 
 	if err := loadData(); err != nil {
 		// Consider showing a dialog even before the main window is fully set up
-		log.Printf("FATAL: Failed to load emotion data: %v\n", err)
+		slog.Error("Failed to load emotion data", "err", err)
 		// dialog.ShowError(err, mainWindow) // This might fail if mainWindow isn't ready
 		fmt.Fprintf(os.Stderr, "Error loading emotion data: %v\n", err) // Fallback to stderr
 		os.Exit(1)
 	}
 
-	// 2. Initialize Navigation Stacks
-	navStack := make([]fyne.CanvasObject, 0, 5) // Pre-allocate some capacity
-	navigationStack = &navStack
-	logNavStack := make([]fyne.CanvasObject, 0, 5)
-	loggingNavigationStack = &logNavStack
-
-	// 3. Setup Core UI Layout
-	setupMainLayout() // Creates the border layout with back button and content area
+	// 2. Setup Core UI Layout: the back button over the screen on top, driven
+	// by the controller
+	shell = ui.NewShell(mainWindow, currentResolver(), ui.ShellOptions{
+		AppName:          appName,
+		Sort:             sortEmotions,
+		OnSaved:          refreshUsage,
+		OnCorruptJournal: func() { checkJournal(true) },
+	})
+	controller = shell.Controller
 
-	// 4. Push Initial View (Browsing Primary Emotions)
-	initialBrowsingView := createEmotionListView("Primary Emotions", nil, primaryEmotions, handleEmotionSelected)
-	pushView(initialBrowsingView, navigationStack) // Push to browsing stack initially
+	// 3. Return to where the user left off
+	restoreSession() // Back where the user left off, or the top-level emotions
 
-	// 5. Setup System Tray & Window Behavior
+	// 4. Setup System Tray, Window Behavior & Live Reload
 	setupSystemTray()
 	setupWindowIntercepts()
+	setupKeyboardShortcuts()
+	setupFileWatchers()
+	startInstanceServer(socketPath)
+
+	// 5. Report overlay changes the dataset no longer accepts, offer to repair
+	// a damaged journal and fix entries whose emotion IDs the dataset no
+	// longer knows
+	reportOverlayIssues()
+	checkJournal(false)
+
+	// 6. Carry out what this launch asked for (e.g. --log)
+	if err := handleInstanceRequest(launchRequest); err != nil {
+		slog.Error("Launch request failed", "command", launchRequest.Command, "err", err)
+		dialog.ShowError(err, mainWindow)
+	}
 
-	// 6. Resize, Center, Show, and Run
+	// 7. Resize, Center, Show, and Run
 	mainWindow.Resize(fyne.NewSize(400, 500)) // Adjusted size
 	mainWindow.CenterOnScreen()
 	mainWindow.ShowAndRun()
 
-	log.Println("Application finished.")
+	saveSession()
+
+	if fileWatcher != nil {
+		fileWatcher.Close()
+	}
+	if instanceServer != nil {
+		instanceServer.Close()
+	}
+	slog.Info("Application finished")
 }
 
-// loadData encapsulates the emotion data loading logic.
+// loadData encapsulates the settings and emotion data loading logic.
 func loadData() error {
-	log.Println("Loading emotion data...")
+	slog.Debug("Loading settings")
+	loaded, err := settings.Load()
+	if err != nil {
+		// Bad settings shouldn't stop the app; fall back to defaults
+		slog.Warn("Failed to load settings, using defaults", "err", err)
+		loaded = settings.Settings{}
+	}
+	updateSettings(func(s *settings.Settings) { *s = loaded })
+	applyLocale()
+
+	if err := loadDataset(loaded.DatasetPath); err != nil {
+		if loaded.DatasetPath == "" {
+			return err
+		}
+		// A broken custom dataset shouldn't stop the app either; it is
+		// still watched, so fixing the file loads it
+		slog.Warn("Failed to load custom dataset, using the built-in one", "err", err)
+		if err := loadDataset(""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadDataset loads the dataset at path (the built-in one if path is empty)
+// and replaces everything derived from it. A custom file that fails
+// data.Validate (e.g. dangling parents or a parent cycle) is refused, since
+// the views assume a well-formed hierarchy. On error the current data is kept.
+func loadDataset(path string) error {
+	slog.Debug("Loading emotion data")
+	var loaded data.EmotionData
 	var err error
-	emotionData, err = data.LoadEmotions()
+	if path != "" {
+		slog.Info("Using custom dataset", "path", path)
+		loaded, err = data.LoadEmotionsFile(path)
+		if err == nil {
+			err = validateDataset(path, loaded)
+		}
+	} else {
+		loaded, err = data.LoadEmotions()
+	}
 	if err != nil {
 		return fmt.Errorf("failed to load emotions: %w", err)
 	}
-	log.Printf("Successfully loaded emotion data. Version: %s", emotionData.Metadata.Version)
-	log.Printf("Found %d total emotions defined.", len(emotionData.Emotions))
+	merged, issues := applyUserOverlay(loaded)
+	slog.Info("Loaded emotion data", "version", merged.Metadata.Version, "emotions", len(merged.Emotions))
+	resolver := core.NewIDResolver(merged)
+	stateMutex.Lock()
+	baseData, emotionData, overlayIssues, idResolver = loaded, merged, issues, resolver
+	colorblind := appSettings.ColorblindPalette
+	stateMutex.Unlock()
+	if shell != nil {
+		shell.SetResolver(resolver) // Callers refresh the views
+	}
+	slog.Debug("Dataset declares ID aliases", "aliases", len(merged.Aliases))
+	ui.ConfigureColors(merged.Emotions, colorblind)
 
-	log.Println("Extracting primary emotions...")
-	primaryEmotions = core.GetPrimaryEmotions(emotionData.Emotions) // Use loaded data
-	log.Printf("Found %d primary emotions.", len(primaryEmotions))
-	if len(primaryEmotions) == 0 {
-		log.Println("Warning: No primary emotions found. Check emotions.json.")
+	slog.Debug("Extracting top-level emotions")
+	rootEmotions := core.GetRootEmotions(merged.Emotions) // Use loaded data
+	slog.Debug("Found top-level emotions", "count", len(rootEmotions), "levels", core.LevelCount(merged.Emotions))
+	if len(rootEmotions) == 0 {
+		slog.Warn("No top-level emotions found; check the dataset")
 	}
+	refreshUsage() // Journal IDs are resolved against the new dataset
 	return nil
 }
 
-// setupMainLayout creates the main window structure (border layout).
-func setupMainLayout() {
-	backButton = widget.NewButtonWithIcon("", theme.NavigateBackIcon(), handleBack) // Use icon
-	backButton.Disable()                                                            // Start disabled
+// validateDataset returns an error if a custom dataset has problems; every
+// problem is logged, and "dataset validate" lists them too.
+func validateDataset(path string, emotionData data.EmotionData) error {
+	problems := data.Validate(emotionData)
+	if len(problems) == 0 {
+		return nil
+	}
+	for _, problem := range problems {
+		slog.Warn("Dataset problem", "path", path, "problem", problem.Error())
+	}
+	return fmt.Errorf("dataset '%s' has %d problem(s), e.g. %w", path, len(problems), problems[0])
+}
+
+// overlayPath returns where the user's personal overlay file lives.
+func overlayPath() string {
+	return paths.File(data.OverlayFilename)
+}
+
+// applyUserOverlay layers the user's overlay file (if any) on top of a freshly
+// loaded dataset. An unreadable overlay is reported as a conflict and the
+// dataset is used as-is, so a typo never stops the app.
+func applyUserOverlay(base data.EmotionData) (data.EmotionData, []data.OverlayConflict) {
+	overlay, err := data.LoadOverlayFile(overlayPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return base, nil // No overlay; the usual case
+	}
+	if err != nil {
+		slog.Warn("Ignoring overlay", "err", err)
+		return base, []data.OverlayConflict{{Message: err.Error()}}
+	}
+	merged, conflicts := data.ApplyOverlay(base, overlay)
+	slog.Info("Applied overlay", "path", overlayPath(), "additions", len(overlay.Add), "overrides", len(overlay.Override),
+		"hides", len(overlay.Hide), "conflicts", len(conflicts))
+	for _, conflict := range conflicts {
+		slog.Warn("Overlay conflict", "conflict", conflict.Message)
+	}
+	return merged, conflicts
+}
 
-	// This container will hold the dynamic content (emotion lists)
-	mainContentArea = container.NewMax() // Use Max layout to fill available space
+// maxListedOverlayIssues caps how many conflicts the overlay dialog lists;
+// the log always has all of them.
+const maxListedOverlayIssues = 8
 
-	// Create the main border layout
-	border := container.NewBorder(
-		container.NewHBox(backButton, layout.NewSpacer()), // Top: Back button aligned left
-		nil,             // Bottom
-		nil,             // Left
-		nil,             // Right
-		mainContentArea, // Center: Dynamic content goes here
-	)
-	mainBorderLayout = border // Store reference if needed, though direct access via mainWindow.Content() works
-	mainWindow.SetContent(border)
-	log.Println("Main layout setup complete.")
+// reportOverlayIssues tells the user about overlay changes that could not be
+// applied as written, e.g. after a dataset upgrade removed a word they had
+// customized. Does nothing if there are none.
+func reportOverlayIssues() {
+	issues := currentOverlayIssues()
+	if len(issues) == 0 {
+		return
+	}
+	lines := []string{i18n.T("overlay.conflicts", len(issues))}
+	for i, conflict := range issues {
+		if i == maxListedOverlayIssues {
+			lines = append(lines, i18n.T("overlay.more", len(issues)-i))
+			break
+		}
+		lines = append(lines, "• "+conflict.Error())
+	}
+	lines = append(lines, "", i18n.T("overlay.where", overlayPath()))
+	mainWindow.Show()
+	dialog.ShowInformation(i18n.T("overlay.title"), strings.Join(lines, "\n"), mainWindow)
 }
 
-// --- Navigation Stack Management ---
+// --- Session ---
 
-// pushView adds a new view to the specified navigation stack and updates the UI.
-func pushView(view fyne.CanvasObject, stack *[]fyne.CanvasObject) {
-	*stack = append(*stack, view)
-	log.Printf("Pushed view. Stack size: %d. Mode: %v", len(*stack), currentMode)
-	updateContentFromActiveStack() // Update content based on the active stack
-	updateBackButtonState()        // Update button state after push
+// restoreSession opens the route the user was on when the app last exited.
+// A route the dataset can no longer show (or none) starts at the top-level
+// emotions.
+func restoreSession() {
+	if lastRoute := currentSettings().LastRoute; lastRoute != "" {
+		route, err := ui.ParseRoute(lastRoute)
+		if err == nil {
+			route = route.Restorable() // Also covers routes saved by older builds
+			err = controller.Open(route)
+		}
+		if err == nil {
+			slog.Debug("Restored session", "route", route.String())
+			return
+		}
+		slog.Warn("Not restoring last session", "err", err)
+	}
+	controller.Refresh()
 }
 
-// popView removes the top view from the specified navigation stack and updates the UI.
-// Returns true if a pop occurred, false if the stack was empty or had only one item.
-func popView(stack *[]fyne.CanvasObject) bool {
-	if len(*stack) <= 1 {
-		log.Printf("Pop requested on stack with size %d. Cannot pop.", len(*stack))
-		return false // Cannot pop the last view
+// saveSession remembers the current route for restoreSession. A logging
+// session is remembered as the place it was browsing.
+func saveSession() {
+	route := controller.Route().Restorable().String()
+	if err := settings.Save(updateSettings(func(s *settings.Settings) { s.LastRoute = route })); err != nil {
+		slog.Error("Failed to save session", "err", err)
+		return
 	}
-	*stack = (*stack)[:len(*stack)-1] // Pop the last element
-	log.Printf("Popped view. Stack size: %d. Mode: %v", len(*stack), currentMode)
-	updateContentFromActiveStack() // Update content based on the active stack
-	updateBackButtonState()        // Update button state after pop
-	return true
+	slog.Debug("Saved session", "route", route)
 }
 
-// --- UI Update Logic ---
+// --- Rendering ---
 
-// updateContentFromActiveStack sets the main content area based on the top of the active stack.
-func updateContentFromActiveStack() {
-	var activeStack *[]fyne.CanvasObject
-	if currentMode == ModeLogging {
-		activeStack = loggingNavigationStack
-	} else {
-		activeStack = navigationStack
+// rerenderIf re-renders the visible screen if match selects it; covered
+// screens are rendered from the current data when revealed anyway.
+func rerenderIf(match func(ui.Screen) bool) {
+	if match(controller.State().Screen) {
+		controller.Refresh()
 	}
+}
+
+// listsEmotions reports whether a screen lists emotions in the user's sort
+// mode, so it is re-rendered when the mode changes.
+func listsEmotions(screen ui.Screen) bool {
+	return screen.Kind == ui.ScreenRoot || screen.Kind == ui.ScreenEmotion
+}
 
-	if len(*activeStack) == 0 {
-		log.Println("Error: Active stack is empty, cannot update content.")
-		// Show an error message or a placeholder in the UI?
-		mainContentArea.Objects = []fyne.CanvasObject{widget.NewLabel("Error: No view available.")}
-		mainContentArea.Refresh()
+// showSearchView opens the emotion search on the active stack.
+func showSearchView() {
+	slog.Debug("Opening search view")
+	controller.ShowSearch()
+	mainWindow.Show()
+	mainWindow.RequestFocus()
+}
+
+// showMoodMeter starts logging on the mood meter: the user taps a point on
+// the valence/arousal plane and picks one of the closest words. Back leads
+// to the word list of the same logging session.
+func showMoodMeter() {
+	slog.Debug("Opening mood meter")
+	controller.ShowMoodMeter()
+	mainWindow.Show()
+	mainWindow.RequestFocus()
+}
+
+// showHistoryView opens the journal history on the browsing stack, if the
+// journal can be read. An in-progress logging session is cancelled first.
+func showHistoryView() {
+	slog.Debug("Opening journal history view")
+	if _, err := journal.GetJournalEntries(); err != nil {
+		slog.Error("Failed to load journal entries for history", "err", err)
+		dialog.ShowError(fmt.Errorf("%s: %w", i18n.T("error.loadJournal"), err), mainWindow)
 		return
 	}
+	controller.ShowHistory()
+	mainWindow.Show()
+	mainWindow.RequestFocus()
+}
 
-	// Get the top view from the active stack
-	topView := (*activeStack)[len(*activeStack)-1]
+// showCompareView opens an empty emotion comparison on the browsing stack;
+// the user picks the emotions there.
+func showCompareView() {
+	slog.Debug("Opening compare view")
+	controller.ShowCompare()
+	mainWindow.Show()
+	mainWindow.RequestFocus()
+}
 
-	// Update the main content area
-	mainContentArea.Objects = []fyne.CanvasObject{topView} // Replace objects in Max container
-	mainContentArea.Refresh()
-	log.Println("Main content area updated.")
+// showLearnView opens the vocabulary quiz on the whole dataset.
+func showLearnView() {
+	slog.Debug("Opening learning view")
+	controller.ShowLearn()
+	mainWindow.Show()
+	mainWindow.RequestFocus()
 }
 
-// updateBackButtonState enables/disables the back button based on the active stack size.
-func updateBackButtonState() {
-	var activeStack *[]fyne.CanvasObject
-	if currentMode == ModeLogging {
-		activeStack = loggingNavigationStack
-	} else {
-		activeStack = navigationStack
+// checkJournal runs the journal integrity check and, if the file is damaged or
+// has fixable problems, offers to repair it (keeping a backup of the
+// original). The emotion ID check follows once the journal is readable.
+// When interactive is true the user asked for the check, so clean results
+// are reported too.
+func checkJournal(interactive bool) {
+	lookup := journalLookup(currentResolver())
+	report, err := journal.Check(lookup, time.Now())
+	if err != nil {
+		slog.Error("Failed to check journal", "err", err)
+		if interactive {
+			dialog.ShowError(fmt.Errorf("%s: %w", i18n.T("error.loadJournal"), err), mainWindow)
+		}
+		return
+	}
+	if !report.NeedsRepair() {
+		checkJournalIDs(interactive)
+		return
 	}
 
-	if len(*activeStack) <= 1 {
-		backButton.Disable()
-		log.Println("Back button disabled.")
-	} else {
-		backButton.Enable()
-		log.Println("Back button enabled.")
+	slog.Warn("Journal needs repair", "entries", len(report.Entries), "issues", len(report.Issues), "wellFormed", report.WellFormed)
+	message := i18n.T("repair.damaged", len(report.Entries))
+	if report.WellFormed {
+		message = i18n.T("repair.fixable", len(report.Issues))
 	}
+	mainWindow.Show()
+	dialog.ShowConfirm(i18n.T("repair.title"), message+"\n"+i18n.T("repair.backup"), func(confirmed bool) {
+		if !confirmed {
+			slog.Info("Journal repair postponed by user")
+			return
+		}
+		result, err := journal.Repair(lookup, time.Now())
+		if err != nil {
+			slog.Error("Failed to repair journal", "err", err)
+			dialog.ShowError(fmt.Errorf("%s: %w", i18n.T("error.repairJournal"), err), mainWindow)
+			return
+		}
+		done := dialog.NewInformation(i18n.T("repair.title"), i18n.T("repair.done", result.Written, result.BackupPath), mainWindow)
+		done.SetOnClosed(func() { checkJournalIDs(false) }) // Unknown IDs still need the user's choice
+		done.Show()
+	}, mainWindow)
 }
 
-// --- Event Handlers ---
+// checkJournalIDs looks for journal entries whose emotion IDs cannot be
+// resolved against the dataset (even through aliases) and, if there are any,
+// walks the user through remapping them. When interactive is true the user
+// asked for the check, so a clean result is reported too.
+func checkJournalIDs(interactive bool) {
+	entries, err := journal.GetJournalEntries()
+	if err != nil {
+		slog.Error("Failed to load journal entries for ID check", "err", err)
+		if interactive {
+			dialog.ShowError(fmt.Errorf("%s: %w", i18n.T("error.loadJournal"), err), mainWindow)
+		}
+		return
+	}
 
-// handleBack manages the back navigation logic for both modes.
-func handleBack() {
-	log.Println("Back button clicked.")
-	if currentMode == ModeLogging {
-		if !popView(loggingNavigationStack) {
-			// If pop failed (we are at the root of logging), treat as cancel
-			log.Println("Back clicked at root of logging stack. Cancelling logging.")
-			switchToBrowsingMode() // Or could just stay here, depends on desired UX
+	resolver := currentResolver()
+	unresolved := journal.FindUnresolvedIDs(entries, resolver.Resolve)
+	slog.Info("Checked journal emotion IDs", "unresolvedIDs", len(unresolved), "entries", len(entries))
+	if len(unresolved) == 0 {
+		if interactive {
+			dialog.ShowInformation(i18n.T("remap.title"), i18n.T("remap.allResolved"), mainWindow)
 		}
-	} else {
-		popView(navigationStack) // Pop the browsing stack
+		return
 	}
+
+	mainWindow.Show()
+	ui.ShowRemapDialog(unresolved, resolver.Emotions(), applyJournalRemap, mainWindow)
 }
 
-// handleEmotionSelected is the central callback for emotion selection in ANY mode.
-// It delegates to mode-specific handlers.
-func handleEmotionSelected(selectedEmotion data.Emotion) {
-	log.Printf("Emotion selected: '%s' (ID: %s) in Mode: %v", selectedEmotion.Name, selectedEmotion.ID, currentMode)
-	if currentMode == ModeLogging {
-		handleLogEmotionSelection(selectedEmotion)
-	} else {
-		handleBrowseEmotionSelection(selectedEmotion)
+// applyJournalRemap rewrites journal entries according to the user's choices
+// in the remap dialog.
+func applyJournalRemap(mapping map[string]string) {
+	emotions := currentData().Emotions
+	names := make(map[string]string, len(mapping))
+	for _, newID := range mapping {
+		names[newID] = emotions[newID].Name // Default-language name, like new entries
 	}
+	changed, err := journal.RemapEmotionIDs(mapping, names)
+	if err != nil {
+		slog.Error("Failed to remap journal entries", "err", err)
+		dialog.ShowError(fmt.Errorf("%s: %w", i18n.T("error.remapJournal"), err), mainWindow)
+		return
+	}
+	dialog.ShowInformation(i18n.T("remap.title"), i18n.T("remap.done", changed), mainWindow)
 }
 
-// handleBrowseEmotionSelection handles navigation when an emotion is selected in browsing mode.
-func handleBrowseEmotionSelection(selectedEmotion data.Emotion) {
-	children := core.GetChildrenOf(selectedEmotion.ID, emotionData.Emotions)
-	log.Printf("[Browse] Found %d children for '%s'.", len(children), selectedEmotion.Name)
+// toggleColorblindPalette switches between dataset colors and the
+// colorblind-safe palette, persists the choice and redraws the views.
+func toggleColorblindPalette() {
+	saved := updateSettings(func(s *settings.Settings) { s.ColorblindPalette = !s.ColorblindPalette })
+	slog.Info("Colorblind-safe palette toggled", "enabled", saved.ColorblindPalette)
+	if err := settings.Save(saved); err != nil {
+		slog.Error("Failed to save settings", "err", err)
+		dialog.ShowError(fmt.Errorf("%s: %w", i18n.T("error.saveSettings"), err), mainWindow)
+	}
+	ui.ConfigureColors(currentData().Emotions, saved.ColorblindPalette)
+	controller.Refresh() // The visible view was drawn with the old colors
+}
 
-	if len(children) > 0 {
-		title := fmt.Sprintf("Exploring: %s", selectedEmotion.Name)
-		// Create and push the new view onto the browsing stack
-		childView := createEmotionListView(title, &selectedEmotion, children, handleEmotionSelected) // Use central handler
-		pushView(childView, navigationStack)
-	} else {
-		// Leaf node in browsing mode - maybe show details in the future
-		log.Printf("[Browse] Leaf Node: '%s'. (Detail view TBD)", selectedEmotion.Name)
-		dialog.ShowInformation("Emotion Details", fmt.Sprintf("Selected: %s\n(More details could be shown here)", selectedEmotion.Name), mainWindow)
+// --- Sort Modes ---
+
+// sortModeKeys maps each sort mode to the translation key of its menu label.
+var sortModeKeys = map[core.SortMode]string{
+	core.SortDataset:      "sort.dataset",
+	core.SortAlphabetical: "sort.alphabetical",
+	core.SortMostUsed:     "sort.mostUsed",
+	core.SortRecent:       "sort.recent",
+	core.SortIntensity:    "sort.intensity",
+}
+
+// currentSortMode returns the sort mode chosen in settings, or dataset
+// order if none (or an unknown one) was chosen.
+func currentSortMode() core.SortMode {
+	if mode := core.SortMode(currentSettings().SortMode); mode.Valid() {
+		return mode
 	}
+	return core.SortDataset
 }
 
-// handleLogEmotionSelection handles navigation or saving when an emotion is selected in logging mode.
-func handleLogEmotionSelection(selectedEmotion data.Emotion) {
-	children := core.GetChildrenOf(selectedEmotion.ID, emotionData.Emotions)
-	log.Printf("[Log] Found %d children for '%s'.", len(children), selectedEmotion.Name)
+// usageSorted reports whether the current sort mode depends on the journal.
+func usageSorted() bool {
+	mode := currentSortMode()
+	return mode == core.SortMostUsed || mode == core.SortRecent
+}
 
-	if len(children) > 0 {
-		// Navigate deeper within logging mode
-		title := fmt.Sprintf("Log > %s > ...", selectedEmotion.Name)                                 // Shorter title
-		childView := createEmotionListView(title, &selectedEmotion, children, handleEmotionSelected) // Use central handler
-		pushView(childView, loggingNavigationStack)
-	} else {
-		// Leaf node selected in logging mode - Log it!
-		log.Printf("[Log] Leaf Node: '%s'. Attempting to save.", selectedEmotion.Name)
-		saveLoggedEmotion(selectedEmotion) // Encapsulate saving logic
-		switchToBrowsingMode()             // Return to browsing after attempting save
+// sortEmotions orders a list of emotions for display in the current mode.
+func sortEmotions(emotions []data.Emotion) []data.Emotion {
+	return core.SortEmotions(emotions, currentSortMode(), currentUsage())
+}
+
+// refreshUsage recounts the journal for the usage-based sort modes. If the
+// journal can't be read the previous counts are kept; the error is reported
+// wherever the journal is actually shown.
+func refreshUsage() {
+	entries, err := journal.GetJournalEntries()
+	if err != nil {
+		slog.Warn("Failed to read journal for usage statistics", "err", err)
+		return
 	}
+	usage := analytics.Usage(entries, currentResolver())
+	stateMutex.Lock()
+	emotionUsage = usage
+	stateMutex.Unlock()
 }
 
-// saveLoggedEmotion handles the process of saving a selected emotion to the journal.
-func saveLoggedEmotion(emotionToLog data.Emotion) {
-	entry := journal.LogEntry{
-		Timestamp:   time.Now(),
-		EmotionID:   emotionToLog.ID,
-		EmotionName: emotionToLog.Name,
-		Notes:       "", // Notes field exists but is empty for now
+// changeSortMode persists a new sort mode and re-sorts the emotion lists.
+func changeSortMode(mode core.SortMode) {
+	slog.Info("Sort mode changed", "mode", mode)
+	if err := settings.Save(updateSettings(func(s *settings.Settings) { s.SortMode = string(mode) })); err != nil {
+		slog.Error("Failed to save settings", "err", err)
+		dialog.ShowError(fmt.Errorf("%s: %w", i18n.T("error.saveSettings"), err), mainWindow)
 	}
+	setupSystemTray() // Update the checked sort item
+	rerenderIf(listsEmotions)
+}
 
-	err := journal.SaveLogEntry(entry)
-	if err != nil {
-		log.Printf("ERROR: Failed to save log entry for '%s': %v", emotionToLog.Name, err)
-		dialog.ShowError(fmt.Errorf("failed to save journal entry: %w", err), mainWindow)
-	} else {
-		log.Printf("[Log] Entry for '%s' saved successfully.", emotionToLog.Name)
-		dialog.ShowInformation("Logged", fmt.Sprintf("Successfully logged: %s", emotionToLog.Name), mainWindow)
+// newSortMenuItem builds the "Sort Emotions" submenu with one checkable item
+// per sort mode.
+func newSortMenuItem() *fyne.MenuItem {
+	items := make([]*fyne.MenuItem, 0, len(core.SortModes))
+	for _, mode := range core.SortModes {
+		mode := mode // Capture loop variable
+		item := fyne.NewMenuItem(i18n.T(sortModeKeys[mode]), func() { changeSortMode(mode) })
+		item.Checked = currentSortMode() == mode
+		items = append(items, item)
 	}
+	sortItem := fyne.NewMenuItem(i18n.T("tray.sort"), nil)
+	sortItem.ChildMenu = fyne.NewMenu("", items...)
+	return sortItem
 }
 
-// --- Mode Switching Logic ---
+// --- Language Selection ---
 
-// switchToLoggingMode prepares the UI for emotion logging.
-func switchToLoggingMode() {
-	if currentMode == ModeLogging {
-		log.Println("Already in logging mode.")
-		return // Avoid redundant setup
+// applyLocale activates the language chosen in settings, or the system
+// language if none was chosen.
+func applyLocale() {
+	locale := currentSettings().Locale
+	if locale == "" {
+		locale = lang.SystemLocale().LanguageString()
+		slog.Debug("Using system locale", "locale", locale)
 	}
-	log.Println("Switching to Logging Mode...")
-	currentMode = ModeLogging
+	i18n.SetLocale(locale)
+}
 
-	// Clear the previous logging stack to start fresh
-	logNavStack := make([]fyne.CanvasObject, 0, 5)
-	loggingNavigationStack = &logNavStack
+// changeLanguage persists a new language choice ("" for the system default)
+// and redraws everything that shows translated text.
+func changeLanguage(locale string) {
+	slog.Info("Language changed", "locale", locale)
+	if err := settings.Save(updateSettings(func(s *settings.Settings) { s.Locale = locale })); err != nil {
+		slog.Error("Failed to save settings", "err", err)
+		dialog.ShowError(fmt.Errorf("%s: %w", i18n.T("error.saveSettings"), err), mainWindow)
+	}
+	applyLocale()
+	setupSystemTray()    // Rebuild the tray menu with the new labels
+	controller.Refresh() // Also translates the window title
+}
+
+// newLanguageMenuItem builds the "Language" submenu with one checkable item
+// per available catalog plus "System Default".
+func newLanguageMenuItem() *fyne.MenuItem {
+	chosen := currentSettings().Locale
+	systemItem := fyne.NewMenuItem(i18n.T("language.systemDefault"), func() { changeLanguage("") })
+	systemItem.Checked = chosen == ""
+	items := []*fyne.MenuItem{systemItem, fyne.NewMenuItemSeparator()}
+	for _, locale := range i18n.Available() {
+		locale := locale // Capture loop variable
+		item := fyne.NewMenuItem(i18n.LanguageName(locale), func() { changeLanguage(locale) })
+		item.Checked = chosen == locale
+		items = append(items, item)
+	}
+	languageItem := fyne.NewMenuItem(i18n.T("tray.language"), nil)
+	languageItem.ChildMenu = fyne.NewMenu("", items...)
+	return languageItem
+}
 
-	// Create and push the initial logging view (primary emotions)
-	initialLogView := createEmotionListView("Select Feeling to Log", nil, primaryEmotions, handleEmotionSelected)
-	pushView(initialLogView, loggingNavigationStack) // Push to the now active logging stack
+// --- Mode Switching Logic ---
 
-	mainWindow.SetTitle(logModeTitle) // Update window title
-	// updateContentFromActiveStack() is called by pushView
-	// updateBackButtonState() is called by pushView
+// switchToLoggingMode starts logging (unless already logging) and brings
+// the window to the front.
+func switchToLoggingMode() {
+	controller.StartLogging()
 	mainWindow.Show()         // Ensure window is visible
 	mainWindow.RequestFocus() // Bring to front
 }
 
-// switchToBrowsingMode returns the UI to the standard emotion browsing state.
-func switchToBrowsingMode() {
-	if currentMode == ModeBrowsing {
-		log.Println("Already in browsing mode.")
+// --- Live Reload ---
+
+// setupFileWatchers starts watching the journal and the custom dataset (if
+// any) so edits made outside the app, e.g. entries logged from the CLI,
+// show up without a restart.
+func setupFileWatchers() {
+	w, err := watch.New(watch.DefaultDelay)
+	if err != nil {
+		slog.Warn("Live reload disabled", "err", err)
+		return
+	}
+	fileWatcher = w
+	if err := fileWatcher.Watch(journal.FilePath(), handleJournalChanged); err != nil {
+		slog.Warn("Not watching the journal", "err", err)
+	}
+	watchDataset(currentSettings().DatasetPath)
+	if err := fileWatcher.Watch(overlayPath(), handleDatasetChanged); err != nil {
+		slog.Warn("Not watching the overlay", "err", err)
+	}
+}
+
+// watchDataset watches a custom dataset file. Empty paths (the built-in
+// dataset) are ignored.
+func watchDataset(path string) {
+	if fileWatcher == nil || path == "" {
+		return
+	}
+	if err := fileWatcher.Watch(path, handleDatasetChanged); err != nil {
+		slog.Warn("Not watching the dataset", "path", path, "err", err)
+	}
+}
+
+// handleJournalChanged re-renders the views that show journal entries, and
+// the emotion lists if they are sorted by usage.
+// Like handleDatasetChanged it runs on the watcher's goroutine: the app's
+// state is read through the stateMutex accessors, and the shell renders one
+// change at a time whichever goroutine it comes from.
+func handleJournalChanged() {
+	refreshUsage()
+	byUsage := usageSorted()
+	rerenderIf(func(screen ui.Screen) bool {
+		return screen.Kind == ui.ScreenHistory || (listsEmotions(screen) && byUsage)
+	})
+}
+
+// handleDatasetChanged reloads the custom dataset (or the overlay on top of
+// any dataset) and rebuilds every view, keeping the user's place where the
+// emotions still exist. A dataset that doesn't load (often an edit in
+// progress) is ignored until it is fixed.
+func handleDatasetChanged() {
+	reloadMutex.Lock()
+	err := loadDataset(currentSettings().DatasetPath)
+	reloadMutex.Unlock()
+	if err != nil {
+		slog.Warn("Ignoring dataset change", "err", err)
+		return
+	}
+	controller.Refresh()
+	reportOverlayIssues()
+}
+
+// --- Dataset Selection ---
+
+// changeDataset switches to the dataset file at path ("" for the built-in
+// dataset), persists the choice and rebuilds the views.
+func changeDataset(path string) {
+	slog.Info("Dataset changed", "path", path)
+	reloadMutex.Lock()
+	if err := loadDataset(path); err != nil {
+		reloadMutex.Unlock()
+		slog.Error("Failed to load dataset", "err", err)
+		dialog.ShowError(fmt.Errorf("%s: %w", i18n.T("error.loadDataset"), err), mainWindow)
+		return
+	}
+	previous := currentSettings().DatasetPath
+	saved := updateSettings(func(s *settings.Settings) { s.DatasetPath = path })
+	reloadMutex.Unlock()
+	if fileWatcher != nil && previous != "" {
+		fileWatcher.Unwatch(previous)
+	}
+	if err := settings.Save(saved); err != nil {
+		slog.Error("Failed to save settings", "err", err)
+		dialog.ShowError(fmt.Errorf("%s: %w", i18n.T("error.saveSettings"), err), mainWindow)
+	}
+	watchDataset(path)
+	setupSystemTray() // Update the checked dataset item
+	controller.Refresh()
+	reportOverlayIssues()
+	checkJournal(false) // The new dataset may not know every logged emotion
+}
+
+// showOpenDatasetDialog lets the user pick a custom dataset file.
+func showOpenDatasetDialog() {
+	mainWindow.Show()
+	open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			slog.Error("Open dataset dialog failed", "err", err)
+			return
+		}
+		if reader == nil {
+			return // Cancelled
+		}
+		path := reader.URI().Path()
+		reader.Close()
+		changeDataset(path)
+	}, mainWindow)
+	open.SetFilter(storage.NewExtensionFileFilter(data.FileExtensions))
+	open.Show()
+}
+
+// showDatasetEditor opens the dataset editor on the current dataset, without
+// the user's overlay (which stays a separate file). Saving switches the app to
+// the saved file.
+func showDatasetEditor() {
+	resolver := currentResolver()
+	usage := make(map[string]int)
+	if entries, err := journal.GetJournalEntries(); err != nil {
+		slog.Warn("Dataset editor opened without journal usage counts", "err", err)
+	} else {
+		for _, entry := range entries {
+			if id, ok := resolver.Resolve(entry.EmotionID); ok {
+				usage[id]++
+			}
+		}
+	}
+	ui.ShowDatasetEditor(myApp, currentBaseData(), currentSettings().DatasetPath, usage, changeDataset)
+}
+
+// newDatasetMenuItem builds the "Dataset" submenu.
+func newDatasetMenuItem() *fyne.MenuItem {
+	datasetPath := currentSettings().DatasetPath
+	builtinItem := fyne.NewMenuItem(i18n.T("tray.datasetBuiltin"), func() { changeDataset("") })
+	builtinItem.Checked = datasetPath == ""
+	openItem := fyne.NewMenuItem(i18n.T("tray.datasetOpen"), showOpenDatasetDialog)
+	openItem.Checked = datasetPath != ""
+	datasetItem := fyne.NewMenuItem(i18n.T("tray.dataset"), nil)
+	editItem := fyne.NewMenuItem(i18n.T("tray.editDataset"), showDatasetEditor)
+	datasetItem.ChildMenu = fyne.NewMenu("", builtinItem, openItem, fyne.NewMenuItemSeparator(), editItem)
+	return datasetItem
+}
+
+// --- Diagnostics ---
+
+// appVersion returns the release version, or the module version recorded by
+// "go install" for builds without one.
+func appVersion() string {
+	if version != "dev" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return version
+}
+
+// showDiagnosticsDialog asks where to save a diagnostics bundle for a bug
+// report and writes it there.
+func showDiagnosticsDialog() {
+	mainWindow.Show()
+	now := time.Now()
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			slog.Error("Save diagnostics dialog failed", "err", err)
+			return
+		}
+		if writer == nil {
+			return // Cancelled
+		}
+		userSettings := currentSettings()
+		sources := diagnosticsSources(currentData(), userSettings.DatasetPath, currentOverlayIssues(), userSettings, fmt.Sprintf("%T", myApp.Driver()))
+		err = diagnostics.Write(writer, diagnostics.Collect(sources, now))
+		if closeErr := writer.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			slog.Error("Failed to write diagnostics bundle", "err", err)
+			dialog.ShowError(fmt.Errorf("%s: %w", i18n.T("error.diagnostics"), err), mainWindow)
+			return
+		}
+		dialog.ShowInformation(i18n.T("diagnostics.title"), i18n.T("diagnostics.saved", writer.URI().Path()), mainWindow)
+	}, mainWindow)
+	save.SetFileName(diagnostics.FileName(now))
+	save.SetFilter(storage.NewExtensionFileFilter([]string{".zip"}))
+	save.Show()
+}
+
+// --- Single Instance ---
+
+// startInstanceServer listens for requests from later launches of the app,
+// so they can hand over to this instance instead of opening a second window.
+func startInstanceServer(socketPath string) {
+	server, err := ipc.Listen(socketPath, handleInstanceRequest)
+	if err != nil {
+		// Rare race with an instance started at the same moment, or no socket support
+		slog.Warn("Not accepting requests from other launches", "err", err)
 		return
 	}
-	log.Println("Switching to Browsing Mode...")
-	currentMode = ModeBrowsing
-
-	// Clear the logging stack (optional, good for memory if logging stack could get deep)
-	// logNavStack := make([]fyne.CanvasObject, 0, 5)
-	// loggingNavigationStack = &logNavStack
-
-	mainWindow.SetTitle(browseModeTitle) // Reset window title
-	updateContentFromActiveStack()       // Display the top of the browsing stack
-	updateBackButtonState()              // Update button based on browsing stack
-	log.Println("Switched back to Browsing Mode.")
-}
-
-// --- View Creation Helper ---
-
-// createEmotionListView wraps the call to the UI package's function.
-// It now only needs the selection callback, as back is handled globally.
-// NOTE: This assumes ui.CreateEmotionListView can be called without back button params.
-// If ui.CreateEmotionListView *requires* back params, we need to adjust it or this wrapper.
-// For now, let's assume the old ui.CreateEmotionListView is still used, taking nil/"" for back.
-func createEmotionListView(
-	title string,
-	parent *data.Emotion, // Optional parent context
-	emotions []data.Emotion,
-	onSelect func(data.Emotion),
-) fyne.CanvasObject {
-	log.Printf("Creating view wrapper: '%s' with %d emotions.", title, len(emotions))
-	// --- UPDATED CALL: Removed the nil and "" arguments ---
-	return ui.CreateEmotionListView(
-		title,
-		parent,
-		emotions,
-		onSelect, // Pass the central selection handler
-	)
+	instanceServer = server
+}
+
+// handleInstanceRequest carries out a launch request, either this launch's
+// own or one forwarded by a later launch (on the server's goroutine, so
+// like the live reload handlers it only reads state through the accessors).
+func handleInstanceRequest(req ipc.Request) error {
+	slog.Info("Handling launch request", "command", req.Command)
+	switch req.Command {
+	case ipc.CommandShow:
+		mainWindow.Show()
+		mainWindow.RequestFocus()
+	case ipc.CommandLog:
+		switchToLoggingMode()
+	case ipc.CommandHistory:
+		showHistoryView()
+	case ipc.CommandOpen:
+		route, err := ui.ParseRoute(req.Route)
+		if err != nil {
+			return err
+		}
+		if err := controller.Open(route); err != nil {
+			return err
+		}
+		mainWindow.Show()
+		mainWindow.RequestFocus()
+	case ipc.CommandLogEmotion:
+		emotion, ok := currentResolver().Lookup(req.EmotionID)
+		if !ok {
+			return fmt.Errorf("unknown emotion ID '%s'", req.EmotionID)
+		}
+		mainWindow.Show()
+		return shell.Save(controller.NewLogEntry(emotion, nil), emotion)
+	default:
+		return fmt.Errorf("unsupported command '%s'", req.Command)
+	}
+	return nil
 }
 
 // --- System Tray & Window Intercepts ---
 
 func setupSystemTray() {
 	if desk, ok := myApp.(desktop.App); ok {
-		log.Println("System tray supported. Setting up...")
+		slog.Debug("System tray supported; setting up")
+		colorblindItem := fyne.NewMenuItem(i18n.T("tray.colorblind"), nil)
+		colorblindItem.Checked = currentSettings().ColorblindPalette
 		m := fyne.NewMenu(appName,
-			fyne.NewMenuItem("Show Window", func() {
-				log.Println("Tray: Show Window clicked.")
+			fyne.NewMenuItem(i18n.T("tray.show"), func() {
+				slog.Debug("Tray: Show Window clicked")
 				mainWindow.Show()
 				mainWindow.RequestFocus() // Good practice to focus
 			}),
-			fyne.NewMenuItem("Log Current Feeling...", func() {
-				log.Println("Tray: Log Current Feeling... clicked.")
+			fyne.NewMenuItem(i18n.T("tray.log"), func() {
+				slog.Debug("Tray: Log Current Feeling clicked")
 				switchToLoggingMode() // Use the mode switch function
 			}),
+			fyne.NewMenuItem(i18n.T("tray.moodMeter"), func() {
+				slog.Debug("Tray: Log on Mood Meter clicked")
+				showMoodMeter()
+			}),
+			fyne.NewMenuItem(i18n.T("tray.history"), func() {
+				slog.Debug("Tray: View Journal History clicked")
+				showHistoryView()
+			}),
+			fyne.NewMenuItem(i18n.T("tray.compare"), func() {
+				slog.Debug("Tray: Compare Emotions clicked")
+				showCompareView()
+			}),
+			fyne.NewMenuItem(i18n.T("tray.learn"), func() {
+				slog.Debug("Tray: Learn Emotion Words clicked")
+				showLearnView()
+			}),
+			fyne.NewMenuItem(i18n.T("tray.checkJournal"), func() {
+				slog.Debug("Tray: Check Journal clicked")
+				checkJournal(true)
+			}),
+			fyne.NewMenuItem(i18n.T("tray.diagnostics"), func() {
+				slog.Debug("Tray: Create Diagnostics Bundle clicked")
+				showDiagnosticsDialog()
+			}),
+			fyne.NewMenuItemSeparator(),
+			colorblindItem,
+			newSortMenuItem(),
+			newLanguageMenuItem(),
+			newDatasetMenuItem(),
 			fyne.NewMenuItemSeparator(),
-			fyne.NewMenuItem("Quit", func() {
-				log.Println("Tray: Quit clicked.")
+			fyne.NewMenuItem(i18n.T("tray.quit"), func() {
+				slog.Debug("Tray: Quit clicked")
 				myApp.Quit()
 			}),
 		)
+		colorblindItem.Action = func() {
+			slog.Debug("Tray: Colorblind-Safe Colors clicked")
+			toggleColorblindPalette()
+			colorblindItem.Checked = currentSettings().ColorblindPalette
+			m.Refresh()
+		}
 		// Consider using a specific icon resource later
 		desk.SetSystemTrayIcon(theme.FyneLogo())
 		desk.SetSystemTrayMenu(m)
-		log.Println("System tray menu set.")
+		slog.Debug("System tray menu set")
 	} else {
-		log.Println("System tray not supported on this platform.")
+		slog.Info("System tray not supported on this platform")
 	}
 }
 
 func setupWindowIntercepts() {
 	// Intercept close requests
 	mainWindow.SetCloseIntercept(func() {
-		log.Println("Main window close intercepted.")
-		if currentMode == ModeLogging {
+		slog.Debug("Main window close intercepted")
+		if controller.State().Mode == ui.ModeLogging {
 			// Optional: Ask for confirmation before cancelling logging?
 			// dialog.ShowConfirm("Cancel Log?", "Closing the window will cancel the current log entry. Proceed?", func(confirm bool) {
 			// 	if confirm {
-			// 		log.Println("Logging cancelled by closing window (confirmed).")
-			// 		switchToBrowsingMode() // Switch back first
+			// 		slog.Debug("Logging cancelled by closing window (confirmed)")
+			// 		controller.CancelLogging() // Switch back first
 			// 		mainWindow.Hide()      // Then hide
 			// 	} else {
-			// 		log.Println("Window close cancelled by user.")
+			// 		slog.Debug("Window close cancelled by user")
 			// 	}
 			// }, mainWindow)
 			// --- For now, just cancel and hide ---
-			log.Println("Window closed during logging. Cancelling log and hiding window.")
-			switchToBrowsingMode() // Ensure state is reset
+			slog.Debug("Window closed during logging; cancelling and hiding the window")
+			controller.CancelLogging() // Ensure state is reset
 			mainWindow.Hide()
 			// ---
 		} else {
-			log.Println("Hiding window (Browsing Mode).")
+			slog.Debug("Hiding window (browsing mode)")
 			mainWindow.Hide() // Default behavior: hide if tray is supported
 		}
 	})
@@ -418,9 +1022,45 @@ func setupWindowIntercepts() {
 	// Fallback if tray isn't supported (already handled by Fyne implicitly, but explicit is okay)
 	if _, ok := myApp.(desktop.App); !ok {
 		mainWindow.SetCloseIntercept(func() {
-			log.Println("Close intercepted (no tray support). Quitting.")
+			slog.Info("Close intercepted without tray support; quitting")
 			myApp.Quit()
 		})
 	}
-	log.Println("Window close intercept setup complete.")
+	slog.Debug("Window close intercept setup complete")
+}
+
+// --- Keyboard Shortcuts ---
+
+// setupKeyboardShortcuts binds window-level keys.
+// Escape and Backspace go back (cards forward keys they don't handle to the
+// canvas, so this works while a card is focused). Ctrl+L (Cmd+L on macOS)
+// starts logging, Ctrl+M logs on the mood meter, Ctrl+F opens search and
+// Ctrl+H opens the journal history.
+func setupKeyboardShortcuts() {
+	canvas := mainWindow.Canvas()
+	canvas.SetOnTypedKey(func(ev *fyne.KeyEvent) {
+		switch ev.Name {
+		case fyne.KeyEscape, fyne.KeyBackspace:
+			slog.Debug("Back key pressed", "key", ev.Name)
+			controller.Back()
+		}
+	})
+
+	shortcuts := []struct {
+		key    fyne.KeyName
+		action func()
+	}{
+		{fyne.KeyL, switchToLoggingMode},
+		{fyne.KeyM, showMoodMeter},
+		{fyne.KeyF, showSearchView},
+		{fyne.KeyH, showHistoryView},
+	}
+	for _, sc := range shortcuts {
+		action := sc.action // Capture loop variable
+		canvas.AddShortcut(
+			&desktop.CustomShortcut{KeyName: sc.key, Modifier: fyne.KeyModifierShortcutDefault},
+			func(fyne.Shortcut) { action() },
+		)
+	}
+	slog.Debug("Keyboard shortcuts setup complete")
 }
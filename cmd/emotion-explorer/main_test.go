@@ -0,0 +1,62 @@
+// cmd/emotion-explorer/main_test.go
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/itsforsxm123/emotion-explorer/internal/journal"
+	"github.com/itsforsxm123/emotion-explorer/internal/settings"
+)
+
+// TestLoadDataRefusesInvalidDataset tests that a custom dataset with a
+// parent cycle is refused: at startup the built-in dataset is used instead,
+// and a reload keeps the current data.
+func TestLoadDataRefusesInvalidDataset(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir) // The overlay and other data files live in the working directory
+
+	datasetPath := filepath.Join(dir, "cyclic.json")
+	dataset := `{"emotions": {
+  "calm": {"id": "calm", "name": "Calm"},
+  "a": {"id": "a", "name": "A", "parentId": "c"},
+  "b": {"id": "b", "name": "B", "parentId": "a"},
+  "c": {"id": "c", "name": "C", "parentId": "b"},
+  "d": {"id": "d", "name": "D", "parentId": "a"}
+}}`
+	if err := os.WriteFile(datasetPath, []byte(dataset), 0644); err != nil {
+		t.Fatal(err)
+	}
+	previousSettings := settings.FilePath()
+	defer settings.SetFilePath(previousSettings)
+	settings.SetFilePath(filepath.Join(dir, "settings.json"))
+	if err := settings.Save(settings.Settings{DatasetPath: datasetPath}); err != nil {
+		t.Fatal(err)
+	}
+	previousJournal := journal.FilePath()
+	defer journal.SetFilePath(previousJournal)
+	journal.SetFilePath(filepath.Join(dir, "journal.json"))
+
+	builtin, err := data.LoadEmotions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := loadData(); err != nil {
+		t.Fatalf("loadData() = %v; want the built-in dataset as a fallback", err)
+	}
+	if got := len(currentData().Emotions); got != len(builtin.Emotions) {
+		t.Errorf("loadData() loaded %d emotions; want the %d built-in ones", got, len(builtin.Emotions))
+	}
+
+	err = loadDataset(datasetPath)
+	if err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("loadDataset() = %v; want a parent cycle error", err)
+	}
+	if _, ok := currentData().Emotions["d"]; ok {
+		t.Errorf("loadDataset() replaced the data with an invalid dataset")
+	}
+}
@@ -0,0 +1,290 @@
+// internal/analytics/analytics.go
+
+// Package analytics summarizes journal entries against the emotion hierarchy,
+// e.g. which families the user logs most. It makes no assumption about how
+// many levels the hierarchy has.
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/journal"
+)
+
+// Summary counts journal entries by emotion, by family and by level.
+type Summary struct {
+	Total      int            // All entries
+	Unresolved int            // Entries whose emotion ID the dataset doesn't know (even through aliases)
+	ByEmotion  map[string]int // Emotion ID -> entries logged with exactly that emotion
+	ByFamily   map[string]int // Root emotion ID -> entries logged anywhere in its family
+	ByDepth    []int          // Entries per level: [0] roots, [1] their children, ... (one slot per dataset level)
+}
+
+// Count pairs an emotion ID with a number of entries.
+type Count struct {
+	EmotionID string
+	Count     int
+}
+
+// Summarize counts entries against the resolver's dataset. Legacy IDs are
+// resolved through the dataset's aliases, so renamed emotions are counted
+// under their current ID. An emotion with several parents counts once, in
+// the family of the path stored with the entry (journal.LogEntry.Path), or
+// of its primary ancestry if there is no usable path.
+func Summarize(entries []journal.LogEntry, resolver *core.IDResolver) Summary {
+	emotions := resolver.Emotions()
+	summary := Summary{
+		Total:     len(entries),
+		ByEmotion: make(map[string]int),
+		ByFamily:  make(map[string]int),
+		ByDepth:   make([]int, core.LevelCount(emotions)),
+	}
+	for _, entry := range entries {
+		id, ok := resolver.Resolve(entry.EmotionID)
+		if !ok {
+			summary.Unresolved++
+			continue
+		}
+		ancestry := core.AncestryAlong(id, entry.Path, emotions)
+		summary.ByEmotion[id]++
+		summary.ByFamily[ancestry[0].ID]++
+		for len(summary.ByDepth) < len(ancestry) {
+			summary.ByDepth = append(summary.ByDepth, 0) // Only a malformed dataset goes deeper
+		}
+		summary.ByDepth[len(ancestry)-1]++
+	}
+	return summary
+}
+
+// TopFamilies returns up to n families by number of entries, most logged
+// first (ties by ID). n <= 0 returns all of them.
+func (s Summary) TopFamilies(n int) []Count {
+	return top(s.ByFamily, n)
+}
+
+// TopEmotions returns up to n emotions by number of entries, most logged
+// first (ties by ID). n <= 0 returns all of them.
+func (s Summary) TopEmotions(n int) []Count {
+	return top(s.ByEmotion, n)
+}
+
+// top sorts counts in descending order and keeps the first n.
+func top(counts map[string]int, n int) []Count {
+	result := make([]Count, 0, len(counts))
+	for id, count := range counts {
+		result = append(result, Count{EmotionID: id, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].EmotionID < result[j].EmotionID
+	})
+	if n > 0 && len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
+
+// Since returns the entries logged at or after from, in their original
+// order, e.g. for a history limited to the last week.
+func Since(entries []journal.LogEntry, from time.Time) []journal.LogEntry {
+	recent := make([]journal.LogEntry, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.Timestamp.Before(from) {
+			recent = append(recent, entry)
+		}
+	}
+	return recent
+}
+
+// --- Usage ---
+
+// Usage counts how often and how recently each emotion was logged, for the
+// most-used and recently used sort modes. An entry also counts for every
+// ancestor along its path, so a family the user logs deep inside still
+// ranks high among the primary emotions.
+func Usage(entries []journal.LogEntry, resolver *core.IDResolver) core.UsageStats {
+	emotions := resolver.Emotions()
+	usage := core.UsageStats{
+		Counts:   make(map[string]int),
+		LastUsed: make(map[string]time.Time),
+	}
+	for _, entry := range entries {
+		id, ok := resolver.Resolve(entry.EmotionID)
+		if !ok {
+			continue
+		}
+		for _, emotion := range core.AncestryAlong(id, entry.Path, emotions) {
+			usage.Counts[emotion.ID]++
+			if entry.Timestamp.After(usage.LastUsed[emotion.ID]) {
+				usage.LastUsed[emotion.ID] = entry.Timestamp
+			}
+		}
+	}
+	return usage
+}
+
+// --- Escalation ---
+
+// LadderStep is a journal entry on an intensity ladder.
+type LadderStep struct {
+	Time      time.Time
+	EmotionID string // Current ID of the logged emotion
+	Rung      int    // Position on the ladder, 0 = mildest
+}
+
+// LadderTrend follows the entries logged on one intensity ladder over time.
+type LadderTrend struct {
+	FamilyID      string       // Emotion the ladder's words share as parent ("" for top-level ladders)
+	Steps         []LadderStep // Oldest first
+	Escalations   int          // Entries stronger than the previous one on the ladder
+	DeEscalations int          // Entries milder than the previous one
+}
+
+// Net returns escalations minus de-escalations: positive if the family's
+// entries have been getting stronger.
+func (t LadderTrend) Net() int {
+	return t.Escalations - t.DeEscalations
+}
+
+// Escalation groups the entries logged on intensity ladders (see
+// core.IntensityLadder) by ladder and counts the steps up and down between
+// consecutive entries. Ladders are returned with the most entries first
+// (ties by family ID); entries off any ladder are ignored.
+func Escalation(entries []journal.LogEntry, resolver *core.IDResolver) []LadderTrend {
+	emotions := resolver.Emotions()
+	sorted := make([]journal.LogEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	trends := make(map[string]*LadderTrend)
+	for _, entry := range sorted {
+		id, ok := resolver.Resolve(entry.EmotionID)
+		if !ok {
+			continue
+		}
+		ladder := core.IntensityLadder(id, entry.Path, emotions)
+		if ladder == nil {
+			continue
+		}
+		familyID := ""
+		if ancestry := core.AncestryAlong(id, entry.Path, emotions); len(ancestry) > 1 {
+			familyID = ancestry[len(ancestry)-2].ID
+		}
+		trend, ok := trends[familyID]
+		if !ok {
+			trend = &LadderTrend{FamilyID: familyID}
+			trends[familyID] = trend
+		}
+		step := LadderStep{Time: entry.Timestamp, EmotionID: id, Rung: core.LadderRung(ladder, id)}
+		if n := len(trend.Steps); n > 0 {
+			switch previous := trend.Steps[n-1].Rung; {
+			case step.Rung > previous:
+				trend.Escalations++
+			case step.Rung < previous:
+				trend.DeEscalations++
+			}
+		}
+		trend.Steps = append(trend.Steps, step)
+	}
+
+	result := make([]LadderTrend, 0, len(trends))
+	for _, trend := range trends {
+		result = append(result, *trend)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if len(result[i].Steps) != len(result[j].Steps) {
+			return len(result[i].Steps) > len(result[j].Steps)
+		}
+		return result[i].FamilyID < result[j].FamilyID
+	})
+	return result
+}
+
+// --- Mood Plane ---
+
+// MoodPoint places a journal entry on the valence/arousal plane.
+type MoodPoint struct {
+	Time      time.Time
+	EmotionID string  // Current ID of the logged emotion
+	Valence   float64 // -1 (unpleasant) to 1 (pleasant)
+	Arousal   float64 // -1 (low energy) to 1 (high energy)
+}
+
+// MoodPoints places entries on the valence/arousal plane, oldest first: at
+// the point tapped on the mood meter if the entry has one, otherwise at its
+// emotion's coordinates (see core.Coordinates). Entries with neither are
+// left out.
+func MoodPoints(entries []journal.LogEntry, resolver *core.IDResolver) []MoodPoint {
+	emotions := resolver.Emotions()
+	points := make([]MoodPoint, 0, len(entries))
+	for _, entry := range entries {
+		id, ok := resolver.Resolve(entry.EmotionID)
+		if !ok {
+			continue
+		}
+		point := MoodPoint{Time: entry.Timestamp, EmotionID: id}
+		if entry.Valence != nil && entry.Arousal != nil {
+			point.Valence, point.Arousal = *entry.Valence, *entry.Arousal
+		} else if point.Valence, point.Arousal, ok = core.Coordinates(id, emotions); !ok {
+			continue
+		}
+		points = append(points, point)
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Time.Before(points[j].Time)
+	})
+	return points
+}
+
+// --- Comparison ---
+
+// EmotionUsage is how often the user logged one of several emotions being
+// compared, and how often alongside the others.
+type EmotionUsage struct {
+	EmotionID string
+	Count     int            // Entries logged with exactly this emotion
+	Days      int            // Days with at least one such entry
+	SameDay   map[string]int // Other compared emotion ID -> days both were logged
+}
+
+// CompareUsage counts the user's entries for each of emotionIDs (current
+// IDs; legacy IDs in the journal are resolved through aliases), in the
+// order given. Two emotions co-occur on a day (in the local time zone) when
+// both were logged that day.
+func CompareUsage(entries []journal.LogEntry, emotionIDs []string, resolver *core.IDResolver) []EmotionUsage {
+	days := make(map[string]map[string]bool, len(emotionIDs)) // Emotion ID -> days logged
+	for _, id := range emotionIDs {
+		days[id] = make(map[string]bool)
+	}
+	counts := make(map[string]int, len(emotionIDs))
+	for _, entry := range entries {
+		id, ok := resolver.Resolve(entry.EmotionID)
+		if !ok || days[id] == nil {
+			continue
+		}
+		counts[id]++
+		days[id][entry.Timestamp.Local().Format("2006-01-02")] = true
+	}
+
+	usage := make([]EmotionUsage, len(emotionIDs))
+	for i, id := range emotionIDs {
+		usage[i] = EmotionUsage{EmotionID: id, Count: counts[id], Days: len(days[id]), SameDay: make(map[string]int)}
+		for _, other := range emotionIDs {
+			if other == id {
+				continue
+			}
+			for day := range days[id] {
+				if days[other][day] {
+					usage[i].SameDay[other]++
+				}
+			}
+		}
+	}
+	return usage
+}
@@ -0,0 +1,256 @@
+// internal/analytics/analytics_test.go
+package analytics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/analytics"
+	"github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/itsforsxm123/emotion-explorer/internal/journal"
+	"github.com/stretchr/testify/assert"
+)
+
+// entriesFor builds one journal entry per emotion ID.
+func entriesFor(ids ...string) []journal.LogEntry {
+	entries := make([]journal.LogEntry, len(ids))
+	for i, id := range ids {
+		entries[i] = journal.LogEntry{EmotionID: id}
+	}
+	return entries
+}
+
+// TestSummarize checks the counts on hierarchies of two and five levels.
+func TestSummarize(t *testing.T) {
+	testCases := []struct {
+		name        string
+		dataset     data.EmotionData
+		entries     []journal.LogEntry
+		wantFamily  map[string]int
+		wantDepth   []int
+		wantUnknown int
+	}{
+		{
+			name: "Two levels",
+			dataset: data.EmotionData{Emotions: map[string]data.Emotion{
+				"calm":    {ID: "calm", Name: "Calm"},
+				"relaxed": {ID: "relaxed", Name: "Relaxed", ParentID: "calm"},
+				"upset":   {ID: "upset", Name: "Upset"},
+			}},
+			entries:    entriesFor("relaxed", "relaxed", "calm", "upset"),
+			wantFamily: map[string]int{"calm": 3, "upset": 1},
+			wantDepth:  []int{2, 2},
+		},
+		{
+			name: "Five levels, legacy and unknown IDs",
+			dataset: data.EmotionData{
+				Emotions: map[string]data.Emotion{
+					"l0": {ID: "l0", Name: "Level 0"},
+					"l1": {ID: "l1", Name: "Level 1", ParentID: "l0"},
+					"l2": {ID: "l2", Name: "Level 2", ParentID: "l1"},
+					"l3": {ID: "l3", Name: "Level 3", ParentID: "l2"},
+					"l4": {ID: "l4", Name: "Level 4", ParentID: "l3"},
+				},
+				Aliases: []data.IDAlias{{From: "old-l4", To: "l4"}},
+			},
+			entries:     entriesFor("l4", "old-l4", "l2", "gone"),
+			wantFamily:  map[string]int{"l0": 3},
+			wantDepth:   []int{0, 0, 1, 0, 2},
+			wantUnknown: 1,
+		},
+		{
+			name: "Several parents, counted once along the logged path",
+			dataset: data.EmotionData{Emotions: map[string]data.Emotion{
+				"fear":        {ID: "fear", Name: "Fear"},
+				"sad":         {ID: "sad", Name: "Sad"},
+				"scared":      {ID: "scared", Name: "Scared", ParentID: "fear"},
+				"overwhelmed": {ID: "overwhelmed", Name: "Overwhelmed", ParentID: "scared", ParentIDs: []string{"sad"}},
+			}},
+			entries: []journal.LogEntry{
+				{EmotionID: "overwhelmed", Path: []string{"sad", "overwhelmed"}},
+				{EmotionID: "overwhelmed"},                                        // No path: primary family
+				{EmotionID: "overwhelmed", Path: []string{"fear", "overwhelmed"}}, // Stale path: primary family
+			},
+			wantFamily: map[string]int{"fear": 2, "sad": 1},
+			wantDepth:  []int{0, 1, 2},
+		},
+		{
+			name: "Parent cycle in a custom dataset",
+			dataset: data.EmotionData{Emotions: map[string]data.Emotion{
+				"a": {ID: "a", Name: "A", ParentID: "c"},
+				"b": {ID: "b", Name: "B", ParentID: "a"},
+				"c": {ID: "c", Name: "C", ParentID: "b"},
+				"d": {ID: "d", Name: "D", ParentID: "a"},
+			}},
+			entries: []journal.LogEntry{
+				{EmotionID: "d"}, // Ancestry b, c, a, d: deeper than the cycle
+				{EmotionID: "a"},
+				{EmotionID: "d", Path: []string{"b", "c", "a", "b", "c", "a", "d"}}, // Round the cycle: not trusted
+			},
+			wantFamily: map[string]int{"b": 3},
+			wantDepth:  []int{0, 0, 1, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			summary := analytics.Summarize(tc.entries, core.NewIDResolver(tc.dataset))
+			assert.Equal(t, len(tc.entries), summary.Total)
+			assert.Equal(t, tc.wantFamily, summary.ByFamily)
+			assert.Equal(t, tc.wantDepth, summary.ByDepth)
+			assert.Equal(t, tc.wantUnknown, summary.Unresolved)
+		})
+	}
+}
+
+func TestTopFamilies(t *testing.T) {
+	summary := analytics.Summary{ByFamily: map[string]int{"bad": 2, "happy": 5, "angry": 2, "sad": 1}}
+	assert.Equal(t, []analytics.Count{
+		{EmotionID: "happy", Count: 5},
+		{EmotionID: "angry", Count: 2}, // Ties are ordered by ID
+		{EmotionID: "bad", Count: 2},
+	}, summary.TopFamilies(3))
+	assert.Len(t, summary.TopFamilies(0), 4)
+}
+
+// TestSince tests limiting entries to a time range.
+func TestSince(t *testing.T) {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	entries := []journal.LogEntry{
+		{EmotionID: "old", Timestamp: day.AddDate(0, 0, -8)},
+		{EmotionID: "edge", Timestamp: day.AddDate(0, 0, -7)},
+		{EmotionID: "new", Timestamp: day},
+	}
+	recent := analytics.Since(entries, day.AddDate(0, 0, -7))
+	if assert.Len(t, recent, 2) {
+		assert.Equal(t, "edge", recent[0].EmotionID)
+		assert.Equal(t, "new", recent[1].EmotionID)
+	}
+	assert.Empty(t, analytics.Since(entries, day.Add(time.Hour)))
+}
+
+// TestUsage tests counting entries for the logged emotion and its ancestors.
+func TestUsage(t *testing.T) {
+	resolver := core.NewIDResolver(data.EmotionData{
+		Emotions: map[string]data.Emotion{
+			"calm":    {ID: "calm", Name: "Calm"},
+			"relaxed": {ID: "relaxed", Name: "Relaxed", ParentID: "calm"},
+			"upset":   {ID: "upset", Name: "Upset"},
+		},
+		Aliases: []data.IDAlias{{From: "chill", To: "relaxed"}},
+	})
+	day := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	entries := []journal.LogEntry{
+		{EmotionID: "relaxed", Timestamp: day.Add(time.Hour)},
+		{EmotionID: "chill", Timestamp: day}, // Legacy ID
+		{EmotionID: "upset", Timestamp: day.Add(2 * time.Hour)},
+		{EmotionID: "gone", Timestamp: day.Add(3 * time.Hour)}, // Unresolved
+	}
+
+	usage := analytics.Usage(entries, resolver)
+	assert.Equal(t, map[string]int{"calm": 2, "relaxed": 2, "upset": 1}, usage.Counts)
+	assert.Equal(t, map[string]time.Time{
+		"calm":    day.Add(time.Hour),
+		"relaxed": day.Add(time.Hour),
+		"upset":   day.Add(2 * time.Hour),
+	}, usage.LastUsed)
+}
+
+// TestEscalation tests following entries along intensity ladders.
+func TestEscalation(t *testing.T) {
+	resolver := core.NewIDResolver(data.EmotionData{Emotions: map[string]data.Emotion{
+		"angry":      {ID: "angry", Name: "Angry"},
+		"annoyed":    {ID: "annoyed", Name: "Annoyed", ParentID: "angry", Intensity: 1},
+		"frustrated": {ID: "frustrated", Name: "Frustrated", ParentID: "angry", Intensity: 2},
+		"furious":    {ID: "furious", Name: "Furious", ParentID: "angry", Intensity: 3},
+		"bitter":     {ID: "bitter", Name: "Bitter", ParentID: "angry"}, // Off the ladder
+		"low":        {ID: "low", Name: "Low", Intensity: 1},
+		"crushed":    {ID: "crushed", Name: "Crushed", Intensity: 2},
+	}})
+	day := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return day.Add(time.Duration(hours) * time.Hour) }
+	entries := []journal.LogEntry{
+		{EmotionID: "furious", Timestamp: at(3)}, // Out of order on disk
+		{EmotionID: "annoyed", Timestamp: at(0)},
+		{EmotionID: "frustrated", Timestamp: at(1)},
+		{EmotionID: "bitter", Timestamp: at(2)},
+		{EmotionID: "annoyed", Timestamp: at(4)},
+		{EmotionID: "crushed", Timestamp: at(5)},
+		{EmotionID: "gone", Timestamp: at(6)},
+	}
+
+	assert.Equal(t, []analytics.LadderTrend{
+		{
+			FamilyID: "angry",
+			Steps: []analytics.LadderStep{
+				{Time: at(0), EmotionID: "annoyed", Rung: 0},
+				{Time: at(1), EmotionID: "frustrated", Rung: 1},
+				{Time: at(3), EmotionID: "furious", Rung: 2},
+				{Time: at(4), EmotionID: "annoyed", Rung: 0},
+			},
+			Escalations:   2,
+			DeEscalations: 1,
+		},
+		{
+			FamilyID: "", // Top-level ladder
+			Steps:    []analytics.LadderStep{{Time: at(5), EmotionID: "crushed", Rung: 1}},
+		},
+	}, analytics.Escalation(entries, resolver))
+	assert.Equal(t, 1, analytics.LadderTrend{Escalations: 2, DeEscalations: 1}.Net())
+}
+
+// TestMoodPoints tests placing entries on the valence/arousal plane.
+func TestMoodPoints(t *testing.T) {
+	v := func(f float64) *float64 { return &f }
+	resolver := core.NewIDResolver(data.EmotionData{Emotions: map[string]data.Emotion{
+		"calm":    {ID: "calm", Name: "Calm", Valence: v(0.6), Arousal: v(-0.6)},
+		"relaxed": {ID: "relaxed", Name: "Relaxed", ParentID: "calm"},
+		"odd":     {ID: "odd", Name: "Odd"},
+	}})
+	day := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	entries := []journal.LogEntry{
+		{EmotionID: "relaxed", Timestamp: day.Add(2 * time.Hour)},                            // Inherits Calm's point
+		{EmotionID: "calm", Timestamp: day, Valence: v(0.2), Arousal: v(-0.9)},               // Tapped point wins
+		{EmotionID: "odd", Timestamp: day.Add(time.Hour)},                                    // Nowhere on the plane
+		{EmotionID: "gone", Timestamp: day.Add(time.Hour), Valence: v(0.5), Arousal: v(0.5)}, // Unresolved
+	}
+
+	assert.Equal(t, []analytics.MoodPoint{
+		{Time: day, EmotionID: "calm", Valence: 0.2, Arousal: -0.9},
+		{Time: day.Add(2 * time.Hour), EmotionID: "relaxed", Valence: 0.6, Arousal: -0.6},
+	}, analytics.MoodPoints(entries, resolver))
+}
+
+// TestCompareUsage tests counting compared emotions and the days they were
+// logged together.
+func TestCompareUsage(t *testing.T) {
+	resolver := core.NewIDResolver(data.EmotionData{
+		Emotions: map[string]data.Emotion{
+			"insecure":   {ID: "insecure", Name: "Insecure"},
+			"inadequate": {ID: "inadequate", Name: "Inadequate", ParentID: "insecure"},
+			"inferior":   {ID: "inferior", Name: "Inferior", ParentID: "insecure"},
+		},
+		Aliases: []data.IDAlias{{From: "not_enough", To: "inadequate"}},
+	})
+	day := func(d, hour int) time.Time { return time.Date(2026, 10, d, hour, 0, 0, 0, time.Local) }
+	entries := []journal.LogEntry{
+		{EmotionID: "insecure", Timestamp: day(1, 9)},
+		{EmotionID: "not_enough", Timestamp: day(1, 20)}, // Legacy ID
+		{EmotionID: "inadequate", Timestamp: day(2, 9)},
+		{EmotionID: "inadequate", Timestamp: day(2, 18)},
+		{EmotionID: "insecure", Timestamp: day(3, 9)},
+		{EmotionID: "inferior", Timestamp: day(3, 9)}, // Not compared
+		{EmotionID: "gone", Timestamp: day(3, 9)},
+	}
+
+	usage := analytics.CompareUsage(entries, []string{"inadequate", "insecure"}, resolver)
+	assert.Equal(t, []analytics.EmotionUsage{
+		{EmotionID: "inadequate", Count: 3, Days: 2, SameDay: map[string]int{"insecure": 1}},
+		{EmotionID: "insecure", Count: 2, Days: 2, SameDay: map[string]int{"inadequate": 1}},
+	}, usage)
+
+	usage = analytics.CompareUsage(nil, []string{"inferior", "insecure"}, resolver)
+	assert.Equal(t, 0, usage[0].Count)
+	assert.Empty(t, usage[0].SameDay)
+}
@@ -0,0 +1,68 @@
+package core
+
+import (
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+)
+
+// IDResolver maps emotion IDs found in journals to emotions in the current
+// dataset, following the dataset's declared aliases (renames across versions).
+type IDResolver struct {
+	emotions map[string]data.Emotion
+	aliases  map[string]string // Legacy ID -> newer ID
+}
+
+// NewIDResolver builds a resolver for the given dataset.
+func NewIDResolver(emotionData data.EmotionData) *IDResolver {
+	aliases := make(map[string]string, len(emotionData.Aliases))
+	for _, alias := range emotionData.Aliases {
+		if alias.From != "" && alias.To != "" {
+			aliases[alias.From] = alias.To
+		}
+	}
+	return &IDResolver{
+		emotions: emotionData.Emotions,
+		aliases:  aliases,
+	}
+}
+
+// Resolve returns the current ID for id.
+// Existing IDs resolve to themselves; legacy IDs follow alias chains
+// (a -> b -> c) until an existing emotion is reached.
+// Returns false if the ID is unknown, the chain dead-ends, or the aliases loop.
+func (r *IDResolver) Resolve(id string) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+	visited := make(map[string]bool)
+	currentID := id
+	for !visited[currentID] {
+		if _, ok := r.emotions[currentID]; ok {
+			return currentID, true
+		}
+		visited[currentID] = true
+		next, ok := r.aliases[currentID]
+		if !ok {
+			return "", false
+		}
+		currentID = next
+	}
+	return "", false // Alias cycle
+}
+
+// Lookup resolves id and returns the matching emotion.
+func (r *IDResolver) Lookup(id string) (data.Emotion, bool) {
+	resolvedID, ok := r.Resolve(id)
+	if !ok {
+		return data.Emotion{}, false
+	}
+	return r.emotions[resolvedID], true
+}
+
+// Emotions returns the dataset's emotions, keyed by ID. The map is shared
+// with the resolver and must not be modified.
+func (r *IDResolver) Emotions() map[string]data.Emotion {
+	if r == nil {
+		return nil
+	}
+	return r.emotions
+}
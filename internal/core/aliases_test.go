@@ -0,0 +1,62 @@
+package core_test
+
+import (
+	"testing"
+
+	core "github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIDResolver tests resolution of current and legacy emotion IDs.
+func TestIDResolver(t *testing.T) {
+
+	// --- Test Data Setup ---
+	emotionData := data.EmotionData{
+		Emotions: map[string]data.Emotion{
+			"happy":    {ID: "happy", Name: "Happy", Type: "primary"},
+			"joyful":   {ID: "joyful", Name: "Joyful", Type: "tertiary", ParentID: "happy"},
+			"let_down": {ID: "let_down", Name: "Let Down", Type: "secondary"},
+		},
+		Aliases: []data.IDAlias{
+			{From: "joy-01", To: "happy", Since: "1.1"},     // Direct rename
+			{From: "joy", To: "joy-01", Since: "1.0"},       // Chain: joy -> joy-01 -> happy
+			{From: "letdown", To: "let_down", Since: "1.1"}, // Spelling fix
+			{From: "gone", To: "also_gone", Since: "1.1"},   // Dead end
+			{From: "loop_a", To: "loop_b"},                  // Cycle
+			{From: "loop_b", To: "loop_a"},                  // Cycle
+		},
+	}
+	resolver := core.NewIDResolver(emotionData)
+
+	// --- Test Cases ---
+	testCases := []struct {
+		name       string
+		id         string
+		expectedID string
+		expectedOK bool
+	}{
+		{name: "Current ID resolves to itself", id: "joyful", expectedID: "joyful", expectedOK: true},
+		{name: "Renamed ID", id: "joy-01", expectedID: "happy", expectedOK: true},
+		{name: "Alias chain", id: "joy", expectedID: "happy", expectedOK: true},
+		{name: "Spelling fix", id: "letdown", expectedID: "let_down", expectedOK: true},
+		{name: "Alias to missing ID", id: "gone", expectedID: "", expectedOK: false},
+		{name: "Alias cycle", id: "loop_a", expectedID: "", expectedOK: false},
+		{name: "Unknown ID", id: "nonexistent_id", expectedID: "", expectedOK: false},
+	}
+
+	// --- Run Test Cases ---
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualID, ok := resolver.Resolve(tc.id)
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedID, actualID)
+		})
+	}
+
+	t.Run("Lookup returns the resolved emotion", func(t *testing.T) {
+		emotion, ok := resolver.Lookup("joy-01")
+		assert.True(t, ok)
+		assert.Equal(t, "Happy", emotion.Name)
+	})
+}
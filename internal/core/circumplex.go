@@ -0,0 +1,64 @@
+// internal/core/circumplex.go
+package core
+
+import (
+	"math"
+	"sort"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+)
+
+// --- Circumplex ---
+//
+// Emotions may carry valence (unpleasant to pleasant) and arousal (low to
+// high energy) coordinates, each from -1 to 1, placing them on the plane of
+// the circumplex model. The mood meter logs by a point on that plane instead
+// of by word.
+
+// Coordinates returns where an emotion sits on the valence/arousal plane:
+// its own coordinates, or those of its nearest ancestor that has them, so
+// words without coordinates still land in their family's region.
+// ok is false if neither the emotion nor any ancestor has coordinates.
+func Coordinates(emotionID string, allEmotions map[string]data.Emotion) (valence, arousal float64, ok bool) {
+	ancestry := GetAncestry(emotionID, allEmotions)
+	for i := len(ancestry) - 1; i >= 0; i-- {
+		if valence, arousal, ok := ancestry[i].Coordinates(); ok {
+			return valence, arousal, true
+		}
+	}
+	return 0, 0, false
+}
+
+// NearestEmotions returns up to n emotions closest to the point (valence,
+// arousal), nearest first (ties by name). Only emotions with their own
+// coordinates are candidates; hidden emotions are skipped.
+func NearestEmotions(valence, arousal float64, allEmotions map[string]data.Emotion, n int) []data.Emotion {
+	type candidate struct {
+		emotion  data.Emotion
+		distance float64
+	}
+	candidates := make([]candidate, 0)
+	for _, emotion := range allEmotions {
+		v, a, ok := emotion.Coordinates()
+		if !ok || emotion.Hidden {
+			continue
+		}
+		candidates = append(candidates, candidate{emotion, math.Hypot(v-valence, a-arousal)})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].emotion.Name < candidates[j].emotion.Name
+	})
+
+	if n >= 0 && len(candidates) > n {
+		candidates = candidates[:n]
+	}
+	nearest := make([]data.Emotion, len(candidates))
+	for i, c := range candidates {
+		nearest[i] = c.emotion
+	}
+	return nearest
+}
@@ -0,0 +1,61 @@
+// internal/core/circumplex_test.go
+package core_test
+
+import (
+	"testing"
+
+	core "github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func coord(v float64) *float64 { return &v }
+
+// circumplexEmotions places two families on the plane; "relaxed" has no
+// coordinates of its own.
+func circumplexEmotions() map[string]data.Emotion {
+	return map[string]data.Emotion{
+		"calm":    {ID: "calm", Name: "Calm", Valence: coord(0.6), Arousal: coord(-0.6)},
+		"relaxed": {ID: "relaxed", Name: "Relaxed", ParentID: "calm"},
+		"elated":  {ID: "elated", Name: "Elated", Valence: coord(0.8), Arousal: coord(0.8)},
+		"tense":   {ID: "tense", Name: "Tense", Valence: coord(-0.6), Arousal: coord(0.6)},
+		"hidden":  {ID: "hidden", Name: "Hidden", Valence: coord(0.6), Arousal: coord(-0.6), Hidden: true},
+		"vague":   {ID: "vague", Name: "Vague", Valence: coord(0.1)}, // Arousal missing
+	}
+}
+
+// TestNearestEmotions tests ranking emotions by distance from a point.
+func TestNearestEmotions(t *testing.T) {
+	emotions := circumplexEmotions()
+	testCases := []struct {
+		name             string
+		valence, arousal float64
+		n                int
+		want             []string
+	}{
+		{"Pleasant and calm", 0.5, -0.5, 2, []string{"calm", "elated"}},
+		{"Unpleasant and tense", -1, 1, 1, []string{"tense"}},
+		{"All candidates", 0, 0, -1, []string{"calm", "tense", "elated"}},
+		{"None requested", 0, 0, 0, []string{}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := core.NearestEmotions(tc.valence, tc.arousal, emotions, tc.n)
+			assert.Equal(t, tc.want, core.PathIDs(got))
+		})
+	}
+}
+
+// TestCoordinates tests inheriting coordinates from the nearest ancestor.
+func TestCoordinates(t *testing.T) {
+	emotions := circumplexEmotions()
+
+	valence, arousal, ok := core.Coordinates("relaxed", emotions)
+	assert.True(t, ok, "Relaxed inherits Calm's coordinates")
+	assert.Equal(t, []float64{0.6, -0.6}, []float64{valence, arousal})
+
+	_, _, ok = core.Coordinates("vague", emotions)
+	assert.False(t, ok, "Half a coordinate is no coordinate")
+	_, _, ok = core.Coordinates("missing", emotions)
+	assert.False(t, ok)
+}
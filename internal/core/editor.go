@@ -0,0 +1,334 @@
+// internal/core/editor.go
+package core
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+)
+
+// DatasetEditor applies edits to a private copy of a dataset, keeping it
+// consistent: new emotions get unique IDs, reparenting cannot create cycles
+// and deleted emotions leave aliases so old journal entries still resolve.
+// Run data.Validate on the result before saving.
+type DatasetEditor struct {
+	data data.EmotionData
+}
+
+// NewDatasetEditor starts editing a copy of emotionData.
+func NewDatasetEditor(emotionData data.EmotionData) *DatasetEditor {
+	return &DatasetEditor{data: copyDataset(emotionData)}
+}
+
+// Data returns a copy of the edited dataset.
+func (e *DatasetEditor) Data() data.EmotionData {
+	return copyDataset(e.data)
+}
+
+// Emotions returns the edited emotions for reading. The map is live and
+// must not be modified; use the editing methods instead.
+func (e *DatasetEditor) Emotions() map[string]data.Emotion {
+	return e.data.Emotions
+}
+
+// Emotion returns the emotion with the given ID in the edited dataset.
+func (e *DatasetEditor) Emotion(id string) (data.Emotion, bool) {
+	emotion, ok := e.data.Emotions[id]
+	return emotion, ok
+}
+
+// Add creates an emotion named name under parentID ("" for a top-level
+// emotion). Its ID is derived from the name and its type from its depth.
+// The color is left empty, so it inherits its parent's color.
+func (e *DatasetEditor) Add(parentID, name string) (data.Emotion, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return data.Emotion{}, fmt.Errorf("name must not be empty")
+	}
+	depth := 0
+	if parentID != "" {
+		if _, ok := e.data.Emotions[parentID]; !ok {
+			return data.Emotion{}, fmt.Errorf("unknown parent '%s'", parentID)
+		}
+		depth = len(GetAncestry(parentID, e.data.Emotions))
+	}
+	emotion := data.Emotion{
+		ID:       e.uniqueID(name),
+		Name:     name,
+		Type:     e.data.TypeForDepth(depth),
+		ParentID: parentID,
+		Position: e.data.NextPosition(), // Last among its siblings
+	}
+	if e.data.Emotions == nil {
+		e.data.Emotions = make(map[string]data.Emotion)
+	}
+	e.data.Emotions[emotion.ID] = emotion
+	return emotion, nil
+}
+
+// Rename changes an emotion's default-language name. The ID stays the same,
+// so journal entries keep pointing at it.
+func (e *DatasetEditor) Rename(id, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("name must not be empty")
+	}
+	return e.update(id, func(emotion *data.Emotion) { emotion.Name = name })
+}
+
+// Recolor sets an emotion's color. hex is validated with data.ParseHexColor
+// and stored normalized ("#RRGGBB"); an empty string clears the color so the
+// emotion inherits its parent's.
+func (e *DatasetEditor) Recolor(id, hex string) error {
+	hex = strings.TrimSpace(hex)
+	if hex != "" {
+		c, err := data.ParseHexColor(hex)
+		if err != nil {
+			return err
+		}
+		hex = data.FormatHexColor(c)
+	}
+	return e.update(id, func(emotion *data.Emotion) { emotion.Color = hex })
+}
+
+// Reparent moves an emotion (with its descendants) under newParentID, or to
+// the top level if newParentID is empty. Only the primary parent changes;
+// further parents (data.Emotion.ParentIDs) are kept. Types are updated to
+// match the new depths. Moving an emotion under itself or one of its
+// descendants fails.
+func (e *DatasetEditor) Reparent(id, newParentID string) error {
+	if _, ok := e.data.Emotions[id]; !ok {
+		return fmt.Errorf("unknown emotion '%s'", id)
+	}
+	if newParentID != "" {
+		if _, ok := e.data.Emotions[newParentID]; !ok {
+			return fmt.Errorf("unknown parent '%s'", newParentID)
+		}
+		for _, descendant := range Subtree(id, e.data.Emotions) {
+			if descendant == newParentID {
+				return fmt.Errorf("cannot move '%s' under its own descendant '%s'", id, newParentID)
+			}
+		}
+	}
+	e.update(id, func(emotion *data.Emotion) {
+		emotion.ParentID = newParentID
+		emotion.ParentIDs = removeString(emotion.ParentIDs, newParentID) // Now the primary one
+	})
+
+	// Depths changed for the whole subtree
+	e.retype(id)
+	return nil
+}
+
+// retype updates the types of an emotion and its descendants to match their
+// depths after a move.
+func (e *DatasetEditor) retype(id string) {
+	for _, movedID := range Subtree(id, e.data.Emotions) {
+		depth := Depth(movedID, e.data.Emotions)
+		e.update(movedID, func(emotion *data.Emotion) { emotion.Type = e.data.TypeForDepth(depth) })
+	}
+}
+
+// Delete removes an emotion and its descendants and returns their IDs.
+// Descendants that also belong to another family (see data.Emotion.ParentIDs)
+// are kept and just lose the deleted parents.
+// Each removed ID gets an alias to the deleted emotion's parent, so journal
+// entries using them are attributed to the parent. Deleting a top-level
+// emotion leaves those entries unresolved (the journal check offers a remap).
+func (e *DatasetEditor) Delete(id string) ([]string, error) {
+	emotion, ok := e.data.Emotions[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown emotion '%s'", id)
+	}
+	removed := e.deletedWith(id)
+	for _, removedID := range removed {
+		delete(e.data.Emotions, removedID)
+		if emotion.PrimaryParent() != "" {
+			e.data.Aliases = append(e.data.Aliases, data.IDAlias{
+				From:  removedID,
+				To:    emotion.PrimaryParent(),
+				Since: e.data.Metadata.Version,
+				Note:  "Removed in the dataset editor",
+			})
+		}
+	}
+
+	// Survivors with several parents drop the deleted ones
+	isRemoved := make(map[string]bool, len(removed))
+	for _, removedID := range removed {
+		isRemoved[removedID] = true
+	}
+	for survivorID, survivor := range e.data.Emotions {
+		parents := survivor.Parents()
+		kept := parents[:0]
+		for _, parentID := range parents {
+			if !isRemoved[parentID] {
+				kept = append(kept, parentID)
+			}
+		}
+		if len(kept) == len(survivor.Parents()) {
+			continue
+		}
+		primaryChanged := survivor.PrimaryParent() != kept[0]
+		survivor.ParentID, survivor.ParentIDs = kept[0], nil
+		if len(kept) > 1 {
+			survivor.ParentIDs = append([]string(nil), kept[1:]...)
+		}
+		e.data.Emotions[survivorID] = survivor
+		if primaryChanged {
+			e.retype(survivorID)
+		}
+	}
+
+	// Older aliases that led into a deleted top-level family now dangle
+	resolver := NewIDResolver(e.data)
+	kept := e.data.Aliases[:0]
+	for _, alias := range e.data.Aliases {
+		if _, ok := resolver.Resolve(alias.To); ok {
+			kept = append(kept, alias)
+		}
+	}
+	e.data.Aliases = kept
+	return removed, nil
+}
+
+// DeleteImpact reports what deleting id would affect: the number of emotions
+// removed and how many journal entries use them. usage maps emotion IDs to
+// journal entry counts.
+func (e *DatasetEditor) DeleteImpact(id string, usage map[string]int) (emotions, entries int) {
+	removed := e.deletedWith(id)
+	for _, removedID := range removed {
+		entries += usage[removedID]
+	}
+	return len(removed), entries
+}
+
+// deletedWith returns the emotions Delete(id) removes: id and the
+// descendants whose parents are all removed, in Subtree order.
+func (e *DatasetEditor) deletedWith(id string) []string {
+	removed := map[string]bool{id: true}
+	subtree := Subtree(id, e.data.Emotions)
+	// A descendant can be listed before one of its parents, so repeat until
+	// nothing changes
+	for changed := true; changed; {
+		changed = false
+		for _, descendantID := range subtree[1:] {
+			if removed[descendantID] {
+				continue
+			}
+			allRemoved := true
+			for _, parentID := range e.data.Emotions[descendantID].Parents() {
+				allRemoved = allRemoved && removed[parentID]
+			}
+			if allRemoved {
+				removed[descendantID] = true
+				changed = true
+			}
+		}
+	}
+	ids := make([]string, 0, len(subtree))
+	for _, descendantID := range subtree {
+		if removed[descendantID] {
+			ids = append(ids, descendantID)
+		}
+	}
+	return ids
+}
+
+// Subtree returns the ID of an emotion followed by the IDs of all its
+// descendants (breadth-first, children in dataset order), each once even if it has
+// several parents in the subtree. Returns nil if id is unknown.
+func Subtree(id string, allEmotions map[string]data.Emotion) []string {
+	if _, ok := allEmotions[id]; !ok {
+		return nil
+	}
+	ids := []string{id}
+	visited := map[string]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range GetChildrenOf(ids[i], allEmotions) {
+			if !visited[child.ID] { // Guards against cycles in malformed data
+				visited[child.ID] = true
+				ids = append(ids, child.ID)
+			}
+		}
+	}
+	return ids
+}
+
+// update applies change to the emotion with the given ID.
+func (e *DatasetEditor) update(id string, change func(emotion *data.Emotion)) error {
+	emotion, ok := e.data.Emotions[id]
+	if !ok {
+		return fmt.Errorf("unknown emotion '%s'", id)
+	}
+	change(&emotion)
+	e.data.Emotions[id] = emotion
+	return nil
+}
+
+// uniqueID derives an ID from a name ("Zoomed out" -> "zoomed_out") that is
+// not used by any emotion or alias.
+func (e *DatasetEditor) uniqueID(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "_"):
+			b.WriteRune('_')
+		}
+	}
+	base := strings.TrimSuffix(b.String(), "_")
+	if base == "" {
+		base = "emotion"
+	}
+
+	taken := func(id string) bool {
+		if _, ok := e.data.Emotions[id]; ok {
+			return true
+		}
+		for _, alias := range e.data.Aliases {
+			if alias.From == id {
+				return true // Reusing a retired ID would hijack old journal entries
+			}
+		}
+		return false
+	}
+	id := base
+	for n := 2; taken(id); n++ {
+		id = fmt.Sprintf("%s_%d", base, n)
+	}
+	return id
+}
+
+// copyDataset makes a copy of a dataset that shares no maps or slices with
+// the original. Per-emotion translation maps are never edited, so they are shared.
+func copyDataset(emotionData data.EmotionData) data.EmotionData {
+	copied := emotionData
+	copied.EmotionTypes = make(map[string]data.EmotionType, len(emotionData.EmotionTypes))
+	for id, emotionType := range emotionData.EmotionTypes {
+		copied.EmotionTypes[id] = emotionType
+	}
+	copied.Emotions = make(map[string]data.Emotion, len(emotionData.Emotions))
+	for id, emotion := range emotionData.Emotions {
+		copied.Emotions[id] = emotion
+	}
+	copied.Aliases = append([]data.IDAlias(nil), emotionData.Aliases...)
+	return copied
+}
+
+// removeString returns list without any occurrence of s.
+func removeString(list []string, s string) []string {
+	kept := list[:0:0]
+	for _, item := range list {
+		if item != s {
+			kept = append(kept, item)
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return kept
+}
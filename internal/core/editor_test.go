@@ -0,0 +1,178 @@
+// internal/core/editor_test.go
+package core_test
+
+import (
+	"testing"
+
+	core "github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// editorTestData is a small three-level dataset for editor tests.
+func editorTestData() data.EmotionData {
+	return data.EmotionData{
+		Metadata: data.Metadata{Version: "1.1"},
+		EmotionTypes: map[string]data.EmotionType{
+			"primary":   {ID: "primary", Level: 1},
+			"secondary": {ID: "secondary", Level: 2},
+			"tertiary":  {ID: "tertiary", Level: 3},
+		},
+		Emotions: map[string]data.Emotion{
+			"happy":   {ID: "happy", Name: "Happy", Type: "primary", Color: "#F29727"},
+			"bad":     {ID: "bad", Name: "Bad", Type: "primary", Color: "#4B8A5B"},
+			"playful": {ID: "playful", Name: "Playful", Type: "secondary", ParentID: "happy"},
+			"aroused": {ID: "aroused", Name: "Aroused", Type: "tertiary", ParentID: "playful"},
+			"cheeky":  {ID: "cheeky", Name: "Cheeky", Type: "tertiary", ParentID: "playful"},
+			"tired":   {ID: "tired", Name: "Tired", Type: "secondary", ParentID: "bad"},
+		},
+		Aliases: []data.IDAlias{{From: "joy-01", To: "happy"}},
+	}
+}
+
+// TestDatasetEditorAdd tests ID generation and type assignment for new emotions.
+func TestDatasetEditorAdd(t *testing.T) {
+	editor := core.NewDatasetEditor(editorTestData())
+
+	added, err := editor.Add("tired", "Zoomed out")
+	require.NoError(t, err)
+	assert.Equal(t, "zoomed_out", added.ID)
+	assert.Equal(t, "tertiary", added.Type, "Type should follow depth")
+	assert.Equal(t, "", added.Color, "New emotions inherit their parent's color")
+
+	again, err := editor.Add("tired", "Zoomed  out!")
+	require.NoError(t, err)
+	assert.Equal(t, "zoomed_out_2", again.ID, "IDs must be unique")
+
+	retired, err := editor.Add("", "Joy 01")
+	require.NoError(t, err)
+	assert.Equal(t, "joy_01", retired.ID)
+	assert.Equal(t, "primary", retired.Type)
+
+	_, err = editor.Add("", "   ")
+	assert.Error(t, err, "Empty names are refused")
+	_, err = editor.Add("nope", "Glad")
+	assert.Error(t, err, "Unknown parents are refused")
+
+	assert.Empty(t, data.Validate(editor.Data()))
+}
+
+// TestDatasetEditorRenameRecolor tests simple field edits.
+func TestDatasetEditorRenameRecolor(t *testing.T) {
+	original := editorTestData()
+	editor := core.NewDatasetEditor(original)
+
+	require.NoError(t, editor.Rename("playful", "Light-hearted"))
+	require.NoError(t, editor.Recolor("playful", "#abc"))
+	playful, _ := editor.Emotion("playful")
+	assert.Equal(t, "Light-hearted", playful.Name)
+	assert.Equal(t, "#AABBCC", playful.Color, "Colors are stored normalized")
+
+	assert.Error(t, editor.Recolor("playful", "orange"))
+	assert.Error(t, editor.Rename("playful", ""))
+	assert.Error(t, editor.Rename("nope", "Nope"))
+
+	require.NoError(t, editor.Recolor("playful", ""))
+	playful, _ = editor.Emotion("playful")
+	assert.Equal(t, "", playful.Color)
+
+	assert.Equal(t, "Playful", original.Emotions["playful"].Name, "The original dataset must not change")
+}
+
+// TestDatasetEditorReparent tests moving subtrees and cycle prevention.
+func TestDatasetEditorReparent(t *testing.T) {
+	editor := core.NewDatasetEditor(editorTestData())
+
+	require.NoError(t, editor.Reparent("playful", "tired"))
+	playful, _ := editor.Emotion("playful")
+	aroused, _ := editor.Emotion("aroused")
+	assert.Equal(t, "tired", playful.ParentID)
+	assert.Equal(t, "tertiary", playful.Type)
+	assert.Equal(t, "tertiary", aroused.Type, "Deeper than declared types use the deepest type")
+
+	require.NoError(t, editor.Reparent("playful", ""))
+	playful, _ = editor.Emotion("playful")
+	aroused, _ = editor.Emotion("aroused")
+	assert.Equal(t, "primary", playful.Type)
+	assert.Equal(t, "secondary", aroused.Type)
+
+	assert.Error(t, editor.Reparent("happy", "happy"), "Cannot be its own parent")
+	assert.Error(t, editor.Reparent("playful", "aroused"), "Cannot move under a descendant")
+	assert.Error(t, editor.Reparent("playful", "nope"))
+	assert.Empty(t, data.Validate(editor.Data()))
+}
+
+// TestDatasetEditorDelete tests subtree removal, aliasing and impact reporting.
+func TestDatasetEditorDelete(t *testing.T) {
+	editor := core.NewDatasetEditor(editorTestData())
+	usage := map[string]int{"playful": 2, "aroused": 1, "happy": 5}
+
+	emotions, entries := editor.DeleteImpact("playful", usage)
+	assert.Equal(t, 3, emotions)
+	assert.Equal(t, 3, entries)
+
+	removed, err := editor.Delete("playful")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"playful", "aroused", "cheeky"}, removed)
+
+	result := editor.Data()
+	resolver := core.NewIDResolver(result)
+	for _, id := range removed {
+		_, exists := result.Emotions[id]
+		assert.False(t, exists, "%s should be removed", id)
+		resolved, ok := resolver.Resolve(id)
+		assert.True(t, ok)
+		assert.Equal(t, "happy", resolved, "Removed IDs are attributed to the parent")
+	}
+	assert.Empty(t, data.Validate(result))
+
+	// Deleting a family drops aliases that would dangle
+	_, err = editor.Delete("happy")
+	require.NoError(t, err)
+	result = editor.Data()
+	assert.Empty(t, result.Aliases)
+	assert.Empty(t, data.Validate(result))
+
+	_, err = editor.Delete("nope")
+	assert.Error(t, err)
+}
+
+// TestDatasetEditorMultipleParents tests editing emotions with several parents.
+func TestDatasetEditorMultipleParents(t *testing.T) {
+	dataset := editorTestData()
+	cheeky := dataset.Emotions["cheeky"]
+	cheeky.ParentIDs = []string{"tired"}
+	dataset.Emotions["cheeky"] = cheeky
+	editor := core.NewDatasetEditor(dataset)
+
+	emotions, _ := editor.DeleteImpact("playful", nil)
+	assert.Equal(t, 2, emotions, "Cheeky is also under Tired and survives")
+	removed, err := editor.Delete("playful")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"playful", "aroused"}, removed)
+	cheeky, ok := editor.Emotion("cheeky")
+	require.True(t, ok)
+	assert.Equal(t, "tired", cheeky.ParentID, "The remaining parent becomes the primary one")
+	assert.Empty(t, cheeky.ParentIDs)
+	assert.Equal(t, "tertiary", cheeky.Type)
+	assert.Empty(t, data.Validate(editor.Data()))
+
+	// Reparenting keeps further parents and never lists the new parent twice
+	editor = core.NewDatasetEditor(dataset)
+	require.NoError(t, editor.Reparent("cheeky", "tired"))
+	cheeky, _ = editor.Emotion("cheeky")
+	assert.Equal(t, "tired", cheeky.ParentID)
+	assert.Empty(t, cheeky.ParentIDs)
+	require.NoError(t, editor.Reparent("cheeky", "bad"))
+	cheeky, _ = editor.Emotion("cheeky")
+	assert.Equal(t, []string{"bad"}, cheeky.Parents())
+}
+
+// TestSubtree tests collecting an emotion and its descendants.
+func TestSubtree(t *testing.T) {
+	emotions := editorTestData().Emotions
+	assert.Equal(t, []string{"happy", "playful", "aroused", "cheeky"}, core.Subtree("happy", emotions))
+	assert.Equal(t, []string{"tired"}, core.Subtree("tired", emotions))
+	assert.Nil(t, core.Subtree("nope", emotions))
+}
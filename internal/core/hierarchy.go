@@ -7,37 +7,50 @@ import (
 	"github.com/itsforsxm123/emotion-explorer/internal/data" // Adjust import path if needed
 )
 
-// GetPrimaryEmotions filters the provided map of emotions and returns a slice
-// containing only the primary emotions, sorted alphabetically by name.
-// It returns an empty slice if the input map is nil or empty, or if no
-// primary emotions are found.
-func GetPrimaryEmotions(emotions map[string]data.Emotion) []data.Emotion {
+// --- Hierarchy ---
+//
+// The hierarchy is usually a tree, but a dataset may list further parents for
+// an emotion (data.Emotion.ParentIDs), which makes it a DAG: "overwhelmed"
+// can sit under both Fear and Sad. Such an emotion is a child of each of its
+// parents, while single-answer questions (its ancestry, its depth) follow its
+// primary parent. Where the route matters — the breadcrumb, a journal entry's
+// family — callers keep the path the user took (see ResolvePath).
+
+// GetRootEmotions returns the top-level emotions: those without a parent,
+// whatever their type or however deep the hierarchy below them goes.
+// The result is in dataset order (see data.LessInDatasetOrder; alphabetical
+// for datasets without one); emotions hidden by a user overlay are skipped.
+// SortEmotions offers the other orders.
+// It returns an empty slice if the input map is nil or empty.
+func GetRootEmotions(emotions map[string]data.Emotion) []data.Emotion {
 	// Handle nil or empty map gracefully
 	if len(emotions) == 0 {
 		return []data.Emotion{}
 	}
 
-	primaryEmotions := make([]data.Emotion, 0) // Initialize with 0 capacity
+	roots := make([]data.Emotion, 0) // Initialize with 0 capacity
 
 	// Iterate through the map of all emotions
 	for _, emotion := range emotions {
-		// Check if the emotion's type is "primary"
-		if emotion.Type == "primary" {
-			primaryEmotions = append(primaryEmotions, emotion)
+		// The hierarchy, not the type name, decides what is top level
+		if len(emotion.Parents()) == 0 && !emotion.Hidden {
+			roots = append(roots, emotion)
 		}
 	}
 
-	// Sort the primary emotions alphabetically by name for consistent UI display
-	sort.Slice(primaryEmotions, func(i, j int) bool {
-		return primaryEmotions[i].Name < primaryEmotions[j].Name
+	// Keep the dataset's order (e.g. wheel order) for consistent UI display
+	sort.Slice(roots, func(i, j int) bool {
+		return data.LessInDatasetOrder(roots[i], roots[j])
 	})
 
-	return primaryEmotions
+	return roots
 }
 
-// GetChildrenOf finds all direct children of a given parent emotion ID.
+// GetChildrenOf finds all direct children of a given parent emotion ID,
+// including emotions that list it as one of several parents.
 // It searches the provided map of all emotions and returns a slice containing
-// the child emotions, sorted alphabetically by name.
+// the child emotions in dataset order. Emotions hidden by a user overlay
+// are skipped.
 // Returns an empty slice if the parentID is not found, if the parent has no
 // children, or if the allEmotions map is nil or empty.
 func GetChildrenOf(parentID string, allEmotions map[string]data.Emotion) []data.Emotion {
@@ -50,16 +63,193 @@ func GetChildrenOf(parentID string, allEmotions map[string]data.Emotion) []data.
 
 	// Iterate through all emotions in the map
 	for _, emotion := range allEmotions {
-		// Check if the emotion's ParentID matches the requested parentID
-		if emotion.ParentID == parentID {
+		// Check if the requested parentID is one of the emotion's parents
+		if emotion.HasParent(parentID) && !emotion.Hidden {
 			children = append(children, emotion)
 		}
 	}
 
-	// Sort the children alphabetically by name for consistent UI display
+	// Keep the dataset's order (e.g. intensity order) for consistent UI display
 	sort.Slice(children, func(i, j int) bool {
-		return children[i].Name < children[j].Name
+		return data.LessInDatasetOrder(children[i], children[j])
 	})
 
 	return children
 }
+
+// GetAncestry returns the chain of emotions from the root of the hierarchy
+// down to (and including) the emotion with the given ID, following primary
+// parents. For example, the ancestry of "aroused" is [Happy, Playful, Aroused].
+// Returns an empty slice if the ID is not found. A malformed dataset with a
+// parent cycle is cut at the first repeated ID instead of looping forever.
+func GetAncestry(emotionID string, allEmotions map[string]data.Emotion) []data.Emotion {
+	chain := make([]data.Emotion, 0)
+	visited := make(map[string]bool)
+
+	// Walk up the primary parent links, collecting emotions from leaf to root
+	currentID := emotionID
+	for currentID != "" && !visited[currentID] {
+		emotion, ok := allEmotions[currentID]
+		if !ok {
+			break // Dangling parent reference ends the chain
+		}
+		visited[currentID] = true
+		chain = append(chain, emotion)
+		currentID = emotion.PrimaryParent()
+	}
+
+	// Reverse so the root comes first
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain
+}
+
+// Depth returns how far below the top of the hierarchy an emotion sits along
+// its primary parents: 0 for a root, 1 for its children and so on, for any
+// number of levels.
+// Returns -1 if the ID is not found.
+func Depth(emotionID string, allEmotions map[string]data.Emotion) int {
+	return len(GetAncestry(emotionID, allEmotions)) - 1
+}
+
+// LevelCount returns how many levels the hierarchy has, or 0 for an empty
+// map. With several parents an emotion can sit deeper along another path
+// than its Depth, so this is the length of the longest path from a root.
+// In a malformed dataset with a parent cycle it is never less than the
+// longest GetAncestry, which cuts the cycle at the first repeated ID.
+func LevelCount(allEmotions map[string]data.Emotion) int {
+	longest := make(map[string]int, len(allEmotions)) // ID -> levels down to it, once known
+	var levelsTo func(id string, visiting map[string]bool) int
+	levelsTo = func(id string, visiting map[string]bool) int {
+		if levels, ok := longest[id]; ok {
+			return levels
+		}
+		visiting[id] = true
+		levels := 1
+		for _, parentID := range allEmotions[id].Parents() {
+			if _, ok := allEmotions[parentID]; !ok || visiting[parentID] {
+				continue // Dangling parents and cycles end the path
+			}
+			if above := levelsTo(parentID, visiting) + 1; above > levels {
+				levels = above
+			}
+		}
+		delete(visiting, id)
+		longest[id] = levels
+		return levels
+	}
+
+	levels := 0
+	for id := range allEmotions {
+		if depth := levelsTo(id, map[string]bool{}); depth > levels {
+			levels = depth
+		}
+		if depth := len(GetAncestry(id, allEmotions)); depth > levels {
+			levels = depth // A cycle cut elsewhere than above
+		}
+	}
+	return levels
+}
+
+// --- Paths ---
+
+// ResolvePath returns the emotions along a path of IDs from a root down the
+// hierarchy, e.g. ["fear", "scared", "overwhelmed"]. It returns nil if the
+// path is empty, doesn't start at a root, skips a level, names an unknown
+// or hidden emotion or repeats one (a parent cycle), so a stale path (the
+// dataset changed) is never trusted.
+func ResolvePath(path []string, allEmotions map[string]data.Emotion) []data.Emotion {
+	if len(path) == 0 {
+		return nil
+	}
+	emotions := make([]data.Emotion, 0, len(path))
+	seen := make(map[string]bool, len(path))
+	for i, id := range path {
+		emotion, ok := allEmotions[id]
+		if !ok || emotion.Hidden || seen[id] {
+			return nil
+		}
+		seen[id] = true
+		if i == 0 && len(emotion.Parents()) > 0 {
+			return nil // Must start at the top
+		}
+		if i > 0 && !emotion.HasParent(path[i-1]) {
+			return nil
+		}
+		emotions = append(emotions, emotion)
+	}
+	return emotions
+}
+
+// AncestryAlong returns the chain from a root down to emotionID along path
+// if it is a valid path ending at emotionID, and GetAncestry otherwise.
+// It answers "how did the user get here" for emotions with several parents.
+func AncestryAlong(emotionID string, path []string, allEmotions map[string]data.Emotion) []data.Emotion {
+	if len(path) > 0 && path[len(path)-1] == emotionID {
+		if chain := ResolvePath(path, allEmotions); chain != nil {
+			return chain
+		}
+	}
+	return GetAncestry(emotionID, allEmotions)
+}
+
+// PathIDs returns the IDs of a chain of emotions, e.g. from GetAncestry.
+func PathIDs(chain []data.Emotion) []string {
+	ids := make([]string, len(chain))
+	for i, emotion := range chain {
+		ids[i] = emotion.ID
+	}
+	return ids
+}
+
+// --- Comparison ---
+
+// NearestCommonAncestor returns the deepest emotion that every one of the
+// given emotions is or descends from: Fearful for "inadequate" and
+// "excluded", Insecure for "insecure" and "inadequate". All parents count,
+// so emotions with several parents meet wherever their families do; among
+// equally deep candidates the first in dataset order wins.
+// ok is false if the IDs are empty or unknown, or sit in different families.
+func NearestCommonAncestor(emotionIDs []string, allEmotions map[string]data.Emotion) (ancestor data.Emotion, ok bool) {
+	if len(emotionIDs) == 0 {
+		return data.Emotion{}, false
+	}
+	common := ancestorsOf(emotionIDs[0], allEmotions)
+	for _, id := range emotionIDs[1:] {
+		others := ancestorsOf(id, allEmotions)
+		for candidate := range common {
+			if !others[candidate] {
+				delete(common, candidate)
+			}
+		}
+	}
+
+	bestDepth := -1
+	for id := range common {
+		emotion := allEmotions[id]
+		depth := Depth(id, allEmotions)
+		if depth > bestDepth || (depth == bestDepth && data.LessInDatasetOrder(emotion, ancestor)) {
+			ancestor, bestDepth = emotion, depth
+		}
+	}
+	return ancestor, bestDepth >= 0
+}
+
+// ancestorsOf returns the IDs of an emotion and everything above it along
+// all of its parents. Unknown IDs have none.
+func ancestorsOf(emotionID string, allEmotions map[string]data.Emotion) map[string]bool {
+	ancestors := make(map[string]bool)
+	pending := []string{emotionID}
+	for len(pending) > 0 {
+		id := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		emotion, found := allEmotions[id]
+		if !found || ancestors[id] {
+			continue // Dangling parents and cycles end the walk
+		}
+		ancestors[id] = true
+		pending = append(pending, emotion.Parents()...)
+	}
+	return ancestors
+}
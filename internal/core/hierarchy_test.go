@@ -12,8 +12,8 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-// TestGetPrimaryEmotions tests the GetPrimaryEmotions function with various scenarios.
-func TestGetPrimaryEmotions(t *testing.T) {
+// TestGetRootEmotions tests the GetRootEmotions function with various scenarios.
+func TestGetRootEmotions(t *testing.T) {
 
 	// --- Test Data Setup ---
 
@@ -31,8 +31,8 @@ func TestGetPrimaryEmotions(t *testing.T) {
 
 	testCases := []struct {
 		name           string                  // Name of the test case
-		inputEmotions  map[string]data.Emotion // Input map for GetPrimaryEmotions
-		expectedOutput []data.Emotion          // Expected slice of primary emotions (must be sorted by Name)
+		inputEmotions  map[string]data.Emotion // Input map for GetRootEmotions
+		expectedOutput []data.Emotion          // Expected slice of root emotions (in dataset order)
 	}{
 		{
 			name: "Happy Path - Mixed Emotions",
@@ -44,7 +44,8 @@ func TestGetPrimaryEmotions(t *testing.T) {
 				"grief":       emotionGrief,       // Secondary
 				"rage":        emotionRage,        // Tertiary/Other
 			},
-			// Expected output should only contain primary emotions, sorted alphabetically by Name
+			// Expected output should only contain the roots; without positions,
+			// dataset order falls back to the names
 			expectedOutput: []data.Emotion{
 				emotionAnger, // Anger comes before Joy
 				emotionJoy,
@@ -59,7 +60,7 @@ func TestGetPrimaryEmotions(t *testing.T) {
 				"sadness": emotionSadness,
 				"anger":   emotionAnger,
 			},
-			// Expected output should be all input emotions, sorted alphabetically by Name
+			// Expected output should be all input emotions, in dataset order (by Name here)
 			expectedOutput: []data.Emotion{
 				emotionAnger,
 				emotionFear,
@@ -67,6 +68,22 @@ func TestGetPrimaryEmotions(t *testing.T) {
 				emotionSadness,
 			},
 		},
+		{
+			name: "Dataset Order Wins Over Names",
+			inputEmotions: map[string]data.Emotion{
+				// Wheel order as a file would list it, not alphabetical
+				"joy":     {ID: "joy", Name: "Joy", Position: 0},
+				"sadness": {ID: "sadness", Name: "Sadness", Position: 1},
+				"anger":   {ID: "anger", Name: "Anger", Position: 2},
+				"fear":    {ID: "fear", Name: "Fear", Position: 3, Order: 1}, // An explicit order comes first
+			},
+			expectedOutput: []data.Emotion{
+				{ID: "fear", Name: "Fear", Position: 3, Order: 1},
+				{ID: "joy", Name: "Joy", Position: 0},
+				{ID: "sadness", Name: "Sadness", Position: 1},
+				{ID: "anger", Name: "Anger", Position: 2},
+			},
+		},
 		{
 			name: "Edge Case - No Primary Emotions",
 			inputEmotions: map[string]data.Emotion{
@@ -77,6 +94,27 @@ func TestGetPrimaryEmotions(t *testing.T) {
 			// Expected output should be an empty slice
 			expectedOutput: []data.Emotion{},
 		},
+		{
+			name: "Roots Come From The Hierarchy, Not The Type Name",
+			inputEmotions: map[string]data.Emotion{
+				// A two-level dataset with its own type names
+				"calm":    {ID: "calm", Name: "Calm", Type: "core"},
+				"relaxed": {ID: "relaxed", Name: "Relaxed", Type: "nuance", ParentID: "calm"},
+				"upset":   {ID: "upset", Name: "Upset", Type: "core"},
+			},
+			expectedOutput: []data.Emotion{
+				{ID: "calm", Name: "Calm", Type: "core"},
+				{ID: "upset", Name: "Upset", Type: "core"},
+			},
+		},
+		{
+			name: "Hidden Emotions Are Skipped",
+			inputEmotions: map[string]data.Emotion{
+				"joy":  emotionJoy,
+				"fear": {ID: "fear", Name: "Fear", Type: "primary", Hidden: true}, // Hidden by a user overlay
+			},
+			expectedOutput: []data.Emotion{emotionJoy},
+		},
 		{
 			name:           "Edge Case - Empty Input Map",
 			inputEmotions:  map[string]data.Emotion{}, // Empty map
@@ -95,12 +133,12 @@ func TestGetPrimaryEmotions(t *testing.T) {
 		// Run each test case as a sub-test
 		t.Run(tc.name, func(t *testing.T) {
 			// Call the function under test
-			actualOutput := core.GetPrimaryEmotions(tc.inputEmotions)
+			actualOutput := core.GetRootEmotions(tc.inputEmotions)
 
 			// --- Assertions ---
 			// Check if the actual output matches the expected output.
 			// assert.Equal checks for equality of type, length, capacity, and element values in order.
-			// This works perfectly because GetPrimaryEmotions guarantees a sorted slice.
+			// This works perfectly because GetRootEmotions guarantees dataset order.
 			assert.Equal(t, tc.expectedOutput, actualOutput)
 
 			// Optional: Add a specific check for length if needed, though assert.Equal covers it.
@@ -165,13 +203,13 @@ func TestGetChildrenOf(t *testing.T) {
 		name             string                  // Name of the test case
 		parentID         string                  // Input: Parent ID to find children for
 		inputAllEmotions map[string]data.Emotion // Input: Map of all emotions
-		expectedOutput   []data.Emotion          // Expected slice of direct children (sorted by Name)
+		expectedOutput   []data.Emotion          // Expected slice of direct children (in dataset order)
 	}{
 		{
 			name:             "Parent with multiple children (Joy)",
 			parentID:         "joy",
 			inputAllEmotions: allTestEmotions,
-			// Expected: Children of 'joy', sorted alphabetically by name
+			// Expected: Children of 'joy' in dataset order (by name, as they have no positions)
 			expectedOutput: []data.Emotion{
 				emotionContentment, // C
 				emotionOptimism,    // O
@@ -182,12 +220,27 @@ func TestGetChildrenOf(t *testing.T) {
 			name:             "Parent with multiple children (Sadness)",
 			parentID:         "sadness",
 			inputAllEmotions: allTestEmotions,
-			// Expected: Children of 'sadness', sorted alphabetically by name
+			// Expected: Children of 'sadness' in dataset order (by name, as they have no positions)
 			expectedOutput: []data.Emotion{
 				emotionDisappointment, // D
 				emotionGrief,          // G
 			},
 		},
+		{
+			name:     "Children in dataset order, not by name",
+			parentID: "joy",
+			inputAllEmotions: map[string]data.Emotion{
+				"joy":         emotionJoy,
+				"zest":        {ID: "zest", Name: "Zest", ParentID: "joy", Position: 1},
+				"contentment": {ID: "contentment", Name: "Contentment", ParentID: "joy", Position: 2},
+				"optimism":    {ID: "optimism", Name: "Optimism", ParentID: "joy", Position: 3},
+			},
+			expectedOutput: []data.Emotion{
+				{ID: "zest", Name: "Zest", ParentID: "joy", Position: 1},
+				{ID: "contentment", Name: "Contentment", ParentID: "joy", Position: 2},
+				{ID: "optimism", Name: "Optimism", ParentID: "joy", Position: 3},
+			},
+		},
 		{
 			name:             "Parent with no direct children (Anger)",
 			parentID:         "anger", // Anger exists but has no children in the map
@@ -203,6 +256,16 @@ func TestGetChildrenOf(t *testing.T) {
 				emotionSorrow,
 			},
 		},
+		{
+			name:     "Hidden children are skipped",
+			parentID: "sadness",
+			inputAllEmotions: map[string]data.Emotion{
+				"sadness":        emotionSadness,
+				"grief":          {ID: "grief", Name: "Grief", Type: "secondary", ParentID: "sadness", Hidden: true},
+				"disappointment": emotionDisappointment,
+			},
+			expectedOutput: []data.Emotion{emotionDisappointment},
+		},
 		{
 			name:             "Parent ID does not exist",
 			parentID:         "nonexistent_id",
@@ -239,3 +302,234 @@ func TestGetChildrenOf(t *testing.T) {
 		})
 	}
 }
+
+// TestGetAncestry tests walking from an emotion up to its root.
+func TestGetAncestry(t *testing.T) {
+
+	// --- Test Data Setup ---
+	emotionHappy := data.Emotion{ID: "happy", Name: "Happy", Type: "primary"}
+	emotionPlayful := data.Emotion{ID: "playful", Name: "Playful", Type: "secondary", ParentID: "happy"}
+	emotionAroused := data.Emotion{ID: "aroused", Name: "Aroused", Type: "tertiary", ParentID: "playful"}
+	emotionOrphan := data.Emotion{ID: "orphan", Name: "Orphan", Type: "secondary", ParentID: "missing"}
+	emotionLoopA := data.Emotion{ID: "loop_a", Name: "Loop A", Type: "secondary", ParentID: "loop_b"}
+	emotionLoopB := data.Emotion{ID: "loop_b", Name: "Loop B", Type: "secondary", ParentID: "loop_a"}
+
+	allTestEmotions := map[string]data.Emotion{
+		"happy":   emotionHappy,
+		"playful": emotionPlayful,
+		"aroused": emotionAroused,
+		"orphan":  emotionOrphan,
+		"loop_a":  emotionLoopA,
+		"loop_b":  emotionLoopB,
+	}
+
+	// --- Test Cases ---
+	testCases := []struct {
+		name             string
+		emotionID        string
+		inputAllEmotions map[string]data.Emotion
+		expectedOutput   []data.Emotion // Root first
+	}{
+		{
+			name:             "Tertiary emotion",
+			emotionID:        "aroused",
+			inputAllEmotions: allTestEmotions,
+			expectedOutput:   []data.Emotion{emotionHappy, emotionPlayful, emotionAroused},
+		},
+		{
+			name:             "Root emotion",
+			emotionID:        "happy",
+			inputAllEmotions: allTestEmotions,
+			expectedOutput:   []data.Emotion{emotionHappy},
+		},
+		{
+			name:             "Dangling parent stops the chain",
+			emotionID:        "orphan",
+			inputAllEmotions: allTestEmotions,
+			expectedOutput:   []data.Emotion{emotionOrphan},
+		},
+		{
+			name:             "Parent cycle terminates",
+			emotionID:        "loop_a",
+			inputAllEmotions: allTestEmotions,
+			expectedOutput:   []data.Emotion{emotionLoopB, emotionLoopA},
+		},
+		{
+			name:             "Unknown ID",
+			emotionID:        "nonexistent_id",
+			inputAllEmotions: allTestEmotions,
+			expectedOutput:   []data.Emotion{},
+		},
+		{
+			name:             "Nil input map",
+			emotionID:        "happy",
+			inputAllEmotions: nil,
+			expectedOutput:   []data.Emotion{},
+		},
+	}
+
+	// --- Run Test Cases ---
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualOutput := core.GetAncestry(tc.emotionID, tc.inputAllEmotions)
+			assert.Equal(t, tc.expectedOutput, actualOutput)
+		})
+	}
+}
+
+// TestDepth tests depth and level counting on hierarchies of different depths.
+func TestDepth(t *testing.T) {
+	// A five-level chain plus a second, single-level root
+	fiveLevels := map[string]data.Emotion{
+		"l0":    {ID: "l0", Name: "Level 0"},
+		"l1":    {ID: "l1", Name: "Level 1", ParentID: "l0"},
+		"l2":    {ID: "l2", Name: "Level 2", ParentID: "l1"},
+		"l3":    {ID: "l3", Name: "Level 3", ParentID: "l2"},
+		"l4":    {ID: "l4", Name: "Level 4", ParentID: "l3"},
+		"alone": {ID: "alone", Name: "Alone"},
+	}
+
+	assert.Equal(t, 0, core.Depth("l0", fiveLevels))
+	assert.Equal(t, 4, core.Depth("l4", fiveLevels))
+	assert.Equal(t, 0, core.Depth("alone", fiveLevels))
+	assert.Equal(t, -1, core.Depth("missing", fiveLevels))
+	assert.Equal(t, 5, core.LevelCount(fiveLevels))
+
+	twoLevels := map[string]data.Emotion{
+		"calm":    {ID: "calm", Name: "Calm"},
+		"relaxed": {ID: "relaxed", Name: "Relaxed", ParentID: "calm"},
+	}
+	assert.Equal(t, 2, core.LevelCount(twoLevels))
+	assert.Equal(t, 0, core.LevelCount(nil))
+
+	// A parent cycle (a malformed custom dataset) with a branch hanging off it
+	cyclic := map[string]data.Emotion{
+		"a": {ID: "a", Name: "A", ParentID: "c"},
+		"b": {ID: "b", Name: "B", ParentID: "a"},
+		"c": {ID: "c", Name: "C", ParentID: "b"},
+		"d": {ID: "d", Name: "D", ParentID: "a"},
+	}
+	for range 20 { // Where a cycle is cut used to depend on map order
+		for id := range cyclic {
+			assert.LessOrEqual(t, core.Depth(id, cyclic)+1, core.LevelCount(cyclic), "levels cover the ancestry of %s", id)
+		}
+	}
+	assert.Nil(t, core.ResolvePath([]string{"b", "c", "a", "b", "c", "a", "d"}, cyclic), "paths round a cycle are rejected")
+}
+
+// dagEmotions is a hierarchy where "overwhelmed" belongs to both Fear and Sad.
+func dagEmotions() map[string]data.Emotion {
+	return map[string]data.Emotion{
+		"fear":        {ID: "fear", Name: "Fear"},
+		"sad":         {ID: "sad", Name: "Sad"},
+		"scared":      {ID: "scared", Name: "Scared", ParentID: "fear"},
+		"overwhelmed": {ID: "overwhelmed", Name: "Overwhelmed", ParentID: "scared", ParentIDs: []string{"sad"}},
+		"drowning":    {ID: "drowning", Name: "Drowning", ParentID: "overwhelmed"},
+	}
+}
+
+// TestMultipleParents tests traversal of a hierarchy with several parents.
+func TestMultipleParents(t *testing.T) {
+	emotions := dagEmotions()
+
+	roots := core.GetRootEmotions(emotions)
+	assert.Equal(t, []string{"fear", "sad"}, core.PathIDs(roots), "Emotions with any parent are not roots")
+	assert.Equal(t, []string{"overwhelmed"}, core.PathIDs(core.GetChildrenOf("scared", emotions)))
+	assert.Equal(t, []string{"overwhelmed"}, core.PathIDs(core.GetChildrenOf("sad", emotions)))
+
+	// Single answers follow the primary parent
+	assert.Equal(t, []string{"fear", "scared", "overwhelmed", "drowning"}, core.PathIDs(core.GetAncestry("drowning", emotions)))
+	assert.Equal(t, 3, core.Depth("drowning", emotions))
+	assert.Equal(t, 4, core.LevelCount(emotions))
+
+	// Reachable twice from Fear (directly and through Scared), listed once
+	overwhelmed := emotions["overwhelmed"]
+	overwhelmed.ParentIDs = append(overwhelmed.ParentIDs, "fear")
+	emotions["overwhelmed"] = overwhelmed
+	assert.Equal(t, []string{"fear", "overwhelmed", "scared", "drowning"}, core.Subtree("fear", emotions))
+}
+
+// TestResolvePath tests validating a path through the hierarchy.
+func TestResolvePath(t *testing.T) {
+	emotions := dagEmotions()
+	testCases := []struct {
+		name string
+		path []string
+		want []string // nil: path rejected
+	}{
+		{"Primary route", []string{"fear", "scared", "overwhelmed"}, []string{"fear", "scared", "overwhelmed"}},
+		{"Second parent", []string{"sad", "overwhelmed", "drowning"}, []string{"sad", "overwhelmed", "drowning"}},
+		{"Root only", []string{"sad"}, []string{"sad"}},
+		{"Empty", nil, nil},
+		{"Not from a root", []string{"scared", "overwhelmed"}, nil},
+		{"Skips a level", []string{"fear", "overwhelmed"}, nil},
+		{"Unknown emotion", []string{"fear", "gone"}, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			chain := core.ResolvePath(tc.path, emotions)
+			if tc.want == nil {
+				assert.Nil(t, chain)
+			} else {
+				assert.Equal(t, tc.want, core.PathIDs(chain))
+			}
+		})
+	}
+
+	// AncestryAlong keeps a valid route and falls back to the primary one
+	assert.Equal(t, []string{"sad", "overwhelmed"}, core.PathIDs(core.AncestryAlong("overwhelmed", []string{"sad", "overwhelmed"}, emotions)))
+	assert.Equal(t, []string{"fear", "scared", "overwhelmed"}, core.PathIDs(core.AncestryAlong("overwhelmed", []string{"sad", "scared"}, emotions)))
+	assert.Equal(t, []string{"fear", "scared", "overwhelmed"}, core.PathIDs(core.AncestryAlong("overwhelmed", nil, emotions)))
+}
+
+// TestNearestCommonAncestor tests finding where emotions' families meet.
+func TestNearestCommonAncestor(t *testing.T) {
+	emotions := dagEmotions()
+	emotions["worried"] = data.Emotion{ID: "worried", Name: "Worried", ParentID: "scared"}
+	emotions["lonely"] = data.Emotion{ID: "lonely", Name: "Lonely", ParentID: "sad"}
+	emotions["happy"] = data.Emotion{ID: "happy", Name: "Happy"}
+
+	testCases := []struct {
+		name string
+		ids  []string
+		want string // "": none
+	}{
+		{"Siblings", []string{"overwhelmed", "worried"}, "scared"},
+		{"Ancestor of the other", []string{"scared", "drowning"}, "scared"},
+		{"Same emotion", []string{"worried", "worried"}, "worried"},
+		{"Single emotion", []string{"worried"}, "worried"},
+		{"Through a second parent", []string{"drowning", "lonely"}, "sad"},
+		{"Three emotions", []string{"drowning", "worried", "scared"}, "scared"},
+		{"Different families", []string{"worried", "happy"}, ""},
+		{"Unknown emotion", []string{"worried", "gone"}, ""},
+		{"No emotions", nil, ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ancestor, ok := core.NearestCommonAncestor(tc.ids, emotions)
+			if tc.want == "" {
+				assert.False(t, ok)
+				return
+			}
+			if assert.True(t, ok) {
+				assert.Equal(t, tc.want, ancestor.ID)
+			}
+		})
+	}
+}
+
+// TestDatasetOrder tests that roots and children follow the dataset's
+// ordering rather than their names: explicit Order first, then Position.
+func TestDatasetOrder(t *testing.T) {
+	emotions := map[string]data.Emotion{
+		"joy":      {ID: "joy", Name: "Joy", Position: 0},
+		"trust":    {ID: "trust", Name: "Trust", Position: 1},
+		"anger":    {ID: "anger", Name: "Anger", Position: 2, Order: 1},
+		"serene":   {ID: "serene", Name: "Serene", ParentID: "joy", Position: 3},
+		"ecstatic": {ID: "ecstatic", Name: "Ecstatic", ParentID: "joy", Position: 4},
+		"content":  {ID: "content", Name: "Content", ParentID: "joy", Position: 5},
+	}
+
+	assert.Equal(t, []string{"anger", "joy", "trust"}, core.PathIDs(core.GetRootEmotions(emotions)), "Explicit order comes first")
+	assert.Equal(t, []string{"serene", "ecstatic", "content"}, core.PathIDs(core.GetChildrenOf("joy", emotions)))
+}
@@ -0,0 +1,66 @@
+// internal/core/intensity.go
+package core
+
+import (
+	"sort"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+)
+
+// --- Intensity Ladders ---
+//
+// Siblings with an intensity rank (data.Emotion.Intensity) form a ladder
+// from the mildest word to the strongest, e.g. annoyed < infuriated. The
+// detail and logging views let the user step along it.
+
+// IntensityLadder returns the ladder the emotion at the end of path (IDs
+// from the root down; see AncestryAlong) stands on: its ranked siblings
+// under the parent the path came through, itself included, mildest first.
+// Returns nil if the emotion has no intensity or no ranked sibling to step
+// to. Hidden siblings are left out.
+func IntensityLadder(emotionID string, path []string, allEmotions map[string]data.Emotion) []data.Emotion {
+	emotion, ok := allEmotions[emotionID]
+	if !ok || emotion.Intensity <= 0 {
+		return nil
+	}
+
+	var siblings []data.Emotion
+	if ancestry := AncestryAlong(emotionID, path, allEmotions); len(ancestry) > 1 {
+		siblings = GetChildrenOf(ancestry[len(ancestry)-2].ID, allEmotions)
+	} else {
+		siblings = GetRootEmotions(allEmotions)
+	}
+
+	ladder := make([]data.Emotion, 0, len(siblings))
+	for _, sibling := range siblings {
+		if sibling.Intensity > 0 {
+			ladder = append(ladder, sibling)
+		}
+	}
+	if len(ladder) < 2 {
+		return nil
+	}
+	// The siblings are in dataset order, which settles ties
+	sort.SliceStable(ladder, func(i, j int) bool {
+		return ladder[i].Intensity < ladder[j].Intensity
+	})
+	return ladder
+}
+
+// LadderRung returns the index of emotionID on ladder, or -1 if it isn't on it.
+func LadderRung(ladder []data.Emotion, emotionID string) int {
+	for i, emotion := range ladder {
+		if emotion.ID == emotionID {
+			return i
+		}
+	}
+	return -1
+}
+
+// LadderPath returns path (IDs from the root down) with its last emotion
+// swapped for a sibling on the same intensity ladder.
+func LadderPath(path []string, siblingID string) []string {
+	swapped := append([]string(nil), path...)
+	swapped[len(swapped)-1] = siblingID
+	return swapped
+}
@@ -0,0 +1,63 @@
+// internal/core/intensity_test.go
+package core_test
+
+import (
+	"testing"
+
+	core "github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/stretchr/testify/assert"
+)
+
+// ladderEmotions has one ladder under Angry (declared strongest first) and a
+// multi-parent emotion ranked only on one of its parents' ladders.
+func ladderEmotions() map[string]data.Emotion {
+	return map[string]data.Emotion{
+		"angry":      {ID: "angry", Name: "Angry"},
+		"sad":        {ID: "sad", Name: "Sad"},
+		"furious":    {ID: "furious", Name: "Furious", ParentID: "angry", Intensity: 3, Position: 0},
+		"annoyed":    {ID: "annoyed", Name: "Annoyed", ParentID: "angry", Intensity: 1, Position: 1},
+		"frustrated": {ID: "frustrated", Name: "Frustrated", ParentID: "angry", ParentIDs: []string{"sad"}, Intensity: 2, Position: 2},
+		"bitter":     {ID: "bitter", Name: "Bitter", ParentID: "angry", Position: 3}, // Not ranked
+		"seething":   {ID: "seething", Name: "Seething", ParentID: "angry", Intensity: 4, Hidden: true},
+		"gloomy":     {ID: "gloomy", Name: "Gloomy", ParentID: "sad"},
+	}
+}
+
+// TestIntensityLadder tests building the ladder an emotion stands on.
+func TestIntensityLadder(t *testing.T) {
+	emotions := ladderEmotions()
+	testCases := []struct {
+		name string
+		id   string
+		path []string
+		want []string // nil: no ladder
+	}{
+		{"Mildest first", "furious", nil, []string{"annoyed", "frustrated", "furious"}},
+		{"Through the primary parent", "frustrated", []string{"angry", "frustrated"}, []string{"annoyed", "frustrated", "furious"}},
+		{"No ranked sibling under the other parent", "frustrated", []string{"sad", "frustrated"}, nil},
+		{"Unranked emotion", "bitter", nil, nil},
+		{"Unknown emotion", "gone", nil, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ladder := core.IntensityLadder(tc.id, tc.path, emotions)
+			if tc.want == nil {
+				assert.Nil(t, ladder)
+			} else {
+				assert.Equal(t, tc.want, core.PathIDs(ladder))
+			}
+		})
+	}
+
+	ladder := core.IntensityLadder("annoyed", nil, emotions)
+	assert.Equal(t, 2, core.LadderRung(ladder, "furious"))
+	assert.Equal(t, -1, core.LadderRung(ladder, "bitter"))
+}
+
+// TestLadderPath tests stepping a path to a sibling on its ladder.
+func TestLadderPath(t *testing.T) {
+	path := []string{"angry", "annoyed"}
+	assert.Equal(t, []string{"angry", "furious"}, core.LadderPath(path, "furious"))
+	assert.Equal(t, []string{"angry", "annoyed"}, path, "the original path is left alone")
+}
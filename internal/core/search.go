@@ -0,0 +1,64 @@
+package core
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+)
+
+// SearchEmotions returns the emotions whose names contain the query,
+// ignoring case and surrounding whitespace. Both the default name and any
+// localized names are searched.
+// Names that start with the query are listed first; within each group the
+// results are sorted alphabetically by name.
+// Emotions hidden by a user overlay are never returned.
+// Returns an empty slice for a blank query or an empty map.
+func SearchEmotions(query string, allEmotions map[string]data.Emotion) []data.Emotion {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" || len(allEmotions) == 0 {
+		return []data.Emotion{}
+	}
+
+	matches := make([]data.Emotion, 0)
+	isPrefix := make(map[string]bool) // Emotion ID -> some name starts with the query
+	for _, emotion := range allEmotions {
+		if emotion.Hidden {
+			continue
+		}
+		matched := false
+		for _, name := range searchableNames(emotion) {
+			name = strings.ToLower(name)
+			if strings.HasPrefix(name, query) {
+				isPrefix[emotion.ID] = true
+			}
+			if strings.Contains(name, query) {
+				matched = true
+			}
+		}
+		if matched {
+			matches = append(matches, emotion)
+		}
+	}
+
+	// Prefix matches first, then alphabetical for a stable, predictable order
+	sort.Slice(matches, func(i, j int) bool {
+		iPrefix, jPrefix := isPrefix[matches[i].ID], isPrefix[matches[j].ID]
+		if iPrefix != jPrefix {
+			return iPrefix
+		}
+		return matches[i].Name < matches[j].Name
+	})
+
+	return matches
+}
+
+// searchableNames lists the default name followed by every localized name.
+func searchableNames(emotion data.Emotion) []string {
+	names := make([]string, 0, 1+len(emotion.Names))
+	names = append(names, emotion.Name)
+	for _, name := range emotion.Names {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,88 @@
+package core_test
+
+import (
+	"testing"
+
+	core "github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSearchEmotions tests name matching and result ordering of SearchEmotions.
+func TestSearchEmotions(t *testing.T) {
+	// --- Test Data Setup ---
+	emotionAngry := data.Emotion{ID: "angry", Name: "Angry", Type: "primary", Names: map[string]string{"es": "Enojado"}}
+	emotionFrustrated := data.Emotion{ID: "frustrated", Name: "Frustrated", Type: "secondary", ParentID: "angry"}
+	emotionInfuriated := data.Emotion{ID: "infuriated", Name: "Infuriated", Type: "tertiary", ParentID: "frustrated"}
+	emotionFurious := data.Emotion{ID: "furious", Name: "Furious", Type: "tertiary", ParentID: "angry"}
+
+	allTestEmotions := map[string]data.Emotion{
+		"angry":      emotionAngry,
+		"frustrated": emotionFrustrated,
+		"infuriated": emotionInfuriated,
+		"furious":    emotionFurious,
+	}
+
+	// --- Test Cases ---
+	testCases := []struct {
+		name           string
+		query          string
+		inputEmotions  map[string]data.Emotion
+		expectedOutput []data.Emotion
+	}{
+		{
+			name:          "Prefix matches come before substring matches",
+			query:         "fu",
+			inputEmotions: allTestEmotions,
+			// Furious starts with "fu"; Infuriated only contains it
+			expectedOutput: []data.Emotion{emotionFurious, emotionInfuriated},
+		},
+		{
+			name:           "Case and whitespace are ignored",
+			query:          "  ANG ",
+			inputEmotions:  allTestEmotions,
+			expectedOutput: []data.Emotion{emotionAngry},
+		},
+		{
+			name:           "Localized names are searched",
+			query:          "enoj",
+			inputEmotions:  allTestEmotions,
+			expectedOutput: []data.Emotion{emotionAngry},
+		},
+		{
+			name:  "Hidden emotions are not found",
+			query: "fu",
+			inputEmotions: map[string]data.Emotion{
+				"furious":    {ID: "furious", Name: "Furious", Hidden: true},
+				"infuriated": emotionInfuriated,
+			},
+			expectedOutput: []data.Emotion{emotionInfuriated},
+		},
+		{
+			name:           "Blank query returns nothing",
+			query:          "   ",
+			inputEmotions:  allTestEmotions,
+			expectedOutput: []data.Emotion{},
+		},
+		{
+			name:           "No matches",
+			query:          "calm",
+			inputEmotions:  allTestEmotions,
+			expectedOutput: []data.Emotion{},
+		},
+		{
+			name:           "Nil input map",
+			query:          "fu",
+			inputEmotions:  nil,
+			expectedOutput: []data.Emotion{},
+		},
+	}
+
+	// --- Run Test Cases ---
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualOutput := core.SearchEmotions(tc.query, tc.inputEmotions)
+			assert.Equal(t, tc.expectedOutput, actualOutput)
+		})
+	}
+}
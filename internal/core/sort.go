@@ -0,0 +1,91 @@
+// internal/core/sort.go
+package core
+
+import (
+	"sort"
+	"time"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+)
+
+// --- Sort Modes ---
+//
+// Lists of emotions (the primary grid, each level's children) come back from
+// the hierarchy functions in dataset order, which keeps the wheel order the
+// dataset author chose. The user can re-sort them with one of these modes.
+
+// SortMode selects how lists of emotions are ordered.
+// The values are persisted in the settings file.
+type SortMode string
+
+const (
+	SortDataset      SortMode = "dataset"      // As declared in the dataset (the default)
+	SortAlphabetical SortMode = "alphabetical" // By name
+	SortMostUsed     SortMode = "most_used"    // Most logged first
+	SortRecent       SortMode = "recent"       // Most recently logged first
+	SortIntensity    SortMode = "intensity"    // Mildest first; emotions without an intensity last
+)
+
+// SortModes lists every sort mode in menu order.
+var SortModes = []SortMode{SortDataset, SortAlphabetical, SortMostUsed, SortRecent, SortIntensity}
+
+// Valid reports whether m is one of SortModes. The empty mode is not valid;
+// callers treat it as SortDataset.
+func (m SortMode) Valid() bool {
+	for _, mode := range SortModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// UsageStats records how the user has logged each emotion, for the
+// most-used and recently used sorts. See analytics.Usage.
+type UsageStats struct {
+	Counts   map[string]int       // Emotion ID -> number of entries
+	LastUsed map[string]time.Time // Emotion ID -> time of the latest entry
+}
+
+// SortEmotions returns a sorted copy of emotions. Emotions the mode can't
+// tell apart (e.g. never used, or without an intensity) keep dataset order
+// among themselves. Unknown modes sort in dataset order.
+func SortEmotions(emotions []data.Emotion, mode SortMode, usage UsageStats) []data.Emotion {
+	sorted := make([]data.Emotion, len(emotions))
+	copy(sorted, emotions)
+
+	var less func(a, b data.Emotion) (less, decided bool)
+	switch mode {
+	case SortAlphabetical:
+		less = func(a, b data.Emotion) (bool, bool) {
+			return a.Name < b.Name, a.Name != b.Name
+		}
+	case SortMostUsed:
+		less = func(a, b data.Emotion) (bool, bool) {
+			countA, countB := usage.Counts[a.ID], usage.Counts[b.ID]
+			return countA > countB, countA != countB
+		}
+	case SortRecent:
+		less = func(a, b data.Emotion) (bool, bool) {
+			lastA, lastB := usage.LastUsed[a.ID], usage.LastUsed[b.ID]
+			return lastA.After(lastB), !lastA.Equal(lastB)
+		}
+	case SortIntensity:
+		less = func(a, b data.Emotion) (bool, bool) {
+			if (a.Intensity > 0) != (b.Intensity > 0) {
+				return a.Intensity > 0, true
+			}
+			return a.Intensity < b.Intensity, a.Intensity != b.Intensity
+		}
+	default:
+		less = func(a, b data.Emotion) (bool, bool) { return false, false }
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if result, decided := less(sorted[i], sorted[j]); decided {
+			return result
+		}
+		return data.LessInDatasetOrder(sorted[i], sorted[j])
+	})
+	return sorted
+}
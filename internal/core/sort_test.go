@@ -0,0 +1,49 @@
+// internal/core/sort_test.go
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	core "github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSortEmotions tests each sort mode on one list of siblings declared out
+// of alphabetical order.
+func TestSortEmotions(t *testing.T) {
+	emotions := []data.Emotion{
+		{ID: "furious", Name: "Furious", Position: 0, Intensity: 3},
+		{ID: "annoyed", Name: "Annoyed", Position: 1, Intensity: 1},
+		{ID: "bitter", Name: "Bitter", Position: 2},
+		{ID: "frustrated", Name: "Frustrated", Position: 3, Intensity: 2},
+	}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	usage := core.UsageStats{
+		Counts:   map[string]int{"bitter": 4, "annoyed": 4, "frustrated": 1},
+		LastUsed: map[string]time.Time{"frustrated": now, "bitter": now.Add(-time.Hour)},
+	}
+
+	testCases := []struct {
+		name string
+		mode core.SortMode
+		want []string
+	}{
+		{"Dataset order", core.SortDataset, []string{"furious", "annoyed", "bitter", "frustrated"}},
+		{"Alphabetical", core.SortAlphabetical, []string{"annoyed", "bitter", "frustrated", "furious"}},
+		{"Most used, ties in dataset order", core.SortMostUsed, []string{"annoyed", "bitter", "frustrated", "furious"}},
+		{"Recently used, unused last", core.SortRecent, []string{"frustrated", "bitter", "furious", "annoyed"}},
+		{"Intensity, unrated last", core.SortIntensity, []string{"annoyed", "frustrated", "furious", "bitter"}},
+		{"Unknown mode falls back to dataset order", core.SortMode("bogus"), []string{"furious", "annoyed", "bitter", "frustrated"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, core.PathIDs(core.SortEmotions(emotions, tc.mode, usage)))
+		})
+	}
+
+	assert.Equal(t, "furious", emotions[0].ID, "The input is left untouched")
+	assert.True(t, core.SortRecent.Valid())
+	assert.False(t, core.SortMode("").Valid())
+}
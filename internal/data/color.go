@@ -0,0 +1,56 @@
+// internal/data/color.go
+package data
+
+import (
+	"fmt"
+	"image/color"
+)
+
+// ParseHexColor parses a dataset color such as "#F29727" or the shorthand
+// "#F93" (the leading '#' is optional) into an opaque color.
+func ParseHexColor(s string) (color.Color, error) {
+	var r, g, b uint8
+	var format string
+
+	if len(s) == 0 {
+		return color.Black, fmt.Errorf("empty color string")
+	}
+
+	if s[0] == '#' {
+		s = s[1:] // Remove leading '#'
+	}
+
+	switch len(s) {
+	case 6: // RRGGBB
+		format = "%02x%02x%02x"
+	case 3: // RGB (shorthand) - Expand to RRGGBB
+		format = "%1x%1x%1x" // Read single hex digits
+		_, err := fmt.Sscanf(s, format, &r, &g, &b)
+		if err != nil {
+			return color.Black, fmt.Errorf("invalid shorthand hex color format: %w", err)
+		}
+		// Expand: e.g., F -> FF, A -> AA
+		r = r*16 + r
+		g = g*16 + g
+		b = b*16 + b
+		// Now format as RRGGBB for consistency in return type
+		format = "%02x%02x%02x"
+		s = fmt.Sprintf("%02x%02x%02x", r, g, b) // Recreate the 6-digit string
+	default:
+		return color.Black, fmt.Errorf("invalid hex color string length: %d", len(s))
+	}
+
+	// Scan the 6-digit hex string
+	_, err := fmt.Sscanf(s, format, &r, &g, &b)
+	if err != nil {
+		return color.Black, fmt.Errorf("invalid hex color format: %w", err)
+	}
+
+	return color.NRGBA{R: r, G: g, B: b, A: 255}, nil // Return NRGBA (non-alpha-premultiplied) or RGBA
+}
+
+// FormatHexColor renders c as a dataset color string ("#RRGGBB").
+func FormatHexColor(c color.Color) string {
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02X%02X%02X", nrgba.R, nrgba.G, nrgba.B)
+}
@@ -0,0 +1,709 @@
+// internal/data/convert.go
+package data
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is a file format a dataset can be read from and written to.
+type Format string
+
+const (
+	FormatJSON Format = "json" // The shape of the embedded emotions.json
+	FormatCSV  Format = "csv"  // One row per emotion, for spreadsheets (see EncodeCSV)
+	FormatYAML Format = "yaml"
+	FormatTOML Format = "toml"
+)
+
+// Formats lists every supported format.
+var Formats = []Format{FormatJSON, FormatCSV, FormatYAML, FormatTOML}
+
+// FileExtensions lists the extensions of dataset files, e.g. for file dialogs.
+var FileExtensions = []string{".json", ".csv", ".yaml", ".yml", ".toml"}
+
+// ParseFormat converts a format name ("json", "csv", "yaml"/"yml", "toml").
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "json":
+		return FormatJSON, nil
+	case "csv":
+		return FormatCSV, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	case "toml":
+		return FormatTOML, nil
+	}
+	return "", fmt.Errorf("unknown dataset format '%s' (want json, csv, yaml or toml)", name)
+}
+
+// FormatFromPath picks the format from a file's extension.
+func FormatFromPath(path string) (Format, error) {
+	ext := filepath.Ext(path)
+	if ext == "" {
+		return "", fmt.Errorf("cannot tell the dataset format of '%s' without an extension", path)
+	}
+	return ParseFormat(ext)
+}
+
+// Decode reads a dataset in the given format. Like the embedded dataset, it
+// must define at least one emotion. Emotions get their Position from the
+// order the file declares them in.
+func Decode(r io.Reader, format Format) (EmotionData, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return EmotionData{}, err
+	}
+
+	var emotionData EmotionData
+	var declared []string // Emotion IDs in file order
+	switch format {
+	case FormatJSON:
+		if err = json.Unmarshal(raw, &emotionData); err == nil {
+			declared = jsonEmotionOrder(raw)
+		}
+	case FormatCSV:
+		emotionData, err = DecodeCSV(bytes.NewReader(raw)) // Positions follow the row order
+	case FormatYAML:
+		var document yaml.Node
+		if err = yaml.Unmarshal(raw, &document); err == nil {
+			err = document.Decode(&emotionData)
+			declared = yamlEmotionOrder(&document)
+		}
+	case FormatTOML:
+		var meta toml.MetaData
+		if meta, err = toml.Decode(string(raw), &emotionData); err == nil {
+			declared = tomlEmotionOrder(meta)
+		}
+	default:
+		err = fmt.Errorf("unsupported dataset format '%s'", format)
+	}
+	if err != nil {
+		return EmotionData{}, err
+	}
+	if len(emotionData.Emotions) == 0 {
+		return EmotionData{}, fmt.Errorf("dataset defines no emotions")
+	}
+	if format != FormatCSV {
+		for i, id := range declared {
+			if emotion, ok := emotionData.Emotions[id]; ok {
+				emotion.Position = i
+				emotionData.Emotions[id] = emotion
+			}
+		}
+	}
+	normalizePositions(emotionData.Emotions)
+	return emotionData, nil
+}
+
+// Encode writes a dataset in the given format, emotions in Position order.
+// Every format round-trips losslessly through Decode.
+func Encode(w io.Writer, emotionData EmotionData, format Format) error {
+	switch format {
+	case FormatJSON:
+		raw, err := json.MarshalIndent(newOrderedDataset(emotionData), "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(raw, '\n'))
+		return err
+	case FormatCSV:
+		return EncodeCSV(w, emotionData)
+	case FormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(newOrderedDataset(emotionData)); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case FormatTOML:
+		return encodeTOML(w, emotionData)
+	}
+	return fmt.Errorf("unsupported dataset format '%s'", format)
+}
+
+// --- Emotion Order ---
+//
+// Emotions are a map, so the order a file lists them in (wheel order,
+// intensity order...) is kept in Emotion.Position: read from the file by
+// the helpers below and written back by the encoders.
+
+// normalizePositions renumbers positions in hierarchy order (see
+// hierarchyOrder), so the same sibling order gives the same positions
+// whatever the file layout, e.g. levels listed one after another in JSON or
+// depth-first in CSV.
+func normalizePositions(emotions map[string]Emotion) {
+	for i, id := range hierarchyOrder(emotions) {
+		emotion := emotions[id]
+		emotion.Position = i
+		emotions[id] = emotion
+	}
+}
+
+// positionOrder lists emotion IDs by Position (then ID), the order the
+// encoders write them in.
+func positionOrder(emotions map[string]Emotion) []string {
+	ids := make([]string, 0, len(emotions))
+	for id := range emotions {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := emotions[ids[i]], emotions[ids[j]]
+		if a.Position != b.Position {
+			return a.Position < b.Position
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+// jsonEmotionOrder returns the keys of the top-level "emotions" object in
+// file order, or nil if they can't be read (the dataset then falls back to
+// name order).
+func jsonEmotionOrder(raw []byte) []string {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil
+	}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return nil
+		}
+		if key != "emotions" {
+			var skipped json.RawMessage
+			if decoder.Decode(&skipped) != nil {
+				return nil
+			}
+			continue
+		}
+		if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+			return nil
+		}
+		var ids []string
+		for decoder.More() {
+			id, err := decoder.Token()
+			if err != nil {
+				return nil
+			}
+			var skipped json.RawMessage
+			if decoder.Decode(&skipped) != nil {
+				return nil
+			}
+			ids = append(ids, fmt.Sprint(id))
+		}
+		return ids
+	}
+	return nil
+}
+
+// yamlEmotionOrder returns the keys of the top-level "emotions" mapping in
+// document order.
+func yamlEmotionOrder(document *yaml.Node) []string {
+	root := document
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	emotions := mappingValue(root, "emotions")
+	if emotions == nil || emotions.Kind != yaml.MappingNode {
+		return nil
+	}
+	ids := make([]string, 0, len(emotions.Content)/2)
+	for i := 0; i+1 < len(emotions.Content); i += 2 {
+		ids = append(ids, emotions.Content[i].Value)
+	}
+	return ids
+}
+
+// mappingValue returns the value node for key in a YAML mapping, or nil.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// tomlEmotionOrder returns the emotion tables ([emotions.<id>]) in document order.
+func tomlEmotionOrder(meta toml.MetaData) []string {
+	var ids []string
+	for _, key := range meta.Keys() {
+		if len(key) == 2 && key[0] == "emotions" {
+			ids = append(ids, key[1])
+		}
+	}
+	return ids
+}
+
+// orderedDataset is EmotionData with the emotions written in Position
+// order, for the JSON and YAML encoders.
+type orderedDataset struct {
+	Metadata     Metadata               `json:"metadata" yaml:"metadata"`
+	EmotionTypes map[string]EmotionType `json:"emotionTypes" yaml:"emotionTypes"`
+	Emotions     orderedEmotions        `json:"emotions" yaml:"emotions"`
+	Aliases      []IDAlias              `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+}
+
+func newOrderedDataset(emotionData EmotionData) orderedDataset {
+	return orderedDataset{
+		Metadata:     emotionData.Metadata,
+		EmotionTypes: emotionData.EmotionTypes,
+		Emotions:     orderedEmotions(emotionData.Emotions),
+		Aliases:      emotionData.Aliases,
+	}
+}
+
+// orderedEmotions marshals an emotion map in Position order instead of the
+// sorted key order encoding/json and yaml.v3 use for maps.
+type orderedEmotions map[string]Emotion
+
+// MarshalJSON writes the emotions as a JSON object in Position order.
+func (o orderedEmotions) MarshalJSON() ([]byte, error) {
+	if o == nil {
+		return []byte("null"), nil
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, id := range positionOrder(o) {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(id)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(o[id])
+		if err != nil {
+			return nil, fmt.Errorf("marshalling emotion '%s': %w", id, err)
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// MarshalYAML writes the emotions as a YAML mapping in Position order.
+func (o orderedEmotions) MarshalYAML() (any, error) {
+	mapping := &yaml.Node{Kind: yaml.MappingNode}
+	for _, id := range positionOrder(o) {
+		var value yaml.Node
+		if err := value.Encode(o[id]); err != nil {
+			return nil, fmt.Errorf("marshalling emotion '%s': %w", id, err)
+		}
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: id}, &value)
+	}
+	return mapping, nil
+}
+
+// encodeTOML writes a dataset as TOML with the [emotions.<id>] tables in
+// Position order. The TOML encoder sorts map keys, so each emotion is
+// encoded on its own and the repeated [emotions] header is dropped.
+func encodeTOML(w io.Writer, emotionData EmotionData) error {
+	emotions := emotionData.Emotions
+	emotionData.Emotions = nil // Encoded below
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(emotionData); err != nil {
+		return err
+	}
+	const header = "[emotions]\n"
+	buf.WriteString("\n" + header)
+	for _, id := range positionOrder(emotions) {
+		var table bytes.Buffer
+		if err := toml.NewEncoder(&table).Encode(map[string]map[string]Emotion{"emotions": {id: emotions[id]}}); err != nil {
+			return fmt.Errorf("marshalling emotion '%s': %w", id, err)
+		}
+		buf.WriteString(strings.TrimPrefix(table.String(), header))
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// --- CSV ---
+//
+// The CSV shape is made for content writers editing vocabularies in a
+// spreadsheet: one row per emotion, parents before children.
+//
+//	# metadata: {"version":"1.1","source":"Feelings Wheel","description":"..."}
+//	# emotionTypes: {"primary":{"id":"primary","name":"Primary","level":1},...}
+//	# aliases: [{"from":"joy-01","to":"happy","since":"1.1"}]
+//	id,name,type,color,parent,description,name:es,description:es
+//	happy,Happy,primary,#FFD700,,,Feliz,
+//	playful,Playful,secondary,#FFEB3B,happy,,Juguetón,
+//
+// The first five columns are required (in any order); description, parents
+// (further parents, "|"-separated; see Emotion.ParentIDs), valence, arousal,
+// dominance, order, intensity and the per-locale name:<locale> /
+// description:<locale> columns are optional.
+// Rows whose first cell starts with "#" carry the dataset-wide parts as
+// JSON, so nothing is lost; spreadsheets keep them as ordinary cells.
+
+// csvColumns are the required CSV columns, in the order EncodeCSV writes them.
+var csvColumns = []string{"id", "name", "type", "color", "parent"}
+
+// csvParentSeparator separates the IDs in the optional parents column.
+const csvParentSeparator = "|"
+
+// CSV directives: "# <key>: <json>" rows for the parts that aren't emotions.
+const (
+	csvMetadata     = "metadata"
+	csvEmotionTypes = "emotionTypes"
+	csvAliases      = "aliases"
+)
+
+// EncodeCSV writes a dataset as CSV (see the format above).
+func EncodeCSV(w io.Writer, emotionData EmotionData) error {
+	writer := csv.NewWriter(w)
+
+	directives := []struct {
+		key   string
+		value any
+		skip  bool
+	}{
+		{csvMetadata, emotionData.Metadata, false},
+		{csvEmotionTypes, emotionData.EmotionTypes, emotionData.EmotionTypes == nil},
+		{csvAliases, emotionData.Aliases, emotionData.Aliases == nil},
+	}
+	for _, directive := range directives {
+		if directive.skip {
+			continue
+		}
+		raw, err := json.Marshal(directive.value)
+		if err != nil {
+			return fmt.Errorf("marshalling %s: %w", directive.key, err)
+		}
+		if err := writer.Write([]string{fmt.Sprintf("# %s: %s", directive.key, raw)}); err != nil {
+			return err
+		}
+	}
+
+	// Translation columns for every locale in use, sorted for stable output
+	nameLocales, descriptionLocales := map[string]bool{}, map[string]bool{}
+	multiParent := false                    // Only write the parents column when it's used
+	usedDimensions := make(map[string]bool) // Likewise for valence, arousal and dominance
+	usedRanks := make(map[string]bool)      // And for order and intensity
+	for _, emotion := range emotionData.Emotions {
+		multiParent = multiParent || len(emotion.ParentIDs) > 0
+		for _, dimension := range emotion.dimensions() {
+			usedDimensions[dimension.name] = usedDimensions[dimension.name] || dimension.value != nil
+		}
+		for _, rank := range emotion.ranks() {
+			usedRanks[rank.name] = usedRanks[rank.name] || *rank.value != 0
+		}
+		for locale := range emotion.Names {
+			nameLocales[locale] = true
+		}
+		for locale := range emotion.Descriptions {
+			descriptionLocales[locale] = true
+		}
+	}
+	header := append(append([]string{}, csvColumns...), "description")
+	if multiParent {
+		header = append(header, "parents")
+	}
+	var dimensionColumns []string
+	for _, dimension := range (Emotion{}).dimensions() {
+		if usedDimensions[dimension.name] {
+			dimensionColumns = append(dimensionColumns, dimension.name)
+		}
+	}
+	header = append(header, dimensionColumns...)
+	for _, rank := range (&Emotion{}).ranks() {
+		if usedRanks[rank.name] {
+			header = append(header, rank.name)
+		}
+	}
+	for _, locale := range sortedKeys(nameLocales) {
+		header = append(header, "name:"+locale)
+	}
+	for _, locale := range sortedKeys(descriptionLocales) {
+		header = append(header, "description:"+locale)
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, id := range hierarchyOrder(emotionData.Emotions) {
+		emotion := emotionData.Emotions[id]
+		row := []string{id, emotion.Name, emotion.Type, emotion.Color, emotion.ParentID, emotion.Description}
+		if multiParent {
+			row = append(row, strings.Join(emotion.ParentIDs, csvParentSeparator))
+		}
+		for _, dimension := range emotion.dimensions() {
+			if !usedDimensions[dimension.name] {
+				continue
+			}
+			cell := ""
+			if dimension.value != nil {
+				cell = strconv.FormatFloat(*dimension.value, 'f', -1, 64)
+			}
+			row = append(row, cell)
+		}
+		for _, rank := range emotion.ranks() {
+			if !usedRanks[rank.name] {
+				continue
+			}
+			cell := ""
+			if *rank.value != 0 {
+				cell = strconv.Itoa(*rank.value)
+			}
+			row = append(row, cell)
+		}
+		for _, column := range header[len(row):] {
+			field, locale, _ := strings.Cut(column, ":")
+			if field == "name" {
+				row = append(row, emotion.Names[locale])
+			} else {
+				row = append(row, emotion.Descriptions[locale])
+			}
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// DecodeCSV reads a dataset written by EncodeCSV or by hand in a spreadsheet.
+// Blank rows are skipped. Files without directives get empty metadata, no
+// emotion types and no aliases.
+func DecodeCSV(r io.Reader) (EmotionData, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // Spreadsheets pad or trim rows freely
+	reader.TrimLeadingSpace = true
+
+	emotionData := EmotionData{Emotions: map[string]Emotion{}}
+	var columns map[string]int // Column name -> index, once the header is read
+	var header []string
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return EmotionData{}, fmt.Errorf("reading CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if isBlankRow(row) {
+			continue
+		}
+		if first := strings.TrimSpace(row[0]); strings.HasPrefix(first, "#") {
+			if err := decodeCSVDirective(first, &emotionData); err != nil {
+				return EmotionData{}, fmt.Errorf("line %d: %w", line, err)
+			}
+			continue
+		}
+
+		if columns == nil {
+			header = row
+			columns = make(map[string]int, len(row))
+			for i, name := range row {
+				columns[strings.TrimSpace(name)] = i
+			}
+			for _, required := range csvColumns {
+				if _, ok := columns[required]; !ok {
+					return EmotionData{}, fmt.Errorf("line %d: CSV header is missing the '%s' column", line, required)
+				}
+			}
+			continue
+		}
+
+		cell := func(i int) string {
+			if i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		emotion := Emotion{
+			ID:       cell(columns["id"]),
+			Name:     cell(columns["name"]),
+			Type:     cell(columns["type"]),
+			Color:    cell(columns["color"]),
+			ParentID: cell(columns["parent"]),
+		}
+		if i, ok := columns["description"]; ok {
+			emotion.Description = cell(i)
+		}
+		if i, ok := columns["parents"]; ok {
+			for _, parentID := range strings.Split(cell(i), csvParentSeparator) {
+				if parentID = strings.TrimSpace(parentID); parentID != "" {
+					emotion.ParentIDs = append(emotion.ParentIDs, parentID)
+				}
+			}
+		}
+		dimensionFields := []struct {
+			name  string
+			field **float64
+		}{{"valence", &emotion.Valence}, {"arousal", &emotion.Arousal}, {"dominance", &emotion.Dominance}}
+		for _, dimension := range dimensionFields {
+			i, ok := columns[dimension.name]
+			if !ok || cell(i) == "" {
+				continue
+			}
+			value, err := strconv.ParseFloat(cell(i), 64)
+			if err != nil {
+				return EmotionData{}, fmt.Errorf("line %d: invalid %s '%s'", line, dimension.name, cell(i))
+			}
+			*dimension.field = &value
+		}
+		for _, rank := range emotion.ranks() {
+			i, ok := columns[rank.name]
+			if !ok || cell(i) == "" {
+				continue
+			}
+			value, err := strconv.Atoi(cell(i))
+			if err != nil {
+				return EmotionData{}, fmt.Errorf("line %d: invalid %s '%s'", line, rank.name, cell(i))
+			}
+			*rank.value = value
+		}
+		for i, name := range header {
+			field, locale, ok := strings.Cut(strings.TrimSpace(name), ":")
+			if !ok || cell(i) == "" {
+				continue
+			}
+			switch field {
+			case "name":
+				emotion.Names = setTranslation(emotion.Names, locale, cell(i))
+			case "description":
+				emotion.Descriptions = setTranslation(emotion.Descriptions, locale, cell(i))
+			}
+		}
+		if emotion.ID == "" {
+			return EmotionData{}, fmt.Errorf("line %d: missing id", line)
+		}
+		if _, dup := emotionData.Emotions[emotion.ID]; dup {
+			return EmotionData{}, fmt.Errorf("line %d: duplicate id '%s'", line, emotion.ID)
+		}
+		emotion.Position = len(emotionData.Emotions) // Row order; see normalizePositions
+		emotionData.Emotions[emotion.ID] = emotion
+	}
+	if columns == nil {
+		return EmotionData{}, fmt.Errorf("CSV has no header row")
+	}
+	normalizePositions(emotionData.Emotions)
+	return emotionData, nil
+}
+
+// decodeCSVDirective applies a "# key: json" row.
+func decodeCSVDirective(cell string, emotionData *EmotionData) error {
+	key, value, ok := strings.Cut(strings.TrimSpace(strings.TrimPrefix(cell, "#")), ":")
+	if !ok {
+		return nil // A plain comment
+	}
+	var target any
+	switch strings.TrimSpace(key) {
+	case csvMetadata:
+		target = &emotionData.Metadata
+	case csvEmotionTypes:
+		target = &emotionData.EmotionTypes
+	case csvAliases:
+		target = &emotionData.Aliases
+	default:
+		return nil // Comments may contain colons too
+	}
+	if err := json.Unmarshal([]byte(value), target); err != nil {
+		return fmt.Errorf("invalid %s: %w", strings.TrimSpace(key), err)
+	}
+	return nil
+}
+
+// isBlankRow reports whether every cell of a CSV row is empty.
+func isBlankRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// setTranslation adds a translation, creating the map on first use.
+func setTranslation(translations map[string]string, locale, text string) map[string]string {
+	if translations == nil {
+		translations = make(map[string]string)
+	}
+	translations[locale] = text
+	return translations
+}
+
+// hierarchyOrder lists emotion IDs depth-first from the roots, children in
+// dataset order (see LessInDatasetOrder), so a spreadsheet reads like the
+// wheel. Emotions with several parents are listed under their primary
+// parent. Emotions not reachable from a root (e.g. parent cycles or
+// dangling parents) follow, sorted by ID.
+func hierarchyOrder(emotions map[string]Emotion) []string {
+	children := make(map[string][]string)
+	for id, emotion := range emotions {
+		children[emotion.PrimaryParent()] = append(children[emotion.PrimaryParent()], id)
+	}
+	for _, ids := range children {
+		sort.Slice(ids, func(i, j int) bool {
+			return LessInDatasetOrder(emotions[ids[i]], emotions[ids[j]])
+		})
+	}
+
+	order := make([]string, 0, len(emotions))
+	visited := make(map[string]bool, len(emotions))
+	var visit func(id string)
+	visit = func(id string) {
+		if visited[id] {
+			return
+		}
+		visited[id] = true
+		order = append(order, id)
+		for _, child := range children[id] {
+			visit(child)
+		}
+	}
+	for _, root := range children[""] {
+		visit(root)
+	}
+
+	rest := make([]string, 0)
+	for id := range emotions {
+		if !visited[id] {
+			rest = append(rest, id)
+		}
+	}
+	sort.Strings(rest)
+	for _, id := range rest {
+		visit(id)
+	}
+	return order
+}
+
+// sortedKeys returns a set's keys in sorted order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// marshalFormat encodes a dataset into memory.
+func marshalFormat(emotionData EmotionData, format Format) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, emotionData, format); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,237 @@
+// internal/data/convert_test.go
+package data
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestFormatsRoundTrip encodes the embedded dataset (plus fields it doesn't
+// use yet) in every format and checks that decoding gives it back unchanged.
+func TestFormatsRoundTrip(t *testing.T) {
+	original := loadBase(t)
+	setEmotion(&original, "happy", func(e *Emotion) {
+		e.Description = "Feeling good, with a comma, and \"quotes\""
+		e.Descriptions = map[string]string{"es": "Sentirse bien\nen dos líneas"}
+	})
+	setEmotion(&original, "bored", func(e *Emotion) {
+		e.ParentIDs = []string{"tired", "happy"}
+		e.Dominance = ptrFloat(-0.25)
+	})
+
+	for _, format := range Formats {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Encode(&buf, original, format); err != nil {
+				t.Fatalf("Encode() error: %v", err)
+			}
+			decoded, err := Decode(&buf, format)
+			if err != nil {
+				t.Fatalf("Decode() error: %v", err)
+			}
+			if !reflect.DeepEqual(original, decoded) {
+				for id, emotion := range original.Emotions {
+					if !reflect.DeepEqual(emotion, decoded.Emotions[id]) {
+						t.Errorf("emotion %q changed:\n got  %+v\n want %+v", id, decoded.Emotions[id], emotion)
+					}
+				}
+				t.Fatalf("round trip changed the dataset (metadata %+v, %d types, %d aliases)",
+					decoded.Metadata, len(decoded.EmotionTypes), len(decoded.Aliases))
+			}
+		})
+	}
+}
+
+func TestDecodeCSV(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		want    map[string]Emotion
+		wantErr string
+	}{
+		{
+			name: "Spreadsheet export: columns reordered, padded and blank rows",
+			input: "parent,id,name,color,type,name:es,notes\n" +
+				",happy,Happy,#FFD700,primary,Feliz,\n" +
+				",,,,,,\n" +
+				"happy,playful, Playful ,,secondary,,ignored column\n",
+			want: map[string]Emotion{
+				"happy":   {ID: "happy", Name: "Happy", Type: "primary", Color: "#FFD700", Names: map[string]string{"es": "Feliz"}},
+				"playful": {ID: "playful", Name: "Playful", Type: "secondary", ParentID: "happy", Position: 1},
+			},
+		},
+		{
+			name:    "Missing column",
+			input:   "id,name,type,parent\nhappy,Happy,primary,\n",
+			wantErr: "missing the 'color' column",
+		},
+		{
+			name:  "Dimensions",
+			input: "id,name,type,color,parent,valence,arousal,dominance\ncalm,Calm,primary,,,0.6,-0.5,\n",
+			want: map[string]Emotion{
+				"calm": {ID: "calm", Name: "Calm", Type: "primary", Valence: ptrFloat(0.6), Arousal: ptrFloat(-0.5)},
+			},
+		},
+		{
+			name:    "Invalid dimension",
+			input:   "id,name,type,color,parent,arousal\ncalm,Calm,primary,,,high\n",
+			wantErr: "line 2: invalid arousal 'high'",
+		},
+		{
+			name:    "Duplicate ID",
+			input:   "id,name,type,color,parent\nhappy,Happy,primary,,\nhappy,Glad,primary,,\n",
+			wantErr: "line 3: duplicate id 'happy'",
+		},
+		{
+			name:    "Broken directive",
+			input:   "# metadata: {not json\nid,name,type,color,parent\n",
+			wantErr: "line 1: invalid metadata",
+		},
+		{
+			name:    "No header",
+			input:   "# just a comment\n",
+			wantErr: "no header row",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := DecodeCSV(strings.NewReader(tc.input))
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeCSV() error: %v", err)
+			}
+			if !reflect.DeepEqual(got.Emotions, tc.want) {
+				t.Errorf("emotions = %+v, want %+v", got.Emotions, tc.want)
+			}
+		})
+	}
+}
+
+func TestEncodeCSVOrder(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodeCSV(&buf, loadBase(t)); err != nil {
+		t.Fatalf("EncodeCSV() error: %v", err)
+	}
+	// Parents come before their children, so the sheet reads like the wheel
+	seen := map[string]bool{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if strings.HasPrefix(line, "\"#") || strings.HasPrefix(line, "id,") {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		if parent := fields[4]; parent != "" && !seen[parent] {
+			t.Fatalf("%s is listed before its parent %s", fields[0], parent)
+		}
+		seen[fields[0]] = true
+	}
+}
+
+// TestDeclarationOrder checks that every format keeps the order emotions
+// are declared in, which is not alphabetical here, through a round trip.
+func TestDeclarationOrder(t *testing.T) {
+	original := EmotionData{Emotions: map[string]Emotion{
+		"joy":      {ID: "joy", Name: "Joy", Position: 0},
+		"serene":   {ID: "serene", Name: "Serene", ParentID: "joy", Position: 1},
+		"ecstatic": {ID: "ecstatic", Name: "Ecstatic", ParentID: "joy", Position: 2},
+		"anger":    {ID: "anger", Name: "Anger", Position: 3},
+		"annoyed":  {ID: "annoyed", Name: "Annoyed", ParentID: "anger", Position: 4},
+	}}
+	want := []string{"joy", "serene", "ecstatic", "anger", "annoyed"}
+
+	for _, format := range Formats {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Encode(&buf, original, format); err != nil {
+				t.Fatalf("Encode() error: %v", err)
+			}
+			encoded := buf.String()
+			decoded, err := Decode(&buf, format)
+			if err != nil {
+				t.Fatalf("Decode() error: %v", err)
+			}
+			if got := positionOrder(decoded.Emotions); !reflect.DeepEqual(got, want) {
+				t.Errorf("decoded order = %v, want %v", got, want)
+			}
+			// The file itself lists them in that order too
+			last := -1
+			for _, id := range want {
+				at := strings.Index(encoded, id)
+				if at < last {
+					t.Errorf("%q is written out of order:\n%s", id, encoded)
+				}
+				last = at
+			}
+		})
+	}
+}
+
+// TestExplicitOrder checks that an "order" field beats declaration order.
+func TestExplicitOrder(t *testing.T) {
+	raw := `{"emotions": {
+		"joy":   {"id": "joy", "name": "Joy"},
+		"anger": {"id": "anger", "name": "Anger", "order": 1},
+		"fear":  {"id": "fear", "name": "Fear"}
+	}}`
+	decoded, err := Decode(strings.NewReader(raw), FormatJSON)
+	if err != nil {
+		t.Fatalf("Decode() error: %v", err)
+	}
+	roots := []Emotion{decoded.Emotions["fear"], decoded.Emotions["joy"], decoded.Emotions["anger"]}
+	sort.Slice(roots, func(i, j int) bool { return LessInDatasetOrder(roots[i], roots[j]) })
+	got := []string{roots[0].ID, roots[1].ID, roots[2].ID}
+	if want := []string{"anger", "joy", "fear"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	testCases := map[string]Format{
+		"words.csv": FormatCSV, "words.YAML": FormatYAML, "words.yml": FormatYAML,
+		"words.toml": FormatTOML, "dir.v2/words.json": FormatJSON,
+	}
+	for path, want := range testCases {
+		if got, err := FormatFromPath(path); err != nil || got != want {
+			t.Errorf("FormatFromPath(%q) = %q, %v; want %q", path, got, err, want)
+		}
+	}
+	for _, path := range []string{"words", "words.xlsx"} {
+		if _, err := FormatFromPath(path); err == nil {
+			t.Errorf("FormatFromPath(%q) succeeded, want an error", path)
+		}
+	}
+}
+
+// TestEmotionsFileFormats checks that dataset files are saved and loaded in
+// the format their extension names.
+func TestEmotionsFileFormats(t *testing.T) {
+	original := loadBase(t)
+	dir := t.TempDir()
+	for _, name := range []string{"words.csv", "words.yaml", "words.toml", "words.dataset"} {
+		path := filepath.Join(dir, name)
+		if err := SaveEmotionsFile(path, original); err != nil {
+			t.Fatalf("SaveEmotionsFile(%s) error: %v", name, err)
+		}
+		loaded, err := LoadEmotionsFile(path)
+		if err != nil {
+			t.Fatalf("LoadEmotionsFile(%s) error: %v", name, err)
+		}
+		if !reflect.DeepEqual(original, loaded) {
+			t.Errorf("%s did not round-trip", name)
+		}
+	}
+	raw, _ := os.ReadFile(filepath.Join(dir, "words.dataset"))
+	if !bytes.HasPrefix(raw, []byte("{")) {
+		t.Errorf("unknown extension was not written as JSON: %.40s", raw)
+	}
+}
@@ -2,9 +2,10 @@
 package data
 
 import (
+	"bytes"
 	"embed" // Required for embedding files
-	"encoding/json"
-	"fmt" // For formatting error messages
+	"fmt"   // For formatting error messages
+	"os"
 )
 
 // Embed the JSON file directly from the current directory.
@@ -15,22 +16,47 @@ var embeddedJSON embed.FS
 
 // LoadEmotions reads and parses the embedded emotions.json file.
 func LoadEmotions() (EmotionData, error) {
-	var emotionData EmotionData
-
 	// Read the file by its base name from the embed FS.
 	bytes, err := embeddedJSON.ReadFile("emotions.json")
 	if err != nil {
 		return EmotionData{}, fmt.Errorf("failed to read embedded file 'emotions.json': %w", err)
 	}
+	return parseEmotions(bytes, "emotions.json")
+}
 
-	err = json.Unmarshal(bytes, &emotionData)
+// LoadEmotionsFile reads and parses a dataset file, e.g. a custom vocabulary
+// chosen in settings. The format follows the extension (see FormatFromPath);
+// files with other extensions are read as JSON, like the embedded emotions.json.
+func LoadEmotionsFile(path string) (EmotionData, error) {
+	bytes, err := os.ReadFile(path)
 	if err != nil {
-		return EmotionData{}, fmt.Errorf("failed to unmarshal emotions.json: %w", err)
+		return EmotionData{}, fmt.Errorf("failed to read dataset file '%s': %w", path, err)
 	}
+	return parseEmotionsAs(bytes, path, fileFormat(path))
+}
+
+// parseEmotions unmarshals dataset JSON. source names the file in errors.
+func parseEmotions(bytes []byte, source string) (EmotionData, error) {
+	return parseEmotionsAs(bytes, source, FormatJSON)
+}
 
+// parseEmotionsAs decodes a dataset in any format. source names the file in errors.
+func parseEmotionsAs(raw []byte, source string, format Format) (EmotionData, error) {
+	emotionData, err := Decode(bytes.NewReader(raw), format)
+	if err != nil {
+		return EmotionData{}, fmt.Errorf("failed to unmarshal %s: %w", source, err)
+	}
 	return emotionData, nil
 }
 
+// fileFormat is FormatFromPath with JSON as the fallback.
+func fileFormat(path string) Format {
+	if format, err := FormatFromPath(path); err == nil {
+		return format
+	}
+	return FormatJSON
+}
+
 // --- Optional Helper Functions (We can add these later as needed) ---
 
 // // GetPrimaryEmotions filters and returns only the primary emotions from the loaded data.
@@ -2,6 +2,10 @@
 package data
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing" // Import the standard Go testing package
 )
 
@@ -19,8 +23,8 @@ func TestLoadEmotions(t *testing.T) {
 	// 2. Perform basic sanity checks on the loaded data
 
 	// Check metadata
-	if data.Metadata.Version != "1.0" {
-		t.Errorf("Expected Metadata.Version '1.0', but got '%s'", data.Metadata.Version)
+	if data.Metadata.Version != "1.1" {
+		t.Errorf("Expected Metadata.Version '1.1', but got '%s'", data.Metadata.Version)
 	}
 	if data.Metadata.Source != "Feelings Wheel" {
 		t.Errorf("Expected Metadata.Source 'Feelings Wheel', but got '%s'", data.Metadata.Source)
@@ -53,6 +57,17 @@ func TestLoadEmotions(t *testing.T) {
 		t.Errorf("Expected happy emotion ParentID to be empty, got '%s'", happyEmotion.ParentID)
 	}
 
+	// Check localized names and their fallback
+	if got := happyEmotion.LocalizedName([]string{"es-MX", "es", "en"}); got != "Feliz" {
+		t.Errorf("Expected Spanish name 'Feliz' for happy, got '%s'", got)
+	}
+	if got := happyEmotion.LocalizedName([]string{"fr", "en"}); got != "Happy" {
+		t.Errorf("Expected fallback to default name 'Happy' for French, got '%s'", got)
+	}
+	if happyEmotion.Description == "" {
+		t.Errorf("Expected happy emotion to have a default description")
+	}
+
 	// Check if a specific secondary emotion exists and has the correct parent
 	playfulEmotion, ok := data.Emotions["playful"]
 	if !ok {
@@ -83,6 +98,29 @@ func TestLoadEmotions(t *testing.T) {
 		t.Errorf("Expected aroused emotion ParentID 'playful', got '%s'", arousedEmotion.ParentID)
 	}
 
+	// Check that every emotion's type matches its depth in the hierarchy, with
+	// the types ordered by EmotionType.Level (nothing assumes three levels)
+	for id, emotion := range data.Emotions {
+		depth := data.childDepth(emotion.ParentID)
+		if want := data.TypeForDepth(depth); emotion.Type != want {
+			t.Errorf("Emotion '%s' at depth %d has type '%s', expected '%s'", id, depth, emotion.Type, want)
+		}
+	}
+
+	// Check that legacy IDs are declared as aliases
+	foundAlias := false
+	for _, alias := range data.Aliases {
+		if alias.From == "joy-01" {
+			foundAlias = true
+			if _, ok := data.Emotions[alias.To]; !ok {
+				t.Errorf("Alias 'joy-01' points at unknown emotion '%s'", alias.To)
+			}
+		}
+	}
+	if !foundAlias {
+		t.Errorf("Expected an alias for legacy ID 'joy-01'")
+	}
+
 	// Optional: Check the total number of emotions loaded (adjust number if your JSON changes)
 	// expectedEmotionCount := 136 // Count items in your "emotions" object
 	// if len(data.Emotions) != expectedEmotionCount {
@@ -93,3 +131,163 @@ func TestLoadEmotions(t *testing.T) {
 	// More specific checks can be added as needed.
 	t.Log("LoadEmotions basic checks passed.") // t.Log only shows up when running tests with -v flag
 }
+
+// TestLoadEmotionsFile tests loading a dataset from disk.
+func TestLoadEmotionsFile(t *testing.T) {
+	dir := t.TempDir()
+	testCases := []struct {
+		name      string
+		content   string
+		expectErr bool
+	}{
+		{"Valid dataset", `{"metadata": {"version": "custom-1"}, "emotions": {"calm": {"id": "calm", "name": "Calm", "type": "primary"}}}`, false},
+		{"Invalid JSON", `{"emotions": {`, true},
+		{"No emotions", `{"metadata": {"version": "custom-1"}}`, true},
+	}
+
+	for i, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, string(rune('a'+i))+".json")
+			if err := os.WriteFile(path, []byte(tc.content), 0644); err != nil {
+				t.Fatalf("Failed to write dataset: %v", err)
+			}
+			data, err := LoadEmotionsFile(path)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("LoadEmotionsFile() error = %v, expected error: %v", err, tc.expectErr)
+			}
+			if !tc.expectErr && data.Emotions["calm"].Name != "Calm" {
+				t.Errorf("Expected emotion 'calm' to be loaded, got %+v", data.Emotions)
+			}
+		})
+	}
+
+	if _, err := LoadEmotionsFile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("Expected an error for a missing file")
+	}
+}
+
+// TestValidate tests the dataset validation rules.
+func TestValidate(t *testing.T) {
+	valid := func() EmotionData {
+		return EmotionData{
+			EmotionTypes: map[string]EmotionType{
+				"primary":   {ID: "primary", Level: 1},
+				"secondary": {ID: "secondary", Level: 2},
+			},
+			Emotions: map[string]Emotion{
+				"happy":   {ID: "happy", Name: "Happy", Type: "primary", Color: "#F29727"},
+				"playful": {ID: "playful", Name: "Playful", Type: "secondary", ParentID: "happy"},
+			},
+			Aliases: []IDAlias{{From: "joy-01", To: "happy"}},
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		mutate    func(d *EmotionData)
+		expectIDs []string // EmotionIDs of the expected problems
+	}{
+		{"Valid dataset", func(d *EmotionData) {}, nil},
+		{"Embedded dataset", func(d *EmotionData) { *d, _ = LoadEmotions() }, nil},
+		{"Bad color", func(d *EmotionData) { setEmotion(d, "happy", func(e *Emotion) { e.Color = "#GG0000" }) }, []string{"happy"}},
+		{"Unknown type", func(d *EmotionData) { setEmotion(d, "playful", func(e *Emotion) { e.Type = "quaternary" }) }, []string{"playful"}},
+		{"Unknown parent", func(d *EmotionData) { setEmotion(d, "playful", func(e *Emotion) { e.ParentID = "glad" }) }, []string{"playful"}},
+		{"Missing name", func(d *EmotionData) { setEmotion(d, "playful", func(e *Emotion) { e.Name = " " }) }, []string{"playful"}},
+		{"Mismatched ID", func(d *EmotionData) { setEmotion(d, "playful", func(e *Emotion) { e.ID = "cheeky" }) }, []string{"playful"}},
+		{"Parent cycle", func(d *EmotionData) { setEmotion(d, "happy", func(e *Emotion) { e.ParentID = "playful" }) }, []string{"", "happy", "playful"}},
+		{"Second parent", func(d *EmotionData) {
+			d.Emotions["calm"] = Emotion{ID: "calm", Name: "Calm", Type: "primary"}
+			setEmotion(d, "playful", func(e *Emotion) { e.ParentIDs = []string{"calm", "happy"} })
+		}, nil},
+		{"Unknown second parent", func(d *EmotionData) { setEmotion(d, "playful", func(e *Emotion) { e.ParentIDs = []string{"glad"} }) }, []string{"playful"}},
+		{"Cycle through a second parent", func(d *EmotionData) { setEmotion(d, "happy", func(e *Emotion) { e.ParentIDs = []string{"playful"} }) }, []string{"", "happy", "playful"}},
+		{"Dimensions in range", func(d *EmotionData) {
+			setEmotion(d, "happy", func(e *Emotion) { e.Valence, e.Arousal, e.Dominance = ptrFloat(1), ptrFloat(-1), ptrFloat(0) })
+		}, nil},
+		{"Dimension out of range", func(d *EmotionData) { setEmotion(d, "playful", func(e *Emotion) { e.Valence = ptrFloat(1.5) }) }, []string{"playful"}},
+		{"Negative intensity", func(d *EmotionData) { setEmotion(d, "playful", func(e *Emotion) { e.Intensity = -1 }) }, []string{"playful"}},
+		{"Intensity ladder", func(d *EmotionData) {
+			d.Emotions["cheerful"] = Emotion{ID: "cheerful", Name: "Cheerful", Type: "secondary", ParentID: "happy", Intensity: 2}
+			setEmotion(d, "playful", func(e *Emotion) { e.Intensity = 1 })
+		}, nil},
+		{"Tied intensity", func(d *EmotionData) {
+			d.Emotions["cheerful"] = Emotion{ID: "cheerful", Name: "Cheerful", Type: "secondary", ParentID: "happy", Intensity: 1}
+			setEmotion(d, "playful", func(e *Emotion) { e.Intensity = 1 })
+		}, []string{"playful"}},
+		{"Alias from existing ID", func(d *EmotionData) { d.Aliases = append(d.Aliases, IDAlias{From: "playful", To: "happy"}) }, []string{"playful"}},
+		{"Dangling alias", func(d *EmotionData) { d.Aliases = append(d.Aliases, IDAlias{From: "glee", To: "gone"}) }, []string{"glee"}},
+		{"Alias chain", func(d *EmotionData) { d.Aliases = append(d.Aliases, IDAlias{From: "glee", To: "joy-01"}) }, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := valid()
+			tc.mutate(&d)
+			problems := Validate(d)
+			var ids []string
+			for _, p := range problems {
+				ids = append(ids, p.EmotionID)
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tc.expectIDs) {
+				t.Errorf("Expected problems for %v, got %v", tc.expectIDs, problems)
+			}
+		})
+	}
+}
+
+// setEmotion applies change to a copy of an emotion and stores it back.
+func setEmotion(d *EmotionData, id string, change func(e *Emotion)) {
+	emotion := d.Emotions[id]
+	change(&emotion)
+	d.Emotions[id] = emotion
+}
+
+// TestSaveEmotionsFile tests that saved datasets load back and invalid ones are refused.
+func TestSaveEmotionsFile(t *testing.T) {
+	original, err := LoadEmotions()
+	if err != nil {
+		t.Fatalf("LoadEmotions() returned an unexpected error: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "custom.json")
+	if err := SaveEmotionsFile(path, original); err != nil {
+		t.Fatalf("SaveEmotionsFile() returned an unexpected error: %v", err)
+	}
+	loaded, err := LoadEmotionsFile(path)
+	if err != nil {
+		t.Fatalf("LoadEmotionsFile() returned an unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(original, loaded) {
+		t.Errorf("Saved dataset did not load back unchanged")
+	}
+
+	setEmotion(&original, "happy", func(e *Emotion) { e.Color = "orange" })
+	if err := SaveEmotionsFile(path, original); err == nil {
+		t.Errorf("Expected an invalid dataset to be refused")
+	}
+}
+
+// TestParseHexColor tests long, shorthand and invalid color strings.
+func TestParseHexColor(t *testing.T) {
+	testCases := []struct {
+		input     string
+		expected  string
+		expectErr bool
+	}{
+		{"#F29727", "#F29727", false},
+		{"f29727", "#F29727", false},
+		{"#F93", "#FF9933", false},
+		{"", "", true},
+		{"#F2972", "", true},
+		{"#GG0000", "", true},
+	}
+	for _, tc := range testCases {
+		c, err := ParseHexColor(tc.input)
+		if (err != nil) != tc.expectErr {
+			t.Errorf("ParseHexColor(%q) error = %v, expected error: %v", tc.input, err, tc.expectErr)
+			continue
+		}
+		if !tc.expectErr && FormatHexColor(c) != tc.expected {
+			t.Errorf("ParseHexColor(%q) = %s, expected %s", tc.input, FormatHexColor(c), tc.expected)
+		}
+	}
+}
@@ -2,33 +2,184 @@
 package data
 
 // EmotionData represents the entire structure of the emotions.json file.
+// The yaml and toml tags mirror the JSON names so every format in convert.go
+// uses the same keys.
 type EmotionData struct {
-	Metadata     Metadata               `json:"metadata"`
-	EmotionTypes map[string]EmotionType `json:"emotionTypes"` // Map key is the type ID (e.g., "primary")
-	Emotions     map[string]Emotion     `json:"emotions"`     // Map key is the emotion ID (e.g., "happy")
+	Metadata     Metadata               `json:"metadata" yaml:"metadata" toml:"metadata"`
+	EmotionTypes map[string]EmotionType `json:"emotionTypes" yaml:"emotionTypes" toml:"emotionTypes"`                // Map key is the type ID (e.g., "primary")
+	Emotions     map[string]Emotion     `json:"emotions" yaml:"emotions" toml:"emotions"`                            // Map key is the emotion ID (e.g., "happy")
+	Aliases      []IDAlias              `json:"aliases,omitempty" yaml:"aliases,omitempty" toml:"aliases,omitempty"` // Renamed/retired IDs, so old journal entries still resolve
 }
 
 // Metadata holds information about the dataset version and source.
 type Metadata struct {
-	Version     string `json:"version"`
-	Source      string `json:"source"`
-	Description string `json:"description"`
+	Version     string `json:"version" yaml:"version" toml:"version"`
+	Source      string `json:"source" yaml:"source" toml:"source"`
+	Description string `json:"description" yaml:"description" toml:"description"`
+}
+
+// IDAlias records that an emotion ID was renamed or retired in some dataset version.
+// Journal entries store emotion IDs, so every ID that ever shipped should
+// either still exist or have an alias pointing at its replacement.
+type IDAlias struct {
+	From  string `json:"from" yaml:"from" toml:"from"`                                  // Legacy ID (e.g. found in older journals)
+	To    string `json:"to" yaml:"to" toml:"to"`                                        // ID it maps to; may itself be an alias
+	Since string `json:"since,omitempty" yaml:"since,omitempty" toml:"since,omitempty"` // Dataset version in which the change happened
+	Note  string `json:"note,omitempty" yaml:"note,omitempty" toml:"note,omitempty"`    // Why the ID changed
 }
 
 // EmotionType defines the characteristics of an emotion level (primary, secondary, etc.).
 type EmotionType struct {
-	ID    string `json:"id"`
-	Name  string `json:"name"`
-	Level int    `json:"level"`
+	ID    string `json:"id" yaml:"id" toml:"id"`
+	Name  string `json:"name" yaml:"name" toml:"name"`
+	Level int    `json:"level" yaml:"level" toml:"level"`
 }
 
 // Emotion represents a single emotion with its properties and relationship.
 type Emotion struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	Type     string `json:"type"`               // Corresponds to an EmotionType ID (e.g., "primary")
-	Color    string `json:"color"`              // Hex color code
-	ParentID string `json:"parentId,omitempty"` // Use omitempty as primary emotions won't have this
+	ID       string `json:"id" yaml:"id" toml:"id"`
+	Name     string `json:"name" yaml:"name" toml:"name"`
+	Type     string `json:"type" yaml:"type" toml:"type"`                                           // Corresponds to an EmotionType ID (e.g., "primary")
+	Color    string `json:"color" yaml:"color" toml:"color"`                                        // Hex color code
+	ParentID string `json:"parentId,omitempty" yaml:"parentId,omitempty" toml:"parentId,omitempty"` // Use omitempty as primary emotions won't have this
+	// ParentIDs lists further parents for words that belong to more than one
+	// family (e.g. "Overwhelmed" under both Fearful and Bad), making the
+	// hierarchy a DAG. ParentID stays the primary parent; see Parents.
+	ParentIDs []string `json:"parentIds,omitempty" yaml:"parentIds,omitempty" toml:"parentIds,omitempty"`
+
+	// Optional localized text. Name and Description are the default (English) text;
+	// the maps hold translations keyed by locale (e.g. "es", "pt-BR").
+	Description  string            `json:"description,omitempty" yaml:"description,omitempty" toml:"description,omitempty"`
+	Names        map[string]string `json:"names,omitempty" yaml:"names,omitempty" toml:"names,omitempty"`
+	Descriptions map[string]string `json:"descriptions,omitempty" yaml:"descriptions,omitempty" toml:"descriptions,omitempty"`
+
+	// Optional position on the circumplex (dimensional) model of affect, each
+	// from -1 to 1: Valence (unpleasant to pleasant), Arousal (low to high
+	// energy) and Dominance (controlled to in control). nil means unknown;
+	// the mood meter only offers emotions with valence and arousal.
+	Valence   *float64 `json:"valence,omitempty" yaml:"valence,omitempty" toml:"valence,omitempty"`
+	Arousal   *float64 `json:"arousal,omitempty" yaml:"arousal,omitempty" toml:"arousal,omitempty"`
+	Dominance *float64 `json:"dominance,omitempty" yaml:"dominance,omitempty" toml:"dominance,omitempty"`
+
+	// Order optionally fixes the emotion's place among its siblings (1 comes
+	// first); siblings without one follow in the order the file lists them.
+	// See LessInDatasetOrder.
+	Order int `json:"order,omitempty" yaml:"order,omitempty" toml:"order,omitempty"`
+	// Intensity optionally ranks siblings from mildest (1) to strongest,
+	// e.g. annoyed < frustrated < furious, for the "by intensity" sort.
+	Intensity int `json:"intensity,omitempty" yaml:"intensity,omitempty" toml:"intensity,omitempty"`
+	// Position is where the emotion is declared in its dataset file, set by
+	// Decode (parents before children, siblings in file order). The encoders
+	// write emotions in this order, so saving keeps a file's ordering.
+	Position int `json:"-" yaml:"-" toml:"-"`
+
+	// Hidden is set by a user overlay (see Overlay) for emotions the user
+	// never uses. Hidden emotions are left out of browsing, logging and
+	// search but still resolve for journal history.
+	Hidden bool `json:"-" yaml:"-" toml:"-"`
 	// We can add fields here later if needed, e.g., to hold child emotions after processing
 	// Children []*Emotion `json:"-"` // Ignored by JSON marshalling/unmarshalling
 }
+
+// Parents returns every parent ID: ParentID (the primary parent) first,
+// then ParentIDs, without duplicates or empty IDs. Roots have none.
+func (e Emotion) Parents() []string {
+	parents := make([]string, 0, 1+len(e.ParentIDs))
+	if e.ParentID != "" {
+		parents = append(parents, e.ParentID)
+	}
+	for _, id := range e.ParentIDs {
+		if id != "" && !containsString(parents, id) {
+			parents = append(parents, id)
+		}
+	}
+	return parents
+}
+
+// Coordinates returns the emotion's valence and arousal, and false if
+// either is missing.
+func (e Emotion) Coordinates() (valence, arousal float64, ok bool) {
+	if e.Valence == nil || e.Arousal == nil {
+		return 0, 0, false
+	}
+	return *e.Valence, *e.Arousal, true
+}
+
+// LessInDatasetOrder reports whether a comes before b in dataset order:
+// emotions with an explicit Order first (by Order), then by Position, with
+// names and IDs as tie-breakers for datasets built in code.
+func LessInDatasetOrder(a, b Emotion) bool {
+	if (a.Order > 0) != (b.Order > 0) {
+		return a.Order > 0
+	}
+	if a.Order != b.Order {
+		return a.Order < b.Order
+	}
+	if a.Position != b.Position {
+		return a.Position < b.Position
+	}
+	if a.Name != b.Name {
+		return a.Name < b.Name
+	}
+	return a.ID < b.ID
+}
+
+// NextPosition returns a Position after every emotion's, for emotions added
+// to a loaded dataset: they come last among their siblings.
+func (d EmotionData) NextPosition() int {
+	next := 0
+	for _, emotion := range d.Emotions {
+		if emotion.Position >= next {
+			next = emotion.Position + 1
+		}
+	}
+	return next
+}
+
+// PrimaryParent returns the parent used where a single parent is needed
+// (colors, the default breadcrumb, tree views): ParentID, or the first of
+// ParentIDs for datasets that only list those. Returns "" for roots.
+func (e Emotion) PrimaryParent() string {
+	if parents := e.Parents(); len(parents) > 0 {
+		return parents[0]
+	}
+	return ""
+}
+
+// HasParent reports whether id is one of the emotion's parents.
+func (e Emotion) HasParent(id string) bool {
+	return id != "" && containsString(e.Parents(), id)
+}
+
+// LocalizedName returns the emotion's name in the first locale of the chain
+// that has a translation, falling back to Name.
+// Use i18n.Chain() for the active fallback chain.
+func (e Emotion) LocalizedName(chain []string) string {
+	return localized(e.Names, chain, e.Name)
+}
+
+// LocalizedDescription returns the emotion's description in the first locale
+// of the chain that has one, falling back to Description.
+func (e Emotion) LocalizedDescription(chain []string) string {
+	return localized(e.Descriptions, chain, e.Description)
+}
+
+// localized looks up the first non-empty translation along the chain.
+func localized(translations map[string]string, chain []string, fallback string) string {
+	for _, locale := range chain {
+		if text := translations[locale]; text != "" {
+			return text
+		}
+	}
+	return fallback
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
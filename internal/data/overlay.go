@@ -0,0 +1,491 @@
+// internal/data/overlay.go
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// OverlayFilename is the name of the user's overlay file in the data directory.
+const OverlayFilename = "overlay.json"
+
+// Overlay holds personal changes layered on top of a dataset at load time,
+// so the dataset itself (e.g. the embedded emotions.json) can be upgraded
+// without losing them. A typical overlay:
+//
+//	{
+//	  "baseVersion": "1.1",
+//	  "add": [{"id": "zoomed_out", "name": "Zoomed out", "parentId": "tired"}],
+//	  "override": {"playful": {"color": "#FFC107"}},
+//	  "hide": ["aroused"]
+//	}
+//
+// Changes are applied in that order: additions, overrides, then hides.
+type Overlay struct {
+	BaseVersion string                     `json:"baseVersion,omitempty"` // Dataset version the overlay was written against
+	Add         []Emotion                  `json:"add,omitempty"`         // New emotions; type and color default from the parent
+	Override    map[string]EmotionOverride `json:"override,omitempty"`    // Field changes keyed by emotion ID
+	Hide        []string                   `json:"hide,omitempty"`        // Emotions hidden (with their descendants) from browsing and logging
+}
+
+// EmotionOverride lists the fields an overlay changes on an existing emotion.
+// Nil fields are left alone; the translation maps are merged key by key.
+type EmotionOverride struct {
+	Name         *string           `json:"name,omitempty"`
+	Color        *string           `json:"color,omitempty"`
+	ParentID     *string           `json:"parentId,omitempty"`  // "" moves the emotion to the top level
+	ParentIDs    *[]string         `json:"parentIds,omitempty"` // Replaces the further parents ([] removes them)
+	Description  *string           `json:"description,omitempty"`
+	Valence      *float64          `json:"valence,omitempty"`
+	Arousal      *float64          `json:"arousal,omitempty"`
+	Dominance    *float64          `json:"dominance,omitempty"`
+	Intensity    *int              `json:"intensity,omitempty"` // 0 takes the emotion off its intensity ladder
+	Names        map[string]string `json:"names,omitempty"`
+	Descriptions map[string]string `json:"descriptions,omitempty"`
+}
+
+// IsEmpty reports whether the overlay changes nothing.
+func (o Overlay) IsEmpty() bool {
+	return len(o.Add) == 0 && len(o.Override) == 0 && len(o.Hide) == 0
+}
+
+// OverlayConflict describes an overlay change that could not be applied as
+// written, usually because the dataset changed underneath it. Some conflicts
+// are informational: the change was still applied (e.g. to a renamed ID).
+type OverlayConflict struct {
+	Section   string // "add", "override", "hide" or "" for the whole overlay
+	EmotionID string // Emotion the change concerns ("" for overlay-wide conflicts)
+	Message   string
+}
+
+func (c OverlayConflict) Error() string {
+	switch {
+	case c.Section == "":
+		return c.Message
+	case c.EmotionID == "":
+		return fmt.Sprintf("%s: %s", c.Section, c.Message)
+	}
+	return fmt.Sprintf("%s %s: %s", c.Section, c.EmotionID, c.Message)
+}
+
+// LoadOverlayFile reads an overlay file. Unknown fields are rejected, since
+// the file is written by hand and a typo would otherwise be silently ignored.
+// A missing file returns an error wrapping fs.ErrNotExist.
+func LoadOverlayFile(path string) (Overlay, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Overlay{}, fmt.Errorf("failed to read overlay file '%s': %w", path, err)
+	}
+	var overlay Overlay
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return overlay, nil // An empty file is an empty overlay
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&overlay); err != nil {
+		return Overlay{}, fmt.Errorf("failed to unmarshal %s: %w", path, err)
+	}
+	return overlay, nil
+}
+
+// ApplyOverlay returns a copy of base with the overlay applied, plus the
+// conflicts found along the way. Changes that conflict with the dataset are
+// skipped; the rest still apply, so one stale line doesn't discard a whole
+// overlay. base is not modified.
+func ApplyOverlay(base EmotionData, overlay Overlay) (EmotionData, []OverlayConflict) {
+	merged := base
+	merged.Emotions = make(map[string]Emotion, len(base.Emotions)+len(overlay.Add))
+	for id, emotion := range base.Emotions {
+		merged.Emotions[id] = emotion
+	}
+
+	var conflicts []OverlayConflict
+	report := func(section, id, format string, args ...any) {
+		conflicts = append(conflicts, OverlayConflict{Section: section, EmotionID: id, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if overlay.BaseVersion != "" && overlay.BaseVersion != base.Metadata.Version {
+		report("", "", "overlay was written for dataset version %s, the dataset is now version %s", overlay.BaseVersion, base.Metadata.Version)
+	}
+
+	applyAdditions(&merged, overlay.Add, report)
+	applyOverrides(&merged, overlay.Override, report)
+	applyHides(&merged, overlay.Hide, report)
+	return merged, conflicts
+}
+
+// conflictReporter records one conflict (see ApplyOverlay).
+type conflictReporter func(section, id, format string, args ...any)
+
+// applyAdditions adds new emotions. Additions may be nested under other
+// additions in any order, so they are applied in rounds until no more
+// parents become available.
+func applyAdditions(d *EmotionData, additions []Emotion, report conflictReporter) {
+	pending := make([]Emotion, 0, len(additions))
+	for _, emotion := range additions {
+		id := emotion.ID
+		switch {
+		case id == "" || strings.TrimSpace(id) != id || strings.ContainsAny(id, " \t\n"):
+			report("add", id, "missing or invalid id")
+		case strings.TrimSpace(emotion.Name) == "":
+			report("add", id, "missing name")
+		case d.resolveAlias(id) != "":
+			// Usually a dataset upgrade now ships the same word; the dataset wins
+			report("add", id, "the dataset already defines this emotion; the dataset's version is kept")
+		case emotion.Color != "" && !isValidColor(emotion.Color):
+			report("add", id, "invalid color '%s'", emotion.Color)
+		case emotion.Type != "" && len(d.EmotionTypes) > 0 && !d.hasType(emotion.Type):
+			report("add", id, "unknown type '%s'", emotion.Type)
+		case invalidDimension(emotion) != "":
+			report("add", id, "%s is outside -1..1", invalidDimension(emotion))
+		default:
+			pending = append(pending, emotion)
+		}
+	}
+
+	for progress := true; progress && len(pending) > 0; {
+		progress = false
+		remaining := pending[:0]
+		for _, emotion := range pending {
+			parentID, parentIDs, ok := d.resolveParents(emotion)
+			if !ok {
+				remaining = append(remaining, emotion) // Maybe another addition
+				continue
+			}
+			if _, exists := d.Emotions[emotion.ID]; exists {
+				report("add", emotion.ID, "added more than once; the first addition is kept")
+				progress = true
+				continue
+			}
+			emotion.ParentID, emotion.ParentIDs = parentID, parentIDs
+			if emotion.Type == "" {
+				emotion.Type = d.TypeForDepth(d.childDepth(emotion.PrimaryParent()))
+			}
+			if emotion.Color == "" && emotion.PrimaryParent() != "" {
+				emotion.Color = d.Emotions[emotion.PrimaryParent()].Color
+			}
+			emotion.Position = d.NextPosition() // After the dataset's emotions, in overlay order
+			d.Emotions[emotion.ID] = emotion
+			progress = true
+		}
+		pending = remaining
+	}
+	for _, emotion := range pending {
+		report("add", emotion.ID, "unknown parent '%s'", strings.Join(emotion.Parents(), "', '"))
+	}
+}
+
+// resolveParents resolves an added emotion's parents through the dataset's
+// aliases. ok is false if any parent doesn't exist (yet).
+func (d *EmotionData) resolveParents(emotion Emotion) (parentID string, parentIDs []string, ok bool) {
+	if emotion.ParentID != "" {
+		if parentID = d.resolveAlias(emotion.ParentID); parentID == "" {
+			return "", nil, false
+		}
+	}
+	for _, id := range emotion.ParentIDs {
+		resolved := d.resolveAlias(id)
+		if resolved == "" {
+			return "", nil, false
+		}
+		parentIDs = append(parentIDs, resolved)
+	}
+	return parentID, parentIDs, true
+}
+
+// applyOverrides changes fields of existing emotions, in ID order so
+// conflicts are reported deterministically.
+func applyOverrides(d *EmotionData, overrides map[string]EmotionOverride, report conflictReporter) {
+	ids := make([]string, 0, len(overrides))
+	for id := range overrides {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, overlayID := range ids {
+		override := overrides[overlayID]
+		id := d.resolveRenamed("override", overlayID, report)
+		if id == "" {
+			continue
+		}
+		emotion := d.Emotions[id]
+
+		if override.Name != nil {
+			if strings.TrimSpace(*override.Name) == "" {
+				report("override", overlayID, "name must not be empty; kept '%s'", emotion.Name)
+			} else {
+				emotion.Name = *override.Name
+			}
+		}
+		if override.Color != nil {
+			if isValidColor(*override.Color) {
+				emotion.Color = *override.Color
+			} else {
+				report("override", overlayID, "invalid color '%s'", *override.Color)
+			}
+		}
+		if override.Description != nil {
+			emotion.Description = *override.Description
+		}
+		setDimension := func(field **float64, name string, value *float64) {
+			if value == nil {
+				return
+			}
+			if !InDimensionRange(*value) {
+				report("override", overlayID, "%s %g is outside -1..1", name, *value)
+				return
+			}
+			*field = value
+		}
+		setDimension(&emotion.Valence, "valence", override.Valence)
+		setDimension(&emotion.Arousal, "arousal", override.Arousal)
+		setDimension(&emotion.Dominance, "dominance", override.Dominance)
+		if override.Intensity != nil {
+			if *override.Intensity < 0 {
+				report("override", overlayID, "intensity %d is negative", *override.Intensity)
+			} else {
+				emotion.Intensity = *override.Intensity
+			}
+		}
+		emotion.Names = mergeTranslations(emotion.Names, override.Names)
+		emotion.Descriptions = mergeTranslations(emotion.Descriptions, override.Descriptions)
+		d.Emotions[id] = emotion
+
+		if override.ParentID != nil {
+			d.moveEmotion(overlayID, id, *override.ParentID, report)
+		}
+		if override.ParentIDs != nil {
+			d.setFurtherParents(overlayID, id, *override.ParentIDs, report)
+		}
+	}
+}
+
+// moveEmotion changes an emotion's parent for an override, retyping it and
+// its descendants for their new depth. Moves that would create a cycle are
+// refused.
+func (d *EmotionData) moveEmotion(overlayID, id, parentID string, report conflictReporter) {
+	if parentID != "" {
+		resolved := d.resolveAlias(parentID)
+		if resolved == "" {
+			report("override", overlayID, "unknown parent '%s'", parentID)
+			return
+		}
+		parentID = resolved
+	}
+	emotion := d.Emotions[id]
+	previous := emotion.ParentID
+	emotion.ParentID = parentID
+	d.Emotions[id] = emotion
+	if parentID == id || inParentCycle(id, d.Emotions) {
+		emotion.ParentID = previous
+		d.Emotions[id] = emotion
+		report("override", overlayID, "cannot move under '%s': it is a descendant", parentID)
+		return
+	}
+	d.retype(id)
+}
+
+// setFurtherParents replaces an emotion's further parents (ParentIDs) for an
+// override. Unknown parents and ones that would create a cycle are skipped.
+func (d *EmotionData) setFurtherParents(overlayID, id string, parentIDs []string, report conflictReporter) {
+	emotion := d.Emotions[id]
+	emotion.ParentIDs = nil
+	d.Emotions[id] = emotion
+	for _, parentID := range parentIDs {
+		resolved := d.resolveAlias(parentID)
+		if resolved == "" {
+			report("override", overlayID, "unknown parent '%s'", parentID)
+			continue
+		}
+		emotion.ParentIDs = append(emotion.ParentIDs, resolved)
+		d.Emotions[id] = emotion
+		if resolved == id || inParentCycle(id, d.Emotions) {
+			emotion.ParentIDs = emotion.ParentIDs[:len(emotion.ParentIDs)-1]
+			d.Emotions[id] = emotion
+			report("override", overlayID, "cannot add parent '%s': it is a descendant", resolved)
+		}
+	}
+	d.retype(id)
+}
+
+// retype updates the types of an emotion and its descendants for their
+// (primary) depth after a move.
+func (d *EmotionData) retype(id string) {
+	for _, movedID := range d.subtree(id) {
+		moved := d.Emotions[movedID]
+		moved.Type = d.TypeForDepth(d.childDepth(moved.PrimaryParent()))
+		d.Emotions[movedID] = moved
+	}
+}
+
+// applyHides marks emotions and their descendants hidden. A descendant that
+// also belongs to a family that stays visible (see Emotion.ParentIDs) stays
+// visible. Hiding every top-level emotion would leave nothing to browse, so
+// it is refused.
+func applyHides(d *EmotionData, hides []string, report conflictReporter) {
+	hidden := make(map[string]bool)
+	for _, overlayID := range hides {
+		if id := d.resolveRenamed("hide", overlayID, report); id != "" {
+			hidden[id] = true
+		}
+	}
+	// Hide descendants whose parents are all hidden, until nothing changes
+	for changed := len(hidden) > 0; changed; {
+		changed = false
+		for id, emotion := range d.Emotions {
+			parents := emotion.Parents()
+			if hidden[id] || len(parents) == 0 {
+				continue
+			}
+			allHidden := true
+			for _, parentID := range parents {
+				allHidden = allHidden && hidden[parentID]
+			}
+			if allHidden {
+				hidden[id] = true
+				changed = true
+			}
+		}
+	}
+
+	visibleRoot := false
+	for id, emotion := range d.Emotions {
+		if len(emotion.Parents()) == 0 && !hidden[id] {
+			visibleRoot = true
+			break
+		}
+	}
+	if len(hidden) > 0 && !visibleRoot {
+		report("hide", "", "would hide every top-level emotion; nothing is hidden")
+		return
+	}
+	for id := range hidden {
+		emotion := d.Emotions[id]
+		emotion.Hidden = true
+		d.Emotions[id] = emotion
+	}
+}
+
+// resolveRenamed resolves an overlay's emotion ID against the dataset,
+// following aliases so overlays survive ID renames (reported, but applied).
+// Returns "" (after reporting) if the ID is unknown.
+func (d *EmotionData) resolveRenamed(section, overlayID string, report conflictReporter) string {
+	id := d.resolveAlias(overlayID)
+	switch {
+	case id == "":
+		report(section, overlayID, "the dataset no longer defines this emotion; change skipped")
+	case id != overlayID:
+		report(section, overlayID, "renamed to '%s' in the dataset; change applied to '%s'", id, id)
+	}
+	return id
+}
+
+// resolveAlias returns the emotion ID that id refers to, following alias
+// chains, or "" if it refers to nothing.
+func (d *EmotionData) resolveAlias(id string) string {
+	targets := make(map[string]string, len(d.Aliases))
+	for _, alias := range d.Aliases {
+		targets[alias.From] = alias.To
+	}
+	for steps := 0; steps <= len(targets); steps++ {
+		if _, ok := d.Emotions[id]; ok {
+			return id
+		}
+		next, ok := targets[id]
+		if !ok {
+			return ""
+		}
+		id = next
+	}
+	return "" // Alias cycle
+}
+
+// subtree returns id followed by all its descendants, each once even if it
+// is reachable through several parents.
+func (d *EmotionData) subtree(id string) []string {
+	ids := []string{id}
+	seen := map[string]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		for childID, emotion := range d.Emotions {
+			if !seen[childID] && emotion.HasParent(ids[i]) {
+				seen[childID] = true
+				ids = append(ids, childID)
+			}
+		}
+	}
+	return ids
+}
+
+// childDepth returns the depth (0 = top level) of a child of parentID:
+// 0 for "" (no parent), 1 under a top-level emotion, and so on. Depth
+// follows primary parents (see Emotion.PrimaryParent).
+func (d *EmotionData) childDepth(parentID string) int {
+	depth := 0
+	visited := map[string]bool{}
+	for current := parentID; current != "" && !visited[current]; current = d.Emotions[current].PrimaryParent() {
+		visited[current] = true
+		depth++
+	}
+	return depth
+}
+
+// hasType reports whether the dataset defines the emotion type.
+func (d *EmotionData) hasType(typeID string) bool {
+	_, ok := d.EmotionTypes[typeID]
+	return ok
+}
+
+// TypeForDepth picks the emotion type for an emotion at the given depth
+// (0 = top level): the depth-th type by Level, or the deepest type for
+// anything below. Returns "" if the dataset defines no types.
+func (d EmotionData) TypeForDepth(depth int) string {
+	types := make([]EmotionType, 0, len(d.EmotionTypes))
+	for _, emotionType := range d.EmotionTypes {
+		types = append(types, emotionType)
+	}
+	if len(types) == 0 {
+		return ""
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Level < types[j].Level })
+	if depth >= len(types) {
+		depth = len(types) - 1
+	}
+	return types[depth].ID
+}
+
+// isValidColor reports whether hex parses as a color.
+func isValidColor(hex string) bool {
+	_, err := ParseHexColor(hex)
+	return err == nil
+}
+
+// invalidDimension returns the name of the emotion's first out-of-range
+// valence, arousal or dominance value, or "".
+func invalidDimension(emotion Emotion) string {
+	for _, dimension := range emotion.dimensions() {
+		if dimension.value != nil && !InDimensionRange(*dimension.value) {
+			return dimension.name
+		}
+	}
+	return ""
+}
+
+// mergeTranslations returns base with the overlay's translations added or
+// replaced. base is not modified.
+func mergeTranslations(base, overlay map[string]string) map[string]string {
+	if len(overlay) == 0 {
+		return base
+	}
+	merged := make(map[string]string, len(base)+len(overlay))
+	for locale, text := range base {
+		merged[locale] = text
+	}
+	for locale, text := range overlay {
+		merged[locale] = text
+	}
+	return merged
+}
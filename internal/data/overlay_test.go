@@ -0,0 +1,282 @@
+// internal/data/overlay_test.go
+package data
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// loadBase loads the embedded dataset, failing the test on error.
+func loadBase(t *testing.T) EmotionData {
+	t.Helper()
+	base, err := LoadEmotions()
+	if err != nil {
+		t.Fatalf("LoadEmotions() failed: %v", err)
+	}
+	return base
+}
+
+func ptr(s string) *string { return &s }
+
+func ptrFloat(f float64) *float64 { return &f }
+
+func ptrInt(i int) *int { return &i }
+
+// conflictFor returns the first conflict for section and emotion ID, or false.
+func conflictFor(conflicts []OverlayConflict, section, id string) (OverlayConflict, bool) {
+	for _, c := range conflicts {
+		if c.Section == section && c.EmotionID == id {
+			return c, true
+		}
+	}
+	return OverlayConflict{}, false
+}
+
+func TestApplyOverlayAdd(t *testing.T) {
+	base := loadBase(t)
+	merged, conflicts := ApplyOverlay(base, Overlay{
+		BaseVersion: base.Metadata.Version,
+		Add: []Emotion{
+			// Nested addition listed before its parent
+			{ID: "fried", Name: "Fried", ParentID: "zoomed_out"},
+			{ID: "zoomed_out", Name: "Zoomed out", ParentID: "tired"},
+			{ID: "happy", Name: "Happy again"},                         // Already in the dataset
+			{ID: "lost", Name: "Lost", ParentID: "nowhere"},            // Unknown parent
+			{ID: "joyish", Name: "Joyish", ParentID: "joy-01"},         // Parent via alias
+			{ID: "bad_color", Name: "Bad color", Color: "not-a-color"}, // Invalid color
+		},
+	})
+
+	added, ok := merged.Emotions["zoomed_out"]
+	if !ok {
+		t.Fatal("zoomed_out was not added")
+	}
+	if added.Type != "tertiary" || added.Color != base.Emotions["tired"].Color {
+		t.Errorf("zoomed_out = type %q color %q, want tertiary and Tired's color", added.Type, added.Color)
+	}
+	if merged.Emotions["fried"].ParentID != "zoomed_out" {
+		t.Errorf("fried was not added under zoomed_out: %+v", merged.Emotions["fried"])
+	}
+	if merged.Emotions["joyish"].ParentID != "happy" {
+		t.Errorf("joyish parent = %q, want the alias target 'happy'", merged.Emotions["joyish"].ParentID)
+	}
+	if merged.Emotions["happy"].Name != "Happy" {
+		t.Errorf("the dataset's Happy was replaced by the overlay: %+v", merged.Emotions["happy"])
+	}
+	for _, id := range []string{"happy", "lost", "bad_color"} {
+		if _, ok := conflictFor(conflicts, "add", id); !ok {
+			t.Errorf("no add conflict reported for %q; got %v", id, conflicts)
+		}
+	}
+	if len(conflicts) != 3 {
+		t.Errorf("got %d conflicts, want 3: %v", len(conflicts), conflicts)
+	}
+	if problems := Validate(merged); len(problems) > 0 {
+		t.Errorf("merged dataset is invalid: %v", problems)
+	}
+	if _, ok := base.Emotions["zoomed_out"]; ok {
+		t.Error("ApplyOverlay modified the base dataset")
+	}
+}
+
+func TestApplyOverlayOverride(t *testing.T) {
+	base := loadBase(t)
+	merged, conflicts := ApplyOverlay(base, Overlay{
+		Override: map[string]EmotionOverride{
+			"playful": {Name: ptr("Cheerful"), Color: ptr("#ffc107"), Names: map[string]string{"pt": "Alegre"}},
+			"joy-01":  {Description: ptr("Renamed in 1.1")},                                              // Applied to "happy" through the alias
+			"tired":   {ParentID: ptr("")},                                                               // Move to the top level
+			"happy":   {ParentID: ptr("playful")},                                                        // Would create a cycle
+			"gone":    {Name: ptr("Gone")},                                                               // Unknown
+			"bored":   {Name: ptr("  "), Color: ptr("#12"), Arousal: ptrFloat(3), Intensity: ptrInt(-2)}, // Invalid fields
+			"content": {Valence: ptrFloat(0.5), Dominance: ptrFloat(-0.2), Intensity: ptrInt(3)},
+		},
+	})
+
+	playful := merged.Emotions["playful"]
+	if playful.Name != "Cheerful" || playful.Color != "#ffc107" {
+		t.Errorf("playful = %q %q, want Cheerful #ffc107", playful.Name, playful.Color)
+	}
+	if playful.Names["pt"] != "Alegre" || playful.Names["es"] != base.Emotions["playful"].Names["es"] {
+		t.Errorf("playful translations not merged: %v", playful.Names)
+	}
+	if base.Emotions["playful"].Names["pt"] != "" {
+		t.Error("ApplyOverlay modified the base dataset's translations")
+	}
+	if merged.Emotions["happy"].Description != "Renamed in 1.1" {
+		t.Errorf("override through alias not applied: %+v", merged.Emotions["happy"])
+	}
+	if _, ok := conflictFor(conflicts, "override", "joy-01"); !ok {
+		t.Errorf("renamed ID not reported; got %v", conflicts)
+	}
+	if tired := merged.Emotions["tired"]; tired.ParentID != "" || tired.Type != "primary" {
+		t.Errorf("tired = parent %q type %q, want a primary top-level emotion", tired.ParentID, tired.Type)
+	}
+	if merged.Emotions["happy"].ParentID != "" {
+		t.Error("cyclic move was applied")
+	}
+	for _, id := range []string{"happy", "gone", "bored"} {
+		if _, ok := conflictFor(conflicts, "override", id); !ok {
+			t.Errorf("no override conflict reported for %q; got %v", id, conflicts)
+		}
+	}
+	if content := merged.Emotions["content"]; *content.Valence != 0.5 || *content.Arousal != *base.Emotions["content"].Arousal || *content.Dominance != -0.2 {
+		t.Errorf("content dimensions not overridden: %v %v %v", *content.Valence, *content.Arousal, *content.Dominance)
+	}
+	if *merged.Emotions["bored"].Arousal != *base.Emotions["bored"].Arousal {
+		t.Error("out-of-range arousal override was applied")
+	}
+	if merged.Emotions["content"].Intensity != 3 || merged.Emotions["bored"].Intensity != base.Emotions["bored"].Intensity {
+		t.Errorf("intensity overrides: content %d, bored %d", merged.Emotions["content"].Intensity, merged.Emotions["bored"].Intensity)
+	}
+	if merged.Emotions["bored"].Name != "Bored" {
+		t.Errorf("empty name override was applied: %q", merged.Emotions["bored"].Name)
+	}
+	if problems := Validate(merged); len(problems) > 0 {
+		t.Errorf("merged dataset is invalid: %v", problems)
+	}
+}
+
+func TestApplyOverlayHide(t *testing.T) {
+	base := loadBase(t)
+	merged, conflicts := ApplyOverlay(base, Overlay{Hide: []string{"playful", "unknown"}})
+
+	for _, id := range []string{"playful", "aroused"} {
+		if !merged.Emotions[id].Hidden {
+			t.Errorf("%q is not hidden", id)
+		}
+	}
+	if merged.Emotions["happy"].Hidden {
+		t.Error("the hidden emotion's parent is hidden too")
+	}
+	if _, ok := conflictFor(conflicts, "hide", "unknown"); !ok || len(conflicts) != 1 {
+		t.Errorf("want a single conflict for 'unknown', got %v", conflicts)
+	}
+
+	// Hiding every top-level emotion is refused
+	var roots []string
+	for id, emotion := range base.Emotions {
+		if emotion.ParentID == "" {
+			roots = append(roots, id)
+		}
+	}
+	merged, conflicts = ApplyOverlay(base, Overlay{Hide: roots})
+	for id, emotion := range merged.Emotions {
+		if emotion.Hidden {
+			t.Fatalf("%q hidden although hiding everything must be refused", id)
+		}
+	}
+	if len(conflicts) != 1 {
+		t.Errorf("want one conflict for hiding everything, got %v", conflicts)
+	}
+}
+
+// TestApplyOverlayMultipleParents tests overlays on emotions with several parents.
+func TestApplyOverlayMultipleParents(t *testing.T) {
+	base := loadBase(t)
+	setEmotion(&base, "aroused", func(e *Emotion) { e.ParentIDs = []string{"bored"} })
+
+	// Hiding one family keeps emotions that also belong to another one
+	merged, conflicts := ApplyOverlay(base, Overlay{Hide: []string{"playful"}})
+	if len(conflicts) > 0 || !merged.Emotions["playful"].Hidden {
+		t.Fatalf("playful not hidden cleanly: conflicts %v", conflicts)
+	}
+	if merged.Emotions["aroused"].Hidden {
+		t.Error("aroused was hidden although it is also under bored")
+	}
+	merged, _ = ApplyOverlay(base, Overlay{Hide: []string{"playful", "bored"}})
+	if !merged.Emotions["aroused"].Hidden {
+		t.Error("aroused is visible although all its parents are hidden")
+	}
+
+	// Further parents can be added, replaced and removed
+	merged, conflicts = ApplyOverlay(base, Overlay{
+		Add: []Emotion{{ID: "torn", Name: "Torn", ParentID: "bored", ParentIDs: []string{"joy-01"}}},
+		Override: map[string]EmotionOverride{
+			"aroused": {ParentIDs: &[]string{}},
+			"tired":   {ParentIDs: &[]string{"happy", "nowhere"}},
+			"happy":   {ParentIDs: &[]string{"playful"}}, // Would create a cycle
+		},
+	})
+	if torn := merged.Emotions["torn"]; len(torn.ParentIDs) != 1 || torn.ParentIDs[0] != "happy" {
+		t.Errorf("torn = %+v, want a second parent 'happy' (through the alias)", torn)
+	}
+	if len(merged.Emotions["aroused"].ParentIDs) != 0 {
+		t.Errorf("aroused still has further parents: %v", merged.Emotions["aroused"].ParentIDs)
+	}
+	if parents := merged.Emotions["tired"].ParentIDs; len(parents) != 1 || parents[0] != "happy" {
+		t.Errorf("tired further parents = %v, want [happy]", parents)
+	}
+	if len(merged.Emotions["happy"].ParentIDs) != 0 {
+		t.Error("cyclic further parent was applied")
+	}
+	for _, id := range []string{"tired", "happy"} {
+		if _, ok := conflictFor(conflicts, "override", id); !ok {
+			t.Errorf("no override conflict reported for %q; got %v", id, conflicts)
+		}
+	}
+	if problems := Validate(merged); len(problems) > 0 {
+		t.Errorf("merged dataset is invalid: %v", problems)
+	}
+}
+
+func TestApplyOverlayVersionMismatch(t *testing.T) {
+	base := loadBase(t)
+	_, conflicts := ApplyOverlay(base, Overlay{BaseVersion: "0.9"})
+	if len(conflicts) != 1 || !strings.Contains(conflicts[0].Error(), "0.9") {
+		t.Errorf("want a version conflict mentioning 0.9, got %v", conflicts)
+	}
+}
+
+func TestLoadOverlayFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+		return path
+	}
+
+	overlay, err := LoadOverlayFile(write("ok.json", `{"add":[{"id":"x","name":"X"}],"override":{"happy":{"color":"#000000"}},"hide":["bored"]}`))
+	if err != nil {
+		t.Fatalf("LoadOverlayFile() error: %v", err)
+	}
+	if len(overlay.Add) != 1 || *overlay.Override["happy"].Color != "#000000" || overlay.Hide[0] != "bored" || overlay.IsEmpty() {
+		t.Errorf("unexpected overlay: %+v", overlay)
+	}
+
+	if overlay, err := LoadOverlayFile(write("empty.json", "  \n")); err != nil || !overlay.IsEmpty() {
+		t.Errorf("empty file: overlay %+v, err %v; want an empty overlay", overlay, err)
+	}
+	if _, err := LoadOverlayFile(write("typo.json", `{"hidden":["bored"]}`)); err == nil {
+		t.Error("unknown field was accepted")
+	}
+	if _, err := LoadOverlayFile(filepath.Join(dir, "missing.json")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing file error = %v, want fs.ErrNotExist", err)
+	}
+}
+
+// TestTypeForDepth checks that types are ordered by Level, not by name, for
+// any number of levels.
+func TestTypeForDepth(t *testing.T) {
+	fiveLevels := EmotionData{EmotionTypes: map[string]EmotionType{
+		"core":    {ID: "core", Level: 1},
+		"family":  {ID: "family", Level: 2},
+		"word":    {ID: "word", Level: 3},
+		"nuance":  {ID: "nuance", Level: 4},
+		"variant": {ID: "variant", Level: 5},
+	}}
+	for depth, want := range []string{"core", "family", "word", "nuance", "variant", "variant"} {
+		if got := fiveLevels.TypeForDepth(depth); got != want {
+			t.Errorf("TypeForDepth(%d) = %q, want %q", depth, got, want)
+		}
+	}
+	if got := (EmotionData{}).TypeForDepth(0); got != "" {
+		t.Errorf("TypeForDepth without types = %q, want \"\"", got)
+	}
+}
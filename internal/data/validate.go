@@ -0,0 +1,230 @@
+// internal/data/validate.go
+package data
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ValidationError describes one problem with a dataset.
+type ValidationError struct {
+	EmotionID string // Emotion the problem concerns ("" for dataset-wide problems)
+	Message   string
+}
+
+func (e ValidationError) Error() string {
+	if e.EmotionID == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.EmotionID, e.Message)
+}
+
+// Validate checks a dataset for problems that would break the app or old
+// journals: missing or mismatched IDs, unknown types and parents, parent
+// cycles, bad colors, out-of-range dimensions, negative or tied ranks and
+// dangling aliases. Problems are sorted by emotion ID.
+// An empty result means the dataset is valid.
+func Validate(emotionData EmotionData) []ValidationError {
+	var problems []ValidationError
+	add := func(id, format string, args ...any) {
+		problems = append(problems, ValidationError{EmotionID: id, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(emotionData.Emotions) == 0 {
+		add("", "dataset defines no emotions")
+	}
+
+	roots := 0
+	for key, emotion := range emotionData.Emotions {
+		switch {
+		case emotion.ID == "":
+			add(key, "missing id")
+		case emotion.ID != key:
+			add(key, "id '%s' does not match its key", emotion.ID)
+		case strings.TrimSpace(emotion.ID) != emotion.ID || strings.ContainsAny(emotion.ID, " \t\n"):
+			add(key, "id must not contain whitespace")
+		}
+		if strings.TrimSpace(emotion.Name) == "" {
+			add(key, "missing name")
+		}
+		if len(emotionData.EmotionTypes) > 0 {
+			if _, ok := emotionData.EmotionTypes[emotion.Type]; !ok {
+				add(key, "unknown type '%s'", emotion.Type)
+			}
+		}
+		if emotion.Color != "" {
+			if _, err := ParseHexColor(emotion.Color); err != nil {
+				add(key, "invalid color '%s': %v", emotion.Color, err)
+			}
+		}
+		for _, dimension := range emotion.dimensions() {
+			if dimension.value != nil && !InDimensionRange(*dimension.value) {
+				add(key, "%s %g is outside -1..1", dimension.name, *dimension.value)
+			}
+		}
+		for _, rank := range emotion.ranks() {
+			if *rank.value < 0 {
+				add(key, "%s %d is negative", rank.name, *rank.value)
+			}
+		}
+		parents := emotion.Parents()
+		if len(parents) == 0 {
+			roots++
+		}
+		validParents := true
+		for _, parentID := range parents {
+			if parentID == key {
+				add(key, "is its own parent")
+				validParents = false
+			} else if _, ok := emotionData.Emotions[parentID]; !ok {
+				add(key, "unknown parent '%s'", parentID)
+				validParents = false
+			}
+		}
+		if validParents && len(parents) > 0 && inParentCycle(key, emotionData.Emotions) {
+			add(key, "is part of a parent cycle")
+		}
+	}
+	if len(emotionData.Emotions) > 0 && roots == 0 {
+		add("", "no top-level emotions (every emotion has a parent)")
+	}
+	for _, tie := range intensityTies(emotionData.Emotions) {
+		add(tie[1], "intensity %d is also used by sibling '%s'", emotionData.Emotions[tie[1]].Intensity, tie[0])
+	}
+
+	for _, alias := range emotionData.Aliases {
+		if _, exists := emotionData.Emotions[alias.From]; exists {
+			add(alias.From, "alias from an ID that still exists")
+		}
+		if !aliasResolves(alias.To, emotionData) {
+			add(alias.From, "alias target '%s' does not resolve to an emotion", alias.To)
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].EmotionID < problems[j].EmotionID
+	})
+	return problems
+}
+
+// InDimensionRange reports whether v is a valid valence, arousal or
+// dominance value (-1 to 1).
+func InDimensionRange(v float64) bool {
+	return v >= -1 && v <= 1
+}
+
+// dimension is a named circumplex value of an emotion, for checks and CSV columns.
+type dimension struct {
+	name  string
+	value *float64
+}
+
+// dimensions lists the emotion's circumplex values by field name.
+func (e Emotion) dimensions() []dimension {
+	return []dimension{{"valence", e.Valence}, {"arousal", e.Arousal}, {"dominance", e.Dominance}}
+}
+
+// rank is a named ordering value of an emotion (0 = unset), for checks and
+// CSV columns.
+type rank struct {
+	name  string
+	value *int
+}
+
+// ranks lists the emotion's order and intensity by field name. The values
+// point into e, so decoders can fill them in.
+func (e *Emotion) ranks() []rank {
+	return []rank{{"order", &e.Order}, {"intensity", &e.Intensity}}
+}
+
+// intensityTies returns pairs of siblings that share an intensity, which
+// would make their intensity ladder ambiguous. Each pair holds the smaller
+// ID first.
+func intensityTies(emotions map[string]Emotion) [][2]string {
+	rungs := make(map[string]map[int]string) // Parent ID ("" for roots) -> intensity -> emotion ID
+	ids := make([]string, 0, len(emotions))
+	for id := range emotions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids) // Report ties the same way every time
+	var ties [][2]string
+	for _, id := range ids {
+		emotion := emotions[id]
+		if emotion.Intensity <= 0 {
+			continue
+		}
+		parents := emotion.Parents()
+		if len(parents) == 0 {
+			parents = []string{""}
+		}
+		for _, parentID := range parents {
+			if rungs[parentID] == nil {
+				rungs[parentID] = make(map[int]string)
+			}
+			if other, taken := rungs[parentID][emotion.Intensity]; taken {
+				ties = append(ties, [2]string{other, id})
+			} else {
+				rungs[parentID][emotion.Intensity] = id
+			}
+		}
+	}
+	return ties
+}
+
+// inParentCycle reports whether following parent links (through any of an
+// emotion's parents, see Emotion.Parents) from id leads back to id.
+func inParentCycle(id string, emotions map[string]Emotion) bool {
+	visited := map[string]bool{}
+	pending := emotions[id].Parents()
+	for len(pending) > 0 {
+		current := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if current == id {
+			return true
+		}
+		if visited[current] {
+			continue // Reached through another parent, or a cycle further up that doesn't include id
+		}
+		visited[current] = true
+		pending = append(pending, emotions[current].Parents()...)
+	}
+	return false
+}
+
+// aliasResolves reports whether id is an emotion or an alias chain ending at one.
+func aliasResolves(id string, emotionData EmotionData) bool {
+	targets := make(map[string]string, len(emotionData.Aliases))
+	for _, alias := range emotionData.Aliases {
+		targets[alias.From] = alias.To
+	}
+	for steps := 0; steps <= len(targets); steps++ {
+		if _, ok := emotionData.Emotions[id]; ok {
+			return true
+		}
+		next, ok := targets[id]
+		if !ok {
+			return false
+		}
+		id = next
+	}
+	return false // Alias cycle
+}
+
+// SaveEmotionsFile validates a dataset and writes it to path in the format
+// its extension names (JSON, the format of emotions.json, for anything
+// else). Invalid datasets are not written.
+func SaveEmotionsFile(path string, emotionData EmotionData) error {
+	if problems := Validate(emotionData); len(problems) > 0 {
+		return fmt.Errorf("dataset is invalid (%d problems), first: %w", len(problems), problems[0])
+	}
+	raw, err := marshalFormat(emotionData, fileFormat(path))
+	if err != nil {
+		return fmt.Errorf("marshalling dataset: %w", err)
+	}
+	if err := os.WriteFile(path, raw, 0644); err != nil {
+		return fmt.Errorf("writing dataset file '%s': %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,364 @@
+// internal/diagnostics/diagnostics.go
+package diagnostics
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/itsforsxm123/emotion-explorer/internal/journal"
+	"github.com/itsforsxm123/emotion-explorer/internal/settings"
+)
+
+// --- Diagnostics Bundle ---
+//
+// A diagnostics bundle is a zip file users attach to bug reports:
+//
+//	report.json   everything below, machine-readable
+//	summary.txt   the same for people
+//	recent.log    the last lines of the log file
+//
+// It describes the app and its data without their content: the journal is
+// summarized by counts (never notes or logged emotions), log lines have the
+// emotions of journal entries and routes blanked out, the saved route is cut
+// to its kind, and the home directory is shortened to "~" in every path.
+
+// MaxLogLines is how many of the most recent log lines a bundle includes.
+const MaxLogLines = 200
+
+// Redacted replaces personal values in a bundle.
+const Redacted = "[redacted]"
+
+// AppInfo describes the running program.
+type AppInfo struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	GoVersion string `json:"goVersion"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+	Driver    string `json:"driver"` // Fyne driver, or how the bundle was made (e.g. "command line")
+}
+
+// DatasetInfo describes the dataset in use (with the user's overlay applied).
+type DatasetInfo struct {
+	Source           string        `json:"source"` // File path, or "built-in"
+	Metadata         data.Metadata `json:"metadata"`
+	Emotions         int           `json:"emotions"`
+	Aliases          int           `json:"aliases"`
+	Levels           int           `json:"levels"`
+	Problems         []string      `json:"problems,omitempty"`         // data.Validate results
+	OverlayConflicts []string      `json:"overlayConflicts,omitempty"` // Overlay changes that couldn't be applied
+}
+
+// JournalStats summarizes the journal by counts only.
+type JournalStats struct {
+	FileSize         int64                     `json:"fileSize"` // Bytes; 0 if there is no journal yet
+	Entries          int                       `json:"entries"`
+	WithNotes        int                       `json:"withNotes"`
+	MoodMeter        int                       `json:"moodMeter"` // Entries logged on the mood meter
+	DistinctEmotions int                       `json:"distinctEmotions"`
+	SchemaVersions   map[int]int               `json:"schemaVersions,omitempty"` // Version -> entries
+	WellFormed       bool                      `json:"wellFormed"`
+	Issues           map[journal.IssueKind]int `json:"issues,omitempty"` // Integrity check results per kind
+	Error            string                    `json:"error,omitempty"`  // Why the journal couldn't be checked
+}
+
+// Report is the content of a bundle.
+type Report struct {
+	GeneratedAt time.Time         `json:"generatedAt"`
+	App         AppInfo           `json:"app"`
+	Paths       map[string]string `json:"paths"` // What -> where, e.g. "journal" -> "~/journal.json"
+	Dataset     DatasetInfo       `json:"dataset"`
+	Journal     JournalStats      `json:"journal"`
+	Settings    settings.Settings `json:"settings"`
+	LogLines    []string          `json:"-"` // Written to recent.log
+	LogError    string            `json:"logError,omitempty"`
+}
+
+// Sources is what Collect reads a report from.
+type Sources struct {
+	App              AppInfo
+	Dataset          data.EmotionData // Dataset in use, overlay applied
+	DatasetPath      string           // "" for the built-in dataset
+	OverlayConflicts []data.OverlayConflict
+	JournalPath      string                // "" for the active journal, read under its lock
+	Lookup           journal.EmotionLookup // Resolves journal emotion IDs; nil skips dataset checks
+	Settings         settings.Settings
+	LogPath          string            // Log file to take the recent lines from
+	Paths            map[string]string // Further data paths to list
+	HomeDir          string            // Shortened to "~" in paths; "" for the user's home directory
+}
+
+// Collect gathers a redacted report. Problems reading the journal or the log
+// are recorded in the report rather than returned, so a bundle can always be
+// made; they are often what the bug report is about.
+func Collect(src Sources, now time.Time) Report {
+	home := src.HomeDir
+	if home == "" {
+		home, _ = os.UserHomeDir()
+	}
+	report := Report{
+		GeneratedAt: now,
+		App:         src.App,
+		Paths:       make(map[string]string),
+		Settings:    RedactSettings(src.Settings, home),
+	}
+	for name, path := range src.Paths {
+		report.Paths[name] = RedactPath(path, home)
+	}
+	if src.LogPath != "" {
+		report.Paths["log"] = RedactPath(src.LogPath, home)
+	}
+
+	// --- Dataset ---
+	report.Dataset = DatasetInfo{
+		Source:   "built-in",
+		Metadata: src.Dataset.Metadata,
+		Emotions: len(src.Dataset.Emotions),
+		Aliases:  len(src.Dataset.Aliases),
+		Levels:   core.LevelCount(src.Dataset.Emotions),
+	}
+	if src.DatasetPath != "" {
+		report.Dataset.Source = RedactPath(src.DatasetPath, home)
+	}
+	for _, problem := range data.Validate(src.Dataset) {
+		report.Dataset.Problems = append(report.Dataset.Problems, problem.Error())
+	}
+	for _, conflict := range src.OverlayConflicts {
+		report.Dataset.OverlayConflicts = append(report.Dataset.OverlayConflicts, RedactPath(conflict.Message, home))
+	}
+
+	// --- Journal ---
+	journalPath := src.JournalPath
+	var check journal.Report
+	var err error
+	if journalPath == "" {
+		journalPath = journal.FilePath()
+		check, err = journal.Check(src.Lookup, now)
+	} else {
+		check, err = journal.CheckFile(journalPath, src.Lookup, now)
+	}
+	report.Paths["journal"] = RedactPath(journalPath, home)
+	if err != nil {
+		report.Journal.Error = RedactPath(err.Error(), home)
+	} else {
+		report.Journal = JournalStatsFor(check)
+	}
+	if info, statErr := os.Stat(journalPath); statErr == nil {
+		report.Journal.FileSize = info.Size()
+	}
+
+	// --- Log ---
+	if src.LogPath != "" {
+		lines, err := RecentLogLines(src.LogPath, MaxLogLines)
+		if err != nil {
+			report.LogError = RedactPath(err.Error(), home)
+		}
+		for _, line := range lines {
+			report.LogLines = append(report.LogLines, RedactLogLine(line, home))
+		}
+	}
+	slog.Info("Collected diagnostics", "entries", report.Journal.Entries, "logLines", len(report.LogLines))
+	return report
+}
+
+// JournalStatsFor counts what a journal check found.
+func JournalStatsFor(check journal.Report) JournalStats {
+	stats := JournalStats{WellFormed: check.WellFormed, Entries: len(check.Entries)}
+	emotions := make(map[string]bool)
+	for _, entry := range check.Entries {
+		if strings.TrimSpace(entry.Notes) != "" {
+			stats.WithNotes++
+		}
+		if entry.Valence != nil && entry.Arousal != nil {
+			stats.MoodMeter++
+		}
+		emotions[entry.EmotionID] = true
+		if stats.SchemaVersions == nil {
+			stats.SchemaVersions = make(map[int]int)
+		}
+		stats.SchemaVersions[entry.SchemaVersion]++
+	}
+	stats.DistinctEmotions = len(emotions)
+	for _, issue := range check.Issues {
+		if stats.Issues == nil {
+			stats.Issues = make(map[journal.IssueKind]int)
+		}
+		stats.Issues[issue.Kind]++
+	}
+	return stats
+}
+
+// --- Redaction ---
+
+// personalLogKeys are log attributes that can name what the user logged or
+// where they were (see internal/logging's privacy policy).
+var personalLogKeys = regexp.MustCompile(`\b(note|emotionID|startedAt|parentID|current|route)=("(?:[^"\\]|\\.)*"|\S*)`)
+
+// RedactLogLine blanks out personal attribute values in a log line and
+// shortens paths under home.
+func RedactLogLine(line, home string) string {
+	line = personalLogKeys.ReplaceAllString(line, "$1="+Redacted)
+	return RedactPath(line, home)
+}
+
+// RedactPath replaces the home directory in s with "~".
+func RedactPath(s, home string) string {
+	if home == "" || home == "/" {
+		return s
+	}
+	return strings.ReplaceAll(s, home, "~")
+}
+
+// RedactSettings returns settings safe to share: the saved route keeps only
+// its kind (e.g. "log", not which emotion the user was logging) and paths
+// are shortened.
+func RedactSettings(s settings.Settings, home string) settings.Settings {
+	if kind, _, found := strings.Cut(s.LastRoute, "/"); found {
+		s.LastRoute = kind + "/" + Redacted
+	}
+	s.DatasetPath = RedactPath(s.DatasetPath, home)
+	return s
+}
+
+// RecentLogLines returns the last n lines of the log file at path, reaching
+// into its newest rotated file (path.1) if the current one is shorter.
+func RecentLogLines(path string, n int) ([]string, error) {
+	var lines []string
+	for _, name := range []string{path + ".1", path} {
+		file, err := os.Open(name)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return lines, fmt.Errorf("reading log file: %w", err)
+		}
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+			if len(lines) > n {
+				lines = lines[len(lines)-n:]
+			}
+		}
+		err = scanner.Err()
+		file.Close()
+		if err != nil {
+			return lines, fmt.Errorf("reading log file: %w", err)
+		}
+	}
+	return lines, nil
+}
+
+// --- Writing ---
+
+// FileName returns the suggested name of a bundle made at t.
+func FileName(t time.Time) string {
+	return "emotion-explorer-diagnostics-" + t.Format("20060102-150405") + ".zip"
+}
+
+// Write writes report as a zip bundle to w.
+func Write(w io.Writer, report Report) error {
+	archive := zip.NewWriter(w)
+	reportJSON, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding diagnostics report: %w", err)
+	}
+	files := []struct {
+		name    string
+		content []byte
+	}{
+		{"report.json", append(reportJSON, '\n')},
+		{"summary.txt", []byte(report.Summary())},
+		{"recent.log", []byte(strings.Join(report.LogLines, "\n") + "\n")},
+	}
+	for _, file := range files {
+		header := &zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: report.GeneratedAt}
+		fw, err := archive.CreateHeader(header)
+		if err != nil {
+			return fmt.Errorf("adding %s to diagnostics bundle: %w", file.name, err)
+		}
+		if _, err := fw.Write(file.content); err != nil {
+			return fmt.Errorf("adding %s to diagnostics bundle: %w", file.name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("writing diagnostics bundle: %w", err)
+	}
+	return nil
+}
+
+// WriteFile writes report as a zip bundle to path.
+func WriteFile(path string, report Report) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating diagnostics bundle: %w", err)
+	}
+	if err := Write(file, report); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Summary formats the report for people.
+func (r Report) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s diagnostics, %s\n", r.App.Name, r.App.Version, r.GeneratedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Go %s on %s/%s, driver: %s\n", r.App.GoVersion, r.App.OS, r.App.Arch, r.App.Driver)
+
+	b.WriteString("\nPaths:\n")
+	names := make([]string, 0, len(r.Paths))
+	for name := range r.Paths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, "  %s: %s\n", name, r.Paths[name])
+	}
+
+	d := r.Dataset
+	fmt.Fprintf(&b, "\nDataset: %s (version %q, source %q)\n", d.Source, d.Metadata.Version, d.Metadata.Source)
+	fmt.Fprintf(&b, "  %d emotions in %d levels, %d aliases\n", d.Emotions, d.Levels, d.Aliases)
+	fmt.Fprintf(&b, "  %d validation problems, %d overlay conflicts\n", len(d.Problems), len(d.OverlayConflicts))
+	for _, problem := range d.Problems {
+		fmt.Fprintf(&b, "  - %s\n", problem)
+	}
+	for _, conflict := range d.OverlayConflicts {
+		fmt.Fprintf(&b, "  - overlay: %s\n", conflict)
+	}
+
+	j := r.Journal
+	fmt.Fprintf(&b, "\nJournal: %d entries (%d bytes), well-formed: %v\n", j.Entries, j.FileSize, j.WellFormed)
+	if j.Error != "" {
+		fmt.Fprintf(&b, "  error: %s\n", j.Error)
+	}
+	fmt.Fprintf(&b, "  %d with notes, %d on the mood meter, %d distinct emotions\n", j.WithNotes, j.MoodMeter, j.DistinctEmotions)
+	kinds := make([]string, 0, len(j.Issues))
+	for kind := range j.Issues {
+		kinds = append(kinds, string(kind))
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		fmt.Fprintf(&b, "  %s: %d\n", kind, j.Issues[journal.IssueKind(kind)])
+	}
+
+	s := r.Settings
+	fmt.Fprintf(&b, "\nSettings: locale %q, sort %q, colorblind palette %v, last route %q\n", s.Locale, s.SortMode, s.ColorblindPalette, s.LastRoute)
+	fmt.Fprintf(&b, "\nRecent log: %d lines", len(r.LogLines))
+	if r.LogError != "" {
+		fmt.Fprintf(&b, " (%s)", r.LogError)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
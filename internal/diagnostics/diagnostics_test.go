@@ -0,0 +1,201 @@
+// internal/diagnostics/diagnostics_test.go
+package diagnostics
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/itsforsxm123/emotion-explorer/internal/settings"
+)
+
+// TestRedactLogLine tests that personal attribute values and the home
+// directory are removed from log lines.
+func TestRedactLogLine(t *testing.T) {
+	testCases := []struct {
+		line string
+		want string
+	}{
+		{
+			line: `level=INFO msg="Saved journal entry" entries=12`,
+			want: `level=INFO msg="Saved journal entry" entries=12`,
+		},
+		{
+			line: `level=DEBUG msg="Saving journal entry" emotionID=lonely time=2026-10-18T09:00:00Z`,
+			want: `level=DEBUG msg="Saving journal entry" emotionID=[redacted] time=2026-10-18T09:00:00Z`,
+		},
+		{
+			line: `level=DEBUG msg="Restored session" route=log/sad`,
+			want: `level=DEBUG msg="Restored session" route=[redacted]`,
+		},
+		{
+			line: `level=INFO msg=saved note="a \"bad\" day" entries=1`,
+			want: `level=INFO msg=saved note=[redacted] entries=1`,
+		},
+		{
+			line: `level=INFO msg="Settings saved" path=/home/sam/emotions/settings.json`,
+			want: `level=INFO msg="Settings saved" path=~/emotions/settings.json`,
+		},
+	}
+	for _, tc := range testCases {
+		if got := RedactLogLine(tc.line, "/home/sam"); got != tc.want {
+			t.Errorf("RedactLogLine(%q)\n got %q\nwant %q", tc.line, got, tc.want)
+		}
+	}
+}
+
+// TestRedactSettings tests that the saved route keeps only its kind.
+func TestRedactSettings(t *testing.T) {
+	testCases := []struct {
+		route string
+		want  string
+	}{
+		{route: "", want: ""},
+		{route: "browse", want: "browse"},
+		{route: "log/sad/lonely", want: "log/[redacted]"},
+		{route: "history?range=7d", want: "history?range=7d"},
+	}
+	for _, tc := range testCases {
+		got := RedactSettings(settings.Settings{LastRoute: tc.route, DatasetPath: "/home/sam/words.csv"}, "/home/sam")
+		if got.LastRoute != tc.want {
+			t.Errorf("LastRoute %q redacted to %q, want %q", tc.route, got.LastRoute, tc.want)
+		}
+		if got.DatasetPath != "~/words.csv" {
+			t.Errorf("DatasetPath = %q, want ~/words.csv", got.DatasetPath)
+		}
+	}
+}
+
+// TestRecentLogLines tests taking the last lines across a rotation.
+func TestRecentLogLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path+".1", []byte("one\ntwo\nthree\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("four\nfive\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	lines, err := RecentLogLines(path, 3)
+	if err != nil {
+		t.Fatalf("RecentLogLines failed: %v", err)
+	}
+	if strings.Join(lines, ",") != "three,four,five" {
+		t.Errorf("Expected the last three lines, got %v", lines)
+	}
+
+	lines, err = RecentLogLines(filepath.Join(t.TempDir(), "missing.log"), 3)
+	if err != nil || len(lines) != 0 {
+		t.Errorf("Expected no lines and no error for a missing log, got %v, %v", lines, err)
+	}
+}
+
+// TestBundle tests that a bundle counts the journal and carries the log
+// without any note or logged emotion.
+func TestBundle(t *testing.T) {
+	dir := t.TempDir()
+	entries := `[
+  {"id": "a", "schema_version": 1, "timestamp": "2026-10-01T09:00:00Z", "emotion_id": "lonely", "emotion_name": "Lonely", "notes": "my secret day"},
+  {"id": "b", "schema_version": 1, "timestamp": "2026-10-02T09:00:00Z", "emotion_id": "lonely", "emotion_name": "Lonely"},
+  {"id": "c", "schema_version": 1, "timestamp": "2026-10-03T09:00:00Z", "emotion_id": "peaceful", "emotion_name": "Peaceful", "valence": 0.5, "arousal": -0.2}
+]`
+	journalPath := filepath.Join(dir, "journal.json")
+	if err := os.WriteFile(journalPath, []byte(entries), 0644); err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(dir, "emotion-explorer.log")
+	logLines := "level=DEBUG msg=\"Saving journal entry\" emotionID=lonely\nlevel=INFO msg=\"Saved journal entry\" entries=3\n"
+	if err := os.WriteFile(logPath, []byte(logLines), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	emotions := map[string]data.Emotion{
+		"sad":      {ID: "sad", Name: "Sad"},
+		"lonely":   {ID: "lonely", Name: "Lonely", ParentID: "sad"},
+		"peaceful": {ID: "peaceful", Name: "Peaceful"},
+	}
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	report := Collect(Sources{
+		App:         AppInfo{Name: "Emotion Explorer", Version: "1.2.3", Driver: "test"},
+		Dataset:     data.EmotionData{Metadata: data.Metadata{Version: "9.9"}, Emotions: emotions},
+		JournalPath: journalPath,
+		Settings:    settings.Settings{Locale: "es", LastRoute: "log/sad"},
+		LogPath:     logPath,
+		HomeDir:     dir,
+	}, now)
+
+	want := JournalStats{FileSize: int64(len(entries)), Entries: 3, WithNotes: 1, MoodMeter: 1, DistinctEmotions: 2, WellFormed: true}
+	got := report.Journal
+	if got.FileSize != want.FileSize || got.Entries != want.Entries || got.WithNotes != want.WithNotes ||
+		got.MoodMeter != want.MoodMeter || got.DistinctEmotions != want.DistinctEmotions || got.WellFormed != want.WellFormed {
+		t.Errorf("Journal stats = %+v, want %+v", got, want)
+	}
+	if got.SchemaVersions[1] != 3 {
+		t.Errorf("Expected 3 entries at schema version 1, got %v", got.SchemaVersions)
+	}
+	if report.Paths["journal"] != "~/journal.json" {
+		t.Errorf("Journal path = %q, want it under ~", report.Paths["journal"])
+	}
+	if report.Dataset.Levels != 2 || report.Dataset.Metadata.Version != "9.9" {
+		t.Errorf("Unexpected dataset info: %+v", report.Dataset)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, report); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Bundle is not a zip: %v", err)
+	}
+	names := []string{}
+	var all strings.Builder
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		all.Write(content)
+	}
+	if strings.Join(names, ",") != "report.json,summary.txt,recent.log" {
+		t.Errorf("Unexpected bundle files: %v", names)
+	}
+	for _, s := range []string{"secret", "lonely", "Lonely", "peaceful", "log/sad", dir} {
+		if strings.Contains(all.String(), s) {
+			t.Errorf("Bundle contains personal content %q", s)
+		}
+	}
+	for _, s := range []string{`"version": "1.2.3"`, `"withNotes": 1`, "entries=3", `"locale": "es"`} {
+		if !strings.Contains(all.String(), s) {
+			t.Errorf("Expected %q in the bundle", s)
+		}
+	}
+}
+
+// TestBundleBrokenJournal tests that an unreadable journal is reported, not
+// fatal.
+func TestBundleBrokenJournal(t *testing.T) {
+	dir := t.TempDir()
+	journalPath := filepath.Join(dir, "journal.json")
+	if err := os.Mkdir(journalPath, 0755); err != nil { // A directory can't be read as a file
+		t.Fatal(err)
+	}
+	report := Collect(Sources{JournalPath: journalPath, HomeDir: dir}, time.Now())
+	if report.Journal.Error == "" {
+		t.Error("Expected the journal error in the report")
+	}
+	if strings.Contains(report.Journal.Error, dir) {
+		t.Errorf("Journal error not redacted: %s", report.Journal.Error)
+	}
+	if !strings.Contains(report.Summary(), "error:") {
+		t.Errorf("Expected the error in the summary:\n%s", report.Summary())
+	}
+}
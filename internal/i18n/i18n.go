@@ -0,0 +1,161 @@
+// internal/i18n/i18n.go
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is the last entry of every fallback chain.
+// Its catalog is expected to contain every key.
+const DefaultLocale = "en"
+
+// Embed the UI string catalogs, one JSON object of key -> text per locale.
+//
+//go:embed locales/*.json
+var embeddedCatalogs embed.FS
+
+var (
+	catalogs     map[string]map[string]string // Locale -> key -> translated text
+	currentChain = []string{DefaultLocale}    // Fallback chain of the active locale
+	i18nMutex    sync.RWMutex                 // Protects currentChain
+)
+
+// init loads the embedded catalogs once at startup.
+func init() {
+	loaded, err := loadCatalogs()
+	if err != nil {
+		// The catalogs are compiled in, so this only happens with a broken build
+		slog.Error("Failed to load translation catalogs", "err", err)
+	}
+	catalogs = loaded
+}
+
+// loadCatalogs parses every embedded locales/<locale>.json file.
+func loadCatalogs() (map[string]map[string]string, error) {
+	files, err := embeddedCatalogs.ReadDir("locales")
+	if err != nil {
+		return nil, fmt.Errorf("reading embedded locales: %w", err)
+	}
+	loaded := make(map[string]map[string]string, len(files))
+	for _, file := range files {
+		raw, err := embeddedCatalogs.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading catalog '%s': %w", file.Name(), err)
+		}
+		var strs map[string]string
+		if err := json.Unmarshal(raw, &strs); err != nil {
+			return nil, fmt.Errorf("unmarshalling catalog '%s': %w", file.Name(), err)
+		}
+		loaded[NormalizeLocale(strings.TrimSuffix(file.Name(), ".json"))] = strs
+	}
+	return loaded, nil
+}
+
+// NormalizeLocale converts system-style locale names such as "pt_BR.UTF-8"
+// into the "pt-BR" form used by the catalogs and the dataset.
+// Returns an empty string for empty, "C" or "POSIX" locales.
+func NormalizeLocale(locale string) string {
+	locale = strings.TrimSpace(locale)
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i] // Drop encoding and modifier suffixes
+	}
+	if locale == "" || locale == "C" || locale == "POSIX" {
+		return ""
+	}
+	parts := strings.Split(strings.ReplaceAll(locale, "_", "-"), "-")
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) == 2 {
+			parts[i] = strings.ToUpper(parts[i]) // Region code, e.g. BR
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// FallbackChain returns the locales to try, most specific first, ending with
+// DefaultLocale. For example "pt_BR" gives ["pt-BR", "pt", "en"].
+func FallbackChain(locale string) []string {
+	chain := make([]string, 0, 3)
+	seen := make(map[string]bool)
+	addLocale := func(l string) {
+		if l != "" && !seen[l] {
+			seen[l] = true
+			chain = append(chain, l)
+		}
+	}
+
+	parts := strings.Split(NormalizeLocale(locale), "-")
+	for i := len(parts); i > 0; i-- {
+		addLocale(strings.Join(parts[:i], "-"))
+	}
+	addLocale(DefaultLocale)
+	return chain
+}
+
+// SetLocale makes locale the active language for T and Chain.
+// An empty locale selects DefaultLocale.
+func SetLocale(locale string) {
+	chain := FallbackChain(locale)
+	i18nMutex.Lock()
+	defer i18nMutex.Unlock()
+	currentChain = chain
+	slog.Info("Locale set", "locale", chain[0], "fallbackChain", chain)
+}
+
+// Locale returns the active locale.
+func Locale() string {
+	i18nMutex.RLock()
+	defer i18nMutex.RUnlock()
+	return currentChain[0]
+}
+
+// Chain returns a copy of the active fallback chain.
+// Pass it to data.Emotion.LocalizedName and friends.
+func Chain() []string {
+	i18nMutex.RLock()
+	defer i18nMutex.RUnlock()
+	return append([]string(nil), currentChain...)
+}
+
+// T translates a UI string key using the active fallback chain and formats
+// it with args (fmt.Sprintf verbs). Unknown keys are returned unchanged so
+// missing translations are visible rather than blank.
+func T(key string, args ...any) string {
+	text := key
+	for _, locale := range Chain() {
+		if translated, ok := catalogs[locale][key]; ok {
+			text = translated
+			break
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// Available returns the locales that have a UI catalog, sorted.
+func Available() []string {
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// LanguageName returns the name of a locale's language in that language
+// (e.g. "Español" for "es"), as declared by its catalog.
+func LanguageName(locale string) string {
+	if name, ok := catalogs[NormalizeLocale(locale)]["language.name"]; ok {
+		return name
+	}
+	return locale
+}
@@ -0,0 +1,55 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFallbackChain tests locale normalization and fallback ordering.
+func TestFallbackChain(t *testing.T) {
+	testCases := []struct {
+		name     string
+		locale   string
+		expected []string
+	}{
+		{name: "Region with POSIX form", locale: "pt_BR.UTF-8", expected: []string{"pt-BR", "pt", "en"}},
+		{name: "Language only", locale: "es", expected: []string{"es", "en"}},
+		{name: "Mixed case", locale: "ES-mx", expected: []string{"es-MX", "es", "en"}},
+		{name: "Default locale is not repeated", locale: "en-GB", expected: []string{"en-GB", "en"}},
+		{name: "Empty locale", locale: "", expected: []string{"en"}},
+		{name: "POSIX C locale", locale: "C", expected: []string{"en"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, FallbackChain(tc.locale))
+		})
+	}
+}
+
+// TestT tests key lookup through the fallback chain.
+func TestT(t *testing.T) {
+	defer SetLocale(DefaultLocale)
+
+	SetLocale("es-MX")
+	assert.Equal(t, "es-MX", Locale())
+	assert.Equal(t, "Emociones primarias", T("view.primary.title"), "es-MX should fall back to es")
+	assert.Equal(t, "Explorando: Feliz", T("view.explore.title", "Feliz"))
+	assert.Equal(t, "no.such.key", T("no.such.key"), "Unknown keys are returned unchanged")
+
+	SetLocale("fr")
+	assert.Equal(t, "Primary Emotions", T("view.primary.title"), "Unsupported locales fall back to English")
+}
+
+// TestCatalogsComplete tests that every catalog translates every English key.
+func TestCatalogsComplete(t *testing.T) {
+	english := catalogs[DefaultLocale]
+	assert.NotEmpty(t, english)
+	for _, locale := range Available() {
+		for key := range english {
+			_, ok := catalogs[locale][key]
+			assert.True(t, ok, "Catalog '%s' is missing key '%s'", locale, key)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+// internal/ipc/ipc.go
+package ipc
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// --- Protocol ---
+//
+// A second launch of the app connects to the running instance over a local
+// Unix domain socket and sends one request; the instance answers with one
+// response. Both are single lines of JSON:
+//
+//	-> {"version":1,"command":"log-emotion","emotionId":"playful"}
+//	<- {"ok":true}
+//	<- {"ok":false,"error":"unknown emotion ID 'zoomed_out'"}
+
+// ProtocolVersion is sent with every request. Instances reject requests with
+// a different version rather than guessing what they mean.
+const ProtocolVersion = 1
+
+// Command names what the new invocation wants the running instance to do.
+type Command string
+
+const (
+	CommandShow       Command = "show"        // Show and focus the main window
+	CommandLog        Command = "log"         // Start the logging flow
+	CommandLogEmotion Command = "log-emotion" // Log Request.EmotionID right away
+	CommandHistory    Command = "history"     // Open the journal history
+	CommandOpen       Command = "open"        // Open Request.Route
+)
+
+// Request is sent by a new invocation to the running instance.
+type Request struct {
+	Version   int     `json:"version"`
+	Command   Command `json:"command"`
+	EmotionID string  `json:"emotionId,omitempty"` // For CommandLogEmotion
+	Route     string  `json:"route,omitempty"`     // For CommandOpen, e.g. "browse/happy/playful"
+}
+
+// Response reports whether the running instance carried out a request.
+type Response struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// Validate checks that a request is complete and understood by this build.
+func (r Request) Validate() error {
+	if r.Version != ProtocolVersion {
+		return fmt.Errorf("unsupported protocol version %d (expected %d)", r.Version, ProtocolVersion)
+	}
+	switch r.Command {
+	case CommandShow, CommandLog, CommandHistory:
+		return nil
+	case CommandLogEmotion:
+		if r.EmotionID == "" {
+			return errors.New("log-emotion requires an emotion ID")
+		}
+		return nil
+	case CommandOpen:
+		if r.Route == "" {
+			return errors.New("open requires a route")
+		}
+		return nil
+	case "":
+		return errors.New("missing command")
+	}
+	return fmt.Errorf("unknown command '%s'", r.Command)
+}
+
+// ErrAlreadyRunning is returned by Listen when another instance owns the socket.
+var ErrAlreadyRunning = errors.New("another instance is already running")
+
+// ErrNotRunning is returned (wrapped) by Send when no instance is listening.
+var ErrNotRunning = errors.New("no running instance")
+
+// Timeouts of an exchange. Carrying out a request can take longer than
+// talking about it: logging an emotion waits for the journal lock (up to
+// 5s when another process holds it), so the server allows handleTimeout
+// for that and the sender waits as long for the response. A sender that
+// gave up earlier would report a failure for an entry that still gets
+// saved, and a retry would log it twice.
+var (
+	ioTimeout     = 3 * time.Second  // Connecting, sending the request, writing the response
+	handleTimeout = 15 * time.Second // Carrying out the request
+)
+
+// maxLineSize bounds a request or response line; real ones are tiny.
+const maxLineSize = 4096
+
+// SocketPath returns the socket used by instances sharing dataDir. It is
+// named after a hash of the data directory, so instances working on
+// different journals don't interfere, and lives in a directory only the
+// user can enter (see socketDir), so other users can neither send requests
+// nor claim the name first.
+func SocketPath(dataDir string) string {
+	sum := sha256.Sum256([]byte(filepath.Clean(dataDir)))
+	return filepath.Join(socketDir(), fmt.Sprintf("emotion-explorer-%x.sock", sum[:6]))
+}
+
+// socketDir returns the per-user directory for instance sockets: under
+// $XDG_RUNTIME_DIR where there is one, else under the user's cache
+// directory, else a per-user directory in the temp directory. The paths stay
+// short, since Unix socket paths are limited to ~100 bytes. Listen creates
+// the directory with mode 0700.
+func socketDir() string {
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		return filepath.Join(runtimeDir, "emotion-explorer")
+	}
+	if cacheDir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(cacheDir, "emotion-explorer")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("emotion-explorer-%d", os.Getuid()))
+}
+
+// ensurePrivateDir creates dir (mode 0700) if needed and makes sure nobody
+// but the user can enter it. A directory someone else owns can't be
+// tightened and is an error.
+func ensurePrivateDir(dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("creating socket directory: %w", err)
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return fmt.Errorf("checking socket directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("socket directory '%s' is not a directory", dir)
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		if err := os.Chmod(dir, 0700); err != nil {
+			return fmt.Errorf("restricting socket directory: %w", err)
+		}
+	}
+	return nil
+}
+
+// --- Client ---
+
+// Send delivers req to the instance listening on socketPath and waits for
+// its response. An error wrapping ErrNotRunning means nobody is listening.
+func Send(socketPath string, req Request) error {
+	req.Version = ProtocolVersion
+	conn, err := net.DialTimeout("unix", socketPath, ioTimeout)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNotRunning, err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(ioTimeout))
+
+	if err := writeLine(conn, req); err != nil {
+		return fmt.Errorf("sending request: %w", err)
+	}
+	conn.SetDeadline(time.Now().Add(handleTimeout + ioTimeout)) // The instance is carrying it out
+	var resp Response
+	if err := readLine(bufio.NewReader(conn), &resp); err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+	if !resp.OK {
+		return fmt.Errorf("running instance refused '%s': %s", req.Command, resp.Error)
+	}
+	return nil
+}
+
+// --- Server ---
+
+// Handler carries out a validated request in the running instance.
+// A returned error is reported back to the sender.
+type Handler func(Request) error
+
+// Server accepts requests from later invocations.
+type Server struct {
+	listener net.Listener
+	path     string
+	handler  Handler
+	wg       sync.WaitGroup
+}
+
+// Listen claims socketPath for this instance and serves requests with
+// handler on background goroutines. If a live instance already owns the
+// socket it returns ErrAlreadyRunning; a socket left behind by a crashed
+// instance is removed and reclaimed. The socket's directory is made private
+// to the user (0700) and the socket itself is readable and writable by the
+// user only (0600).
+func Listen(socketPath string, handler Handler) (*Server, error) {
+	if err := ensurePrivateDir(filepath.Dir(socketPath)); err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		// Either someone is listening, or a dead instance left the file behind
+		if conn, dialErr := net.DialTimeout("unix", socketPath, ioTimeout); dialErr == nil {
+			conn.Close()
+			return nil, ErrAlreadyRunning
+		}
+		slog.Info("Removing stale instance socket", "path", socketPath)
+		if removeErr := os.Remove(socketPath); removeErr != nil && !os.IsNotExist(removeErr) {
+			return nil, fmt.Errorf("removing stale socket: %w", removeErr)
+		}
+		if listener, err = net.Listen("unix", socketPath); err != nil {
+			return nil, fmt.Errorf("listening on '%s': %w", socketPath, err)
+		}
+	}
+	if err := os.Chmod(socketPath, 0600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("restricting socket: %w", err)
+	}
+
+	s := &Server{listener: listener, path: socketPath, handler: handler}
+	s.wg.Add(1)
+	go s.serve()
+	slog.Info("Listening for other instances", "path", socketPath)
+	return s, nil
+}
+
+// Close stops accepting requests, waits for in-flight ones and removes the socket.
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	os.Remove(s.path) // Usually already removed by the listener
+	return err
+}
+
+// serve accepts connections until the listener is closed.
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				slog.Warn("Instance socket accept failed", "err", err)
+			}
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+// handle reads one request from conn, runs it and writes the response. The
+// handler runs without a deadline on the connection, so a slow request
+// (see handleTimeout) still gets its answer.
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(ioTimeout))
+
+	var req Request
+	resp := Response{OK: true}
+	if err := readLine(bufio.NewReader(conn), &req); err != nil {
+		resp = Response{Error: fmt.Sprintf("malformed request: %v", err)}
+	} else if err := req.Validate(); err != nil {
+		resp = Response{Error: err.Error()}
+	} else {
+		start := time.Now()
+		if err := s.handler(req); err != nil {
+			resp = Response{Error: err.Error()}
+		}
+		if elapsed := time.Since(start); elapsed > handleTimeout {
+			slog.Warn("Instance request took longer than the sender waits", "command", req.Command, "elapsed", elapsed)
+		}
+	}
+	slog.Info("Instance request handled", "command", req.Command, "ok", resp.OK)
+	conn.SetDeadline(time.Now().Add(ioTimeout))
+	if err := writeLine(conn, resp); err != nil {
+		slog.Warn("Failed to answer instance request", "err", err)
+	}
+}
+
+// writeLine writes v as one line of JSON.
+func writeLine(conn net.Conn, v any) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = conn.Write(append(line, '\n'))
+	return err
+}
+
+// readLine reads one line of JSON into v.
+func readLine(r *bufio.Reader, v any) error {
+	var line []byte
+	for {
+		chunk, isPrefix, err := r.ReadLine()
+		if err != nil {
+			return err
+		}
+		line = append(line, chunk...)
+		if len(line) > maxLineSize {
+			return fmt.Errorf("line longer than %d bytes", maxLineSize)
+		}
+		if !isPrefix {
+			break
+		}
+	}
+	return json.Unmarshal(line, v)
+}
@@ -0,0 +1,238 @@
+package ipc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testSocketPath returns a short socket path in a fresh temp directory.
+// (t.TempDir() paths can exceed the Unix socket path limit.)
+func testSocketPath(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "ipc")
+	if err != nil {
+		t.Fatalf("MkdirTemp() failed: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "s.sock")
+}
+
+// TestRequestValidate tests which requests a running instance accepts.
+func TestRequestValidate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		req       Request
+		expectErr bool
+	}{
+		{"Show", Request{Version: ProtocolVersion, Command: CommandShow}, false},
+		{"Log", Request{Version: ProtocolVersion, Command: CommandLog}, false},
+		{"History", Request{Version: ProtocolVersion, Command: CommandHistory}, false},
+		{"Log emotion", Request{Version: ProtocolVersion, Command: CommandLogEmotion, EmotionID: "playful"}, false},
+		{"Log emotion without ID", Request{Version: ProtocolVersion, Command: CommandLogEmotion}, true},
+		{"Open", Request{Version: ProtocolVersion, Command: CommandOpen, Route: "history?range=7d"}, false},
+		{"Open without route", Request{Version: ProtocolVersion, Command: CommandOpen}, true},
+		{"Missing command", Request{Version: ProtocolVersion}, true},
+		{"Unknown command", Request{Version: ProtocolVersion, Command: "dance"}, true},
+		{"Wrong version", Request{Version: ProtocolVersion + 1, Command: CommandShow}, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.req.Validate(); (err != nil) != tc.expectErr {
+				t.Errorf("Validate() error = %v, expected error: %v", err, tc.expectErr)
+			}
+		})
+	}
+}
+
+// TestSendToRunningInstance tests a full request/response round trip.
+func TestSendToRunningInstance(t *testing.T) {
+	path := testSocketPath(t)
+	var mu sync.Mutex
+	var received []Request
+	server, err := Listen(path, func(req Request) error {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, req)
+		if req.EmotionID == "zoomed_out" {
+			return fmt.Errorf("unknown emotion ID '%s'", req.EmotionID)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Listen() returned an unexpected error: %v", err)
+	}
+	defer server.Close()
+
+	if err := Send(path, Request{Command: CommandLogEmotion, EmotionID: "playful"}); err != nil {
+		t.Fatalf("Send() returned an unexpected error: %v", err)
+	}
+	err = Send(path, Request{Command: CommandLogEmotion, EmotionID: "zoomed_out"})
+	if err == nil || !strings.Contains(err.Error(), "zoomed_out") {
+		t.Errorf("Expected the handler's error to reach the sender, got %v", err)
+	}
+	if err := Send(path, Request{Command: "dance"}); err == nil {
+		t.Errorf("Expected an invalid request to be refused")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 {
+		t.Fatalf("Expected the handler to see 2 valid requests, got %d", len(received))
+	}
+	if received[0].Command != CommandLogEmotion || received[0].EmotionID != "playful" || received[0].Version != ProtocolVersion {
+		t.Errorf("Unexpected request: %+v", received[0])
+	}
+}
+
+// TestMalformedRequest tests that garbage on the socket gets an error response.
+func TestMalformedRequest(t *testing.T) {
+	path := testSocketPath(t)
+	server, err := Listen(path, func(Request) error { return nil })
+	if err != nil {
+		t.Fatalf("Listen() returned an unexpected error: %v", err)
+	}
+	defer server.Close()
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Dial() failed: %v", err)
+	}
+	defer conn.Close()
+	fmt.Fprintln(conn, "{not json")
+	var resp Response
+	if err := readLine(bufio.NewReader(conn), &resp); err != nil {
+		t.Fatalf("Failed to read response: %v", err)
+	}
+	if resp.OK || !strings.Contains(resp.Error, "malformed") {
+		t.Errorf("Expected a malformed request error, got %+v", resp)
+	}
+}
+
+// TestSlowHandler tests that a request taking longer than the I/O timeout,
+// e.g. waiting for the journal lock, is still answered, so the sender doesn't
+// report a failure for something that was done.
+func TestSlowHandler(t *testing.T) {
+	previousIO, previousHandle := ioTimeout, handleTimeout
+	defer func() { ioTimeout, handleTimeout = previousIO, previousHandle }()
+	ioTimeout, handleTimeout = 100*time.Millisecond, time.Second
+
+	path := testSocketPath(t)
+	var calls atomic.Int32
+	server, err := Listen(path, func(Request) error {
+		calls.Add(1)
+		time.Sleep(3 * ioTimeout)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Listen() returned an unexpected error: %v", err)
+	}
+	defer server.Close()
+
+	if err := Send(path, Request{Command: CommandLogEmotion, EmotionID: "playful"}); err != nil {
+		t.Errorf("Send() returned an unexpected error: %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Handler ran %d times, expected once", got)
+	}
+}
+
+// TestSingleInstance tests detection of a live instance and reclaiming a stale socket.
+func TestSingleInstance(t *testing.T) {
+	path := testSocketPath(t)
+
+	// 1. Nobody listening
+	if err := Send(path, Request{Command: CommandShow}); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("Expected ErrNotRunning, got %v", err)
+	}
+
+	// 2. A second Listen on a live socket is refused
+	first, err := Listen(path, func(Request) error { return nil })
+	if err != nil {
+		t.Fatalf("Listen() returned an unexpected error: %v", err)
+	}
+	if _, err := Listen(path, func(Request) error { return nil }); !errors.Is(err, ErrAlreadyRunning) {
+		t.Fatalf("Expected ErrAlreadyRunning, got %v", err)
+	}
+	first.Close()
+
+	// 3. A socket file left by a crashed instance is reclaimed
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("Failed to create stale socket: %v", err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("Expected the stale socket file to remain: %v", err)
+	}
+	second, err := Listen(path, func(Request) error { return nil })
+	if err != nil {
+		t.Fatalf("Listen() over a stale socket returned an unexpected error: %v", err)
+	}
+	defer second.Close()
+	if err := Send(path, Request{Command: CommandShow}); err != nil {
+		t.Errorf("Send() to the reclaimed socket returned an unexpected error: %v", err)
+	}
+}
+
+// TestSocketPath tests that socket paths depend on the data directory only.
+func TestSocketPath(t *testing.T) {
+	a := SocketPath("/home/user/journal")
+	if a != SocketPath("/home/user/journal/") {
+		t.Errorf("Equivalent data directories got different sockets")
+	}
+	if a == SocketPath("/home/user/other") {
+		t.Errorf("Different data directories share a socket")
+	}
+	if len(a) > 100 {
+		t.Errorf("Socket path %q exceeds the Unix socket path limit", a)
+	}
+}
+
+// TestSocketDir tests that sockets live in a per-user runtime directory
+// when there is one.
+func TestSocketDir(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+	if got := filepath.Dir(SocketPath("/home/user/journal")); got != "/run/user/1000/emotion-explorer" {
+		t.Errorf("Socket directory = %q, want it under XDG_RUNTIME_DIR", got)
+	}
+}
+
+// TestSocketPermissions tests that only the user can reach the socket, even
+// if its directory already existed with looser permissions.
+func TestSocketPermissions(t *testing.T) {
+	for _, existing := range []bool{false, true} {
+		path := testSocketPath(t)
+		path = filepath.Join(filepath.Dir(path), "private", filepath.Base(path))
+		if existing {
+			if err := os.Mkdir(filepath.Dir(path), 0777); err != nil {
+				t.Fatal(err)
+			}
+			os.Chmod(filepath.Dir(path), 0777) // Past the umask
+		}
+
+		server, err := Listen(path, func(Request) error { return nil })
+		if err != nil {
+			t.Fatalf("Listen() failed: %v", err)
+		}
+		for name, want := range map[string]os.FileMode{filepath.Dir(path): 0700, path: 0600} {
+			info, err := os.Stat(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := info.Mode().Perm(); got != want {
+				t.Errorf("%s has mode %o, want %o (existing directory: %v)", filepath.Base(name), got, want, existing)
+			}
+		}
+		server.Close()
+	}
+}
@@ -0,0 +1,357 @@
+package journal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrCorruptJournal is returned (wrapped) when the journal file is not valid
+// JSON. Nothing is written to a corrupt journal; run a repair instead.
+var ErrCorruptJournal = errors.New("journal file is corrupted")
+
+// futureTolerance allows for small clock differences before a timestamp
+// counts as being in the future.
+const futureTolerance = time.Minute
+
+// IssueKind classifies a problem found by the integrity checker.
+type IssueKind string
+
+const (
+	IssueMalformed   IssueKind = "malformed"          // Element could not be parsed and was dropped
+	IssueDuplicate   IssueKind = "duplicate"          // Same timestamp, emotion and notes as an earlier entry
+	IssueDuplicateID IssueKind = "duplicate_id"       // Different entry reusing an earlier entry's ID
+	IssueOutOfOrder  IssueKind = "out_of_order"       // Timestamp earlier than the previous entry's
+	IssueFuture      IssueKind = "future_timestamp"   // Timestamp later than now
+	IssueUnknownID   IssueKind = "unknown_emotion_id" // Emotion ID not in the dataset, even via aliases
+	IssueLegacyID    IssueKind = "legacy_emotion_id"  // Emotion ID is an alias of a current ID
+	IssueStaleName   IssueKind = "stale_emotion_name" // Stored EmotionName differs from the dataset's name
+)
+
+// Issue is a single problem found in the journal.
+type Issue struct {
+	Kind   IssueKind
+	Index  int    // Index into Report.Entries, or the element number for IssueMalformed
+	Detail string // Human-readable description
+}
+
+// EmotionLookup maps a stored emotion ID to the current ID and default-language
+// name in the dataset. Typically built on (*core.IDResolver).Lookup.
+type EmotionLookup func(emotionID string) (currentID, currentName string, ok bool)
+
+// Report is the result of checking a journal file.
+type Report struct {
+	Path       string
+	WellFormed bool       // The file parsed as a JSON array (salvage scan not needed)
+	Entries    []LogEntry // Every entry that could be read, in file order
+	Issues     []Issue
+}
+
+// Count returns the number of issues of the given kind.
+func (r Report) Count(kind IssueKind) int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// NeedsRepair reports whether a repair would change the file.
+// Unknown IDs and future timestamps are reported but cannot be fixed
+// automatically, so they alone don't call for a repair.
+func (r Report) NeedsRepair() bool {
+	if !r.WellFormed {
+		return true
+	}
+	for _, issue := range r.Issues {
+		switch issue.Kind {
+		case IssueMalformed, IssueDuplicate, IssueDuplicateID, IssueOutOfOrder, IssueLegacyID, IssueStaleName:
+			return true
+		}
+	}
+	return false
+}
+
+// String renders the report as a short human-readable summary followed by
+// one line per issue.
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Journal: %s\n", r.Path)
+	if r.WellFormed {
+		fmt.Fprintf(&b, "Format: OK\n")
+	} else {
+		fmt.Fprintf(&b, "Format: DAMAGED (entries were salvaged individually)\n")
+	}
+	fmt.Fprintf(&b, "Readable entries: %d\n", len(r.Entries))
+	if len(r.Issues) == 0 {
+		fmt.Fprintf(&b, "No problems found.\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "Problems found: %d\n", len(r.Issues))
+	for _, issue := range r.Issues {
+		fmt.Fprintf(&b, "  [%s] #%d: %s\n", issue.Kind, issue.Index, issue.Detail)
+	}
+	return b.String()
+}
+
+// CheckFile reads the journal at path, salvaging every well-formed entry even
+// if the file as a whole is damaged, and reports problems with the entries.
+// lookup may be nil to skip dataset checks. A missing file yields an empty report.
+func CheckFile(path string, lookup EmotionLookup, now time.Time) (Report, error) {
+	report := Report{Path: path, WellFormed: true}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return report, nil
+		}
+		return report, fmt.Errorf("reading journal file: %w", err)
+	}
+
+	entries, wellFormed, malformed, err := salvageEntries(raw)
+	if err != nil {
+		return report, err
+	}
+	report.Entries = entries
+	report.WellFormed = wellFormed
+	report.Issues = append(report.Issues, malformed...)
+	report.Issues = append(report.Issues, checkEntries(entries, lookup, now)...)
+	slog.Info("Checked journal", "path", path, "entries", len(entries), "issues", len(report.Issues), "wellFormed", wellFormed)
+	return report, nil
+}
+
+// Check runs CheckFile on the active journal file.
+func Check(lookup EmotionLookup, now time.Time) (Report, error) {
+	release, err := lockJournal()
+	if err != nil {
+		return Report{}, err
+	}
+	defer release()
+	return CheckFile(journalFilePath, lookup, now)
+}
+
+// RepairResult describes what a repair did.
+type RepairResult struct {
+	Report     Report // The check the repair was based on
+	BackupPath string // Where the original file was copied to
+	Written    int    // Number of entries in the repaired file
+}
+
+// RepairFile checks the journal at path and rewrites it with the salvaged
+// entries: duplicates dropped, entries sorted by timestamp, legacy IDs and
+// stale names updated from the dataset, reused IDs replaced and everything
+// upgraded to the current schema. The original file is first copied to
+// a timestamped .bak file next to it. A missing file is an empty, healthy
+// journal, as for CheckFile: nothing is written and BackupPath is empty.
+func RepairFile(path string, lookup EmotionLookup, now time.Time) (RepairResult, error) {
+	release, err := lockJournalAt(path)
+	if err != nil {
+		return RepairResult{}, err
+	}
+	defer release()
+
+	report, err := CheckFile(path, lookup, now)
+	if err != nil {
+		return RepairResult{}, err
+	}
+	result := RepairResult{Report: report}
+
+	original, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		slog.Info("No journal to repair", "path", path)
+		return result, nil
+	}
+	if err != nil {
+		return result, fmt.Errorf("reading journal file for backup: %w", err)
+	}
+	result.BackupPath = fmt.Sprintf("%s.%s.bak", path, now.Format("20060102-150405"))
+	if err := os.WriteFile(result.BackupPath, original, 0644); err != nil {
+		return result, fmt.Errorf("writing journal backup: %w", err)
+	}
+	slog.Info("Backed up journal", "path", path, "backup", result.BackupPath)
+
+	repaired := repairEntries(report, lookup)
+	if err := writeJournalFile(path, repaired); err != nil {
+		return result, fmt.Errorf("writing repaired journal: %w", err)
+	}
+	result.Written = len(repaired)
+	slog.Info("Repaired journal", "path", path, "entries", result.Written)
+	return result, nil
+}
+
+// Repair runs RepairFile on the active journal file.
+func Repair(lookup EmotionLookup, now time.Time) (RepairResult, error) {
+	return RepairFile(FilePath(), lookup, now)
+}
+
+// repairEntries applies the automatic fixes to a report's entries.
+func repairEntries(report Report, lookup EmotionLookup) []LogEntry {
+	duplicates := make(map[int]bool)
+	reusedIDs := make(map[int]bool)
+	for _, issue := range report.Issues {
+		switch issue.Kind {
+		case IssueDuplicate:
+			duplicates[issue.Index] = true
+		case IssueDuplicateID:
+			reusedIDs[issue.Index] = true
+		}
+	}
+
+	repaired := make([]LogEntry, 0, len(report.Entries))
+	for i, entry := range report.Entries {
+		if duplicates[i] {
+			continue
+		}
+		if reusedIDs[i] {
+			entry.ID = NewEntryID()
+		}
+		if lookup != nil {
+			if currentID, currentName, ok := lookup(entry.EmotionID); ok {
+				entry.EmotionID = currentID
+				entry.EmotionName = currentName
+			}
+		}
+		repaired = append(repaired, entry)
+	}
+	sort.SliceStable(repaired, func(i, j int) bool {
+		return repaired[i].Timestamp.Before(repaired[j].Timestamp)
+	})
+	return repaired
+}
+
+// checkEntries looks for problems between and within readable entries.
+func checkEntries(entries []LogEntry, lookup EmotionLookup, now time.Time) []Issue {
+	var issues []Issue
+	type entryKey struct {
+		timestamp int64
+		emotionID string
+		notes     string
+	}
+	seen := make(map[entryKey]int)  // Key -> index of first occurrence
+	seenIDs := make(map[string]int) // Entry ID -> index of first occurrence
+
+	for i, entry := range entries {
+		key := entryKey{entry.Timestamp.UnixNano(), entry.EmotionID, entry.Notes}
+		if first, dup := seen[key]; dup {
+			issues = append(issues, Issue{IssueDuplicate, i, fmt.Sprintf("same as entry #%d (%s at %s)", first, entry.EmotionID, entry.Timestamp.Format(time.RFC3339))})
+		} else {
+			seen[key] = i
+			if first, reused := seenIDs[entry.ID]; reused {
+				issues = append(issues, Issue{IssueDuplicateID, i, fmt.Sprintf("ID %s is already used by entry #%d", entry.ID, first)})
+			} else {
+				seenIDs[entry.ID] = i
+			}
+		}
+
+		if i > 0 && entry.Timestamp.Before(entries[i-1].Timestamp) {
+			issues = append(issues, Issue{IssueOutOfOrder, i, fmt.Sprintf("%s is earlier than the previous entry (%s)", entry.Timestamp.Format(time.RFC3339), entries[i-1].Timestamp.Format(time.RFC3339))})
+		}
+		if entry.Timestamp.After(now.Add(futureTolerance)) {
+			issues = append(issues, Issue{IssueFuture, i, fmt.Sprintf("%s is in the future", entry.Timestamp.Format(time.RFC3339))})
+		}
+
+		if lookup == nil {
+			continue
+		}
+		currentID, currentName, ok := lookup(entry.EmotionID)
+		switch {
+		case !ok:
+			issues = append(issues, Issue{IssueUnknownID, i, fmt.Sprintf("emotion ID '%s' is not in the dataset", entry.EmotionID)})
+		case currentID != entry.EmotionID:
+			issues = append(issues, Issue{IssueLegacyID, i, fmt.Sprintf("emotion ID '%s' is now '%s'", entry.EmotionID, currentID)})
+		case currentName != entry.EmotionName:
+			issues = append(issues, Issue{IssueStaleName, i, fmt.Sprintf("stored name '%s' differs from dataset name '%s'", entry.EmotionName, currentName)})
+		}
+	}
+	return issues
+}
+
+// salvageEntries parses journal JSON as leniently as possible.
+// If the data is a valid JSON array, each element is decoded on its own so
+// one bad element doesn't lose the rest. Otherwise every balanced {...}
+// object in the text is tried individually. Entries without an emotion ID
+// or timestamp are treated as malformed. Entries are upgraded to the current
+// schema; an entry from a newer schema is an error, since repairing it would
+// drop fields this build doesn't know.
+func salvageEntries(raw []byte) (entries []LogEntry, wellFormed bool, malformed []Issue, err error) {
+	entries = []LogEntry{}
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return entries, true, nil, nil
+	}
+
+	var elements []json.RawMessage
+	wellFormed = json.Unmarshal(raw, &elements) == nil
+	if !wellFormed {
+		elements = scanObjects(raw)
+	}
+
+	for i, element := range elements {
+		entry, _, err := decodeEntry(element)
+		if errors.Is(err, ErrNewerSchema) {
+			return nil, wellFormed, nil, fmt.Errorf("element #%d: %w", i, err)
+		}
+		if err != nil {
+			malformed = append(malformed, Issue{IssueMalformed, i, fmt.Sprintf("could not parse element: %v", err)})
+			continue
+		}
+		if entry.EmotionID == "" || entry.Timestamp.IsZero() {
+			malformed = append(malformed, Issue{IssueMalformed, i, "element is missing emotion_id or timestamp"})
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, wellFormed, malformed, nil
+}
+
+// scanObjects extracts every top-level {...} object from damaged JSON text.
+// String state is reset at each newline: valid JSON strings never contain a
+// raw newline, so this stops one broken quote from swallowing the rest of
+// the file. Entries are flat objects, so an opening brace inside an object
+// means the previous object was never closed; scanning restarts there.
+func scanObjects(raw []byte) []json.RawMessage {
+	var objects []json.RawMessage
+	depth, start := 0, -1
+	inString, escaped := false, false
+
+	for i, c := range raw {
+		if c == '\n' {
+			inString, escaped = false, false
+			continue
+		}
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			start = i // Restart on unclosed objects, see above
+			depth = 1
+		case '}':
+			if depth == 0 {
+				continue // Stray closing brace
+			}
+			depth--
+			if depth == 0 && start >= 0 {
+				objects = append(objects, json.RawMessage(raw[start:i+1]))
+				start = -1
+			}
+		}
+	}
+	return objects
+}
@@ -0,0 +1,249 @@
+package journal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testLookup knows a few emotions and one legacy alias.
+func testLookup(emotionID string) (string, string, bool) {
+	switch emotionID {
+	case "inspired":
+		return "inspired", "Inspired", true
+	case "provoked":
+		return "provoked", "Provoked", true
+	case "happy", "joy-01":
+		return "happy", "Happy", true
+	}
+	return "", "", false
+}
+
+// testNow is the reference "current time" for integrity tests.
+var testNow = time.Date(2025, 4, 6, 12, 0, 0, 0, time.UTC)
+
+// writeTestJournal writes raw content to a journal file in a temp directory.
+func writeTestJournal(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), journalFilename)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test journal: %v", err)
+	}
+	return path
+}
+
+// TestCheckFileSalvagesDamagedJournal tests that entries survive common hand-edit mistakes.
+func TestCheckFileSalvagesDamagedJournal(t *testing.T) {
+	testCases := []struct {
+		name             string
+		content          string
+		expectedIDs      []string
+		expectWellFormed bool
+	}{
+		{
+			name: "Missing comma between entries",
+			content: `[
+  {"timestamp": "2025-04-05T09:00:00Z", "emotion_id": "inspired", "emotion_name": "Inspired"}
+  {"timestamp": "2025-04-05T10:00:00Z", "emotion_id": "provoked", "emotion_name": "Provoked"}
+]`,
+			expectedIDs: []string{"inspired", "provoked"},
+		},
+		{
+			name: "Unclosed object",
+			content: `[
+  {"timestamp": "2025-04-05T09:00:00Z", "emotion_id": "inspired", "emotion_name": "Inspired",
+  {"timestamp": "2025-04-05T10:00:00Z", "emotion_id": "provoked", "emotion_name": "Provoked"}
+]`,
+			expectedIDs: []string{"provoked"},
+		},
+		{
+			name: "Broken quote does not swallow later entries",
+			content: `[
+  {"timestamp": "2025-04-05T09:00:00Z", "emotion_id": "inspired, "emotion_name": "Inspired"},
+  {"timestamp": "2025-04-05T10:00:00Z", "emotion_id": "provoked", "emotion_name": "Provoked"}
+]`,
+			expectedIDs: []string{"provoked"},
+		},
+		{
+			name: "Truncated file",
+			content: `[
+  {"timestamp": "2025-04-05T09:00:00Z", "emotion_id": "inspired", "emotion_name": "Inspired"},
+  {"timestamp": "2025-04-05T10:00:00Z", "emoti`,
+			expectedIDs: []string{"inspired"},
+		},
+		{
+			name: "Valid array with an invalid element",
+			content: `[
+  {"timestamp": "not a time", "emotion_id": "inspired"},
+  {"timestamp": "2025-04-05T10:00:00Z", "emotion_id": "provoked", "emotion_name": "Provoked"}
+]`,
+			expectedIDs:      []string{"provoked"},
+			expectWellFormed: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := CheckFile(writeTestJournal(t, tc.content), testLookup, testNow)
+			if err != nil {
+				t.Fatalf("CheckFile() returned an unexpected error: %v", err)
+			}
+			if report.WellFormed != tc.expectWellFormed {
+				t.Errorf("Expected WellFormed=%v, got %v", tc.expectWellFormed, report.WellFormed)
+			}
+			var ids []string
+			for _, entry := range report.Entries {
+				ids = append(ids, entry.EmotionID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tc.expectedIDs, ",") {
+				t.Errorf("Expected salvaged IDs %v, got %v", tc.expectedIDs, ids)
+			}
+			if !report.NeedsRepair() {
+				t.Errorf("Expected a damaged journal to need repair")
+			}
+		})
+	}
+}
+
+// TestCheckFileReportsEntryProblems tests the per-entry checks.
+func TestCheckFileReportsEntryProblems(t *testing.T) {
+	content := `[
+  {"timestamp": "2025-04-05T09:00:00Z", "emotion_id": "inspired", "emotion_name": "Inspired"},
+  {"timestamp": "2025-04-05T09:00:00Z", "emotion_id": "inspired", "emotion_name": "Inspired"},
+  {"timestamp": "2025-04-05T08:00:00Z", "emotion_id": "provoked", "emotion_name": "Annoyed"},
+  {"timestamp": "2025-04-05T10:00:00Z", "emotion_id": "joy-01", "emotion_name": "Joy"},
+  {"timestamp": "2025-04-05T11:00:00Z", "emotion_id": "zoomed_out", "emotion_name": "Zoomed out"},
+  {"timestamp": "2030-01-01T00:00:00Z", "emotion_id": "happy", "emotion_name": "Happy"}
+]`
+	report, err := CheckFile(writeTestJournal(t, content), testLookup, testNow)
+	if err != nil {
+		t.Fatalf("CheckFile() returned an unexpected error: %v", err)
+	}
+
+	expected := map[IssueKind]int{
+		IssueMalformed:  0,
+		IssueDuplicate:  1, // Entry #1
+		IssueOutOfOrder: 1, // Entry #2
+		IssueStaleName:  1, // Entry #2 "Annoyed"
+		IssueLegacyID:   1, // Entry #3 joy-01
+		IssueUnknownID:  1, // Entry #4 zoomed_out
+		IssueFuture:     1, // Entry #5
+	}
+	for kind, count := range expected {
+		if got := report.Count(kind); got != count {
+			t.Errorf("Expected %d '%s' issues, got %d\n%s", count, kind, got, report)
+		}
+	}
+}
+
+// TestNeedsRepairDuplicateIDs tests that a reused entry ID alone calls for a
+// repair, since repair gives the later entry a new ID.
+func TestNeedsRepairDuplicateIDs(t *testing.T) {
+	content := `[
+  {"id": "a1", "schema_version": 1, "timestamp": "2025-04-05T09:00:00Z", "emotion_id": "inspired", "emotion_name": "Inspired"},
+  {"id": "a1", "schema_version": 1, "timestamp": "2025-04-05T10:00:00Z", "emotion_id": "happy", "emotion_name": "Happy"}
+]`
+	path := writeTestJournal(t, content)
+	report, err := CheckFile(path, testLookup, testNow)
+	if err != nil {
+		t.Fatalf("CheckFile() returned an unexpected error: %v", err)
+	}
+	if len(report.Issues) != 1 || report.Count(IssueDuplicateID) != 1 {
+		t.Fatalf("Expected only a reused ID, got:\n%s", report)
+	}
+	if !report.NeedsRepair() {
+		t.Errorf("NeedsRepair() = false for a journal with a reused ID")
+	}
+
+	if _, err := RepairFile(path, testLookup, testNow); err != nil {
+		t.Fatalf("RepairFile() returned an unexpected error: %v", err)
+	}
+	repaired, err := CheckFile(path, testLookup, testNow)
+	if err != nil {
+		t.Fatalf("CheckFile() on repaired journal returned an unexpected error: %v", err)
+	}
+	if repaired.NeedsRepair() || len(repaired.Entries) != 2 {
+		t.Errorf("Repaired journal still needs repair or lost entries:\n%s", repaired)
+	}
+}
+
+// TestRepairFile tests that repair keeps the original and writes a cleaned journal.
+func TestRepairFile(t *testing.T) {
+	content := `[
+  {"timestamp": "2025-04-05T10:00:00Z", "emotion_id": "joy-01", "emotion_name": "Joy"}
+  {"timestamp": "2025-04-05T09:00:00Z", "emotion_id": "inspired", "emotion_name": "Inspired"},
+  {"timestamp": "2025-04-05T09:00:00Z", "emotion_id": "inspired", "emotion_name": "Inspired"},
+  {"timestamp": "2025-04-05T11:00:00Z", "emotion_id": "zoomed_out", "emotion_name": "Zoomed out"}
+]`
+	path := writeTestJournal(t, content)
+
+	result, err := RepairFile(path, testLookup, testNow)
+	if err != nil {
+		t.Fatalf("RepairFile() returned an unexpected error: %v", err)
+	}
+
+	// 1. Original is kept byte for byte
+	backup, err := os.ReadFile(result.BackupPath)
+	if err != nil {
+		t.Fatalf("Failed to read backup '%s': %v", result.BackupPath, err)
+	}
+	if string(backup) != content {
+		t.Errorf("Backup does not match the original journal")
+	}
+
+	// 2. Repaired file is valid, sorted, de-duplicated and updated
+	repaired, err := CheckFile(path, testLookup, testNow)
+	if err != nil {
+		t.Fatalf("CheckFile() on repaired journal returned an unexpected error: %v", err)
+	}
+	if !repaired.WellFormed || repaired.NeedsRepair() {
+		t.Errorf("Repaired journal still needs repair:\n%s", repaired)
+	}
+	if result.Written != 3 || len(repaired.Entries) != 3 {
+		t.Fatalf("Expected 3 entries after repair, got %d (written %d)", len(repaired.Entries), result.Written)
+	}
+	if repaired.Entries[0].EmotionID != "inspired" || repaired.Entries[1].EmotionID != "happy" || repaired.Entries[1].EmotionName != "Happy" {
+		t.Errorf("Unexpected repaired entries: %+v", repaired.Entries)
+	}
+	// Unknown IDs are kept for the user to remap
+	if repaired.Count(IssueUnknownID) != 1 {
+		t.Errorf("Expected the unknown ID to be kept, got:\n%s", repaired)
+	}
+}
+
+// TestRepairMissingFile tests that repairing a journal that doesn't exist
+// yet (a fresh install) succeeds without creating anything.
+func TestRepairMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), journalFilename)
+	result, err := RepairFile(path, testLookup, testNow)
+	if err != nil {
+		t.Fatalf("RepairFile() returned an unexpected error: %v", err)
+	}
+	if result.BackupPath != "" || result.Written != 0 || result.Report.NeedsRepair() {
+		t.Errorf("Expected nothing to repair, got %+v", result)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("RepairFile() created the journal (stat error %v)", err)
+	}
+}
+
+// TestSaveRefusesCorruptJournal tests that saving never overwrites a damaged journal.
+func TestSaveRefusesCorruptJournal(t *testing.T) {
+	path := useTempJournal(t)
+	damaged := `[{"timestamp": "2025-04-05T09:00:00Z", "emotion_id": "inspired"`
+	if err := os.WriteFile(path, []byte(damaged), 0644); err != nil {
+		t.Fatalf("Failed to write damaged journal: %v", err)
+	}
+
+	err := SaveLogEntry(LogEntry{Timestamp: testNow, EmotionID: "happy", EmotionName: "Happy"})
+	if !errors.Is(err, ErrCorruptJournal) {
+		t.Fatalf("Expected ErrCorruptJournal, got %v", err)
+	}
+	after, _ := os.ReadFile(path)
+	if string(after) != damaged {
+		t.Errorf("Damaged journal was modified by SaveLogEntry")
+	}
+}
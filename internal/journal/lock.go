@@ -0,0 +1,133 @@
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- Cross-Process Locking ---
+//
+// journalMutex only serializes goroutines in this process. To keep a second
+// app instance or a script from interleaving its read-modify-write cycle
+// with ours, every journal access also holds an advisory lock on a
+// "<journal>.lock" file next to the journal. On Linux/macOS/BSD this is
+// flock(2), which the OS releases when the holder exits, so a crashed
+// process never leaves a stale lock. Elsewhere the lock file is created
+// exclusively and removed on release; a lock file older than staleLockAge
+// or left by a process that no longer exists is treated as stale.
+
+// ErrJournalLocked is returned (wrapped) when another process holds the
+// journal lock for longer than the lock timeout.
+var ErrJournalLocked = errors.New("journal is locked by another process")
+
+// errLockHeld is returned by tryLock implementations when the lock is taken.
+var errLockHeld = errors.New("lock held")
+
+var lockTimeout = 5 * time.Second // How long to wait for another process to finish with the journal
+
+const (
+	lockRetryInterval = 50 * time.Millisecond
+	staleLockAge      = 2 * time.Minute // Journal operations take milliseconds
+)
+
+// lockJournal locks journalMutex and the cross-process lock of the active
+// journal file. The returned function releases both.
+func lockJournal() (release func(), err error) {
+	return lockJournalWith(func() string { return journalFilePath })
+}
+
+// lockJournalAt is like lockJournal but locks the journal file at path,
+// which need not be the active journal.
+func lockJournalAt(path string) (release func(), err error) {
+	return lockJournalWith(func() string { return path })
+}
+
+// lockJournalWith does the work of lockJournal and lockJournalAt. pathOf is
+// called once journalMutex is held, so reading journalFilePath is safe.
+func lockJournalWith(pathOf func() string) (release func(), err error) {
+	journalMutex.Lock()
+	releaseFile, err := acquireFileLock(pathOf(), lockTimeout)
+	if err != nil {
+		journalMutex.Unlock()
+		return nil, err
+	}
+	return func() {
+		releaseFile()
+		journalMutex.Unlock()
+	}, nil
+}
+
+// lockFilePath returns the lock file used for the journal at journalPath.
+func lockFilePath(journalPath string) string {
+	return journalPath + ".lock"
+}
+
+// acquireFileLock takes the lock for journalPath, retrying until timeout.
+func acquireFileLock(journalPath string, timeout time.Duration) (release func(), err error) {
+	path := lockFilePath(journalPath)
+	deadline := time.Now().Add(timeout)
+	for {
+		unlock, err := tryLock(path)
+		if err == nil {
+			return func() {
+				if err := unlock(); err != nil {
+					slog.Warn("Failed to release journal lock", "path", path, "err", err)
+				}
+			}, nil
+		}
+		if !errors.Is(err, errLockHeld) {
+			return nil, fmt.Errorf("locking journal: %w", err)
+		}
+		if time.Now().After(deadline) {
+			slog.Warn("Timed out waiting for journal lock", "path", path, "timeout", timeout, "holder", describeLockHolder(path))
+			return nil, fmt.Errorf("%w (%s); gave up after %v", ErrJournalLocked, describeLockHolder(path), timeout)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// --- Lock Holder Info ---
+//
+// The lock file records "<pid> <RFC3339 time>" of the holder so errors can
+// say who has the journal and the fallback lock can detect stale locks.
+
+// writeLockHolder records this process as the holder in f.
+func writeLockHolder(f *os.File) error {
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	_, err := f.WriteAt([]byte(fmt.Sprintf("%d %s\n", os.Getpid(), time.Now().Format(time.RFC3339))), 0)
+	return err
+}
+
+// readLockHolder returns the PID and acquisition time recorded in the lock file.
+func readLockHolder(path string) (pid int, since time.Time, ok bool) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) != 2 {
+		return 0, time.Time{}, false
+	}
+	pid, pidErr := strconv.Atoi(fields[0])
+	since, timeErr := time.Parse(time.RFC3339, fields[1])
+	if pidErr != nil || timeErr != nil {
+		return 0, time.Time{}, false
+	}
+	return pid, since, true
+}
+
+// describeLockHolder renders the holder of a lock file for error messages.
+func describeLockHolder(path string) string {
+	pid, since, ok := readLockHolder(path)
+	if !ok {
+		return "holder unknown"
+	}
+	return fmt.Sprintf("held by process %d since %s", pid, since.Format(time.RFC3339))
+}
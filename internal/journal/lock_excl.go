@@ -0,0 +1,58 @@
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// tryExclusiveLock takes the lock by creating the lock file exclusively.
+// It is the portable fallback for platforms without flock (see lock.go).
+// A stale lock file is removed and the attempt repeated once.
+func tryExclusiveLock(path string) (unlock func() error, err error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			if err := writeLockHolder(f); err != nil {
+				f.Close()
+				os.Remove(path)
+				return nil, fmt.Errorf("writing lock file: %w", err)
+			}
+			return func() error {
+				closeErr := f.Close()
+				if err := os.Remove(path); err != nil {
+					return err
+				}
+				return closeErr
+			}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if !lockIsStale(path, time.Now()) {
+			return nil, errLockHeld
+		}
+		slog.Info("Removing stale journal lock", "path", path, "holder", describeLockHolder(path))
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("removing stale lock file: %w", err)
+		}
+	}
+	return nil, errLockHeld
+}
+
+// lockIsStale reports whether an exclusive lock file was left behind: it is
+// older than staleLockAge, or it names a process that no longer exists.
+// An unreadable holder is judged by the file's age alone.
+func lockIsStale(path string, now time.Time) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false // Gone already, or can't tell; the next attempt decides
+	}
+	if now.Sub(info.ModTime()) > staleLockAge {
+		return true
+	}
+	pid, _, ok := readLockHolder(path)
+	return ok && pid != os.Getpid() && !processExists(pid)
+}
@@ -0,0 +1,40 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// tryLock takes an exclusive flock(2) on the lock file without blocking.
+// The lock file itself is left in place on release; only the flock matters.
+func tryLock(path string) (unlock func() error, err error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, errLockHeld
+		}
+		return nil, fmt.Errorf("flock: %w", err)
+	}
+	if err := writeLockHolder(f); err != nil {
+		f.Close() // Closing releases the flock
+		return nil, fmt.Errorf("writing lock file: %w", err)
+	}
+	return func() error {
+		f.Truncate(0) // Best effort: clear the holder info before unlocking
+		return f.Close()
+	}, nil
+}
+
+// processExists reports whether a process with the given PID is running.
+func processExists(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,22 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package journal
+
+import "os"
+
+// tryLock uses the exclusive lock file fallback on platforms without flock.
+func tryLock(path string) (unlock func() error, err error) {
+	return tryExclusiveLock(path)
+}
+
+// processExists reports whether a process with the given PID is running.
+// On Windows FindProcess fails for processes that have exited; elsewhere it
+// always succeeds, leaving staleness to the lock file's age.
+func processExists(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
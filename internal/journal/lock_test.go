@@ -0,0 +1,235 @@
+package journal
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Environment variables used to run this test binary as a helper process.
+const (
+	helperModeEnv    = "JOURNAL_LOCK_HELPER"
+	helperJournalEnv = "JOURNAL_LOCK_HELPER_FILE"
+)
+
+// TestMain lets the test binary act as a second process touching the journal.
+func TestMain(m *testing.M) {
+	switch os.Getenv(helperModeEnv) {
+	case "hold":
+		// Take the lock, report it, then wait to be killed
+		if _, err := acquireFileLock(os.Getenv(helperJournalEnv), time.Second); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(1)
+		}
+		fmt.Println("locked")
+		time.Sleep(time.Minute)
+		os.Exit(0)
+	case "save":
+		SetFilePath(os.Getenv(helperJournalEnv))
+		count, _ := strconv.Atoi(os.Getenv("JOURNAL_LOCK_HELPER_COUNT"))
+		for i := 0; i < count; i++ {
+			if err := SaveLogEntry(LogEntry{Timestamp: time.Now(), EmotionID: "helper"}); err != nil {
+				fmt.Println("error:", err)
+				os.Exit(1)
+			}
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// helperCommand runs this test binary in the given helper mode.
+func helperCommand(t *testing.T, mode, journalPath string, extraEnv ...string) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Env = append(os.Environ(), helperModeEnv+"="+mode, helperJournalEnv+"="+journalPath)
+	cmd.Env = append(cmd.Env, extraEnv...)
+	return cmd
+}
+
+// TestLockImplementations tests that each lock implementation excludes a second holder.
+func TestLockImplementations(t *testing.T) {
+	implementations := map[string]func(string) (func() error, error){
+		"platform":  tryLock,
+		"exclusive": tryExclusiveLock,
+	}
+	for name, try := range implementations {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "journal.json.lock")
+
+			unlock, err := try(path)
+			if err != nil {
+				t.Fatalf("First lock returned an unexpected error: %v", err)
+			}
+			if _, err := try(path); !errors.Is(err, errLockHeld) {
+				t.Fatalf("Second lock: expected errLockHeld, got %v", err)
+			}
+			if err := unlock(); err != nil {
+				t.Fatalf("Unlock returned an unexpected error: %v", err)
+			}
+			unlock, err = try(path)
+			if err != nil {
+				t.Fatalf("Lock after unlock returned an unexpected error: %v", err)
+			}
+			unlock()
+		})
+	}
+}
+
+// TestAcquireFileLockTimesOut tests the timeout error and holder description.
+func TestAcquireFileLockTimesOut(t *testing.T) {
+	journalPath := filepath.Join(t.TempDir(), journalFilename)
+	release, err := acquireFileLock(journalPath, time.Second)
+	if err != nil {
+		t.Fatalf("acquireFileLock() returned an unexpected error: %v", err)
+	}
+	defer release()
+
+	start := time.Now()
+	_, err = acquireFileLock(journalPath, 150*time.Millisecond)
+	if !errors.Is(err, ErrJournalLocked) {
+		t.Fatalf("Expected ErrJournalLocked, got %v", err)
+	}
+	if waited := time.Since(start); waited < 150*time.Millisecond {
+		t.Errorf("Gave up after %v, before the timeout", waited)
+	}
+	if !strings.Contains(err.Error(), strconv.Itoa(os.Getpid())) {
+		t.Errorf("Expected the error to name the holder's PID, got: %v", err)
+	}
+}
+
+// TestStaleExclusiveLock tests that lock files from dead or long-gone holders are taken over.
+func TestStaleExclusiveLock(t *testing.T) {
+	testCases := []struct {
+		name   string
+		holder string
+		age    time.Duration
+		stale  bool
+	}{
+		{"Live holder", fmt.Sprintf("%d %s\n", os.Getpid(), time.Now().Format(time.RFC3339)), 0, false},
+		{"Old lock file", fmt.Sprintf("%d %s\n", os.Getpid(), time.Now().Format(time.RFC3339)), 2 * staleLockAge, true},
+		{"Unreadable holder", "garbage", 0, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "journal.json.lock")
+			if err := os.WriteFile(path, []byte(tc.holder), 0644); err != nil {
+				t.Fatalf("Failed to write lock file: %v", err)
+			}
+			modTime := time.Now().Add(-tc.age)
+			if err := os.Chtimes(path, modTime, modTime); err != nil {
+				t.Fatalf("Failed to age lock file: %v", err)
+			}
+
+			// A live holder in this same process is never stale, but
+			// tryExclusiveLock must still refuse since the file exists
+			unlock, err := tryExclusiveLock(path)
+			if tc.stale {
+				if err != nil {
+					t.Fatalf("Expected a stale lock to be taken over, got %v", err)
+				}
+				unlock()
+			} else if !errors.Is(err, errLockHeld) {
+				t.Fatalf("Expected errLockHeld, got %v", err)
+			}
+		})
+	}
+
+	t.Run("Exited holder", func(t *testing.T) {
+		exited := exec.Command(os.Args[0], "-test.run=^$")
+		if err := exited.Run(); err != nil {
+			t.Fatalf("Failed to run a short-lived process: %v", err)
+		}
+		path := filepath.Join(t.TempDir(), "journal.json.lock")
+		holder := fmt.Sprintf("%d %s\n", exited.Process.Pid, time.Now().Format(time.RFC3339))
+		if err := os.WriteFile(path, []byte(holder), 0644); err != nil {
+			t.Fatalf("Failed to write lock file: %v", err)
+		}
+		if !lockIsStale(path, time.Now()) && processExists(exited.Process.Pid) {
+			t.Skip("Platform cannot tell whether a process has exited")
+		}
+		unlock, err := tryExclusiveLock(path)
+		if err != nil {
+			t.Fatalf("Expected the lock of an exited process to be taken over, got %v", err)
+		}
+		unlock()
+	})
+}
+
+// TestLockHeldByOtherProcess tests locking against a real second process,
+// including the lock being freed when that process dies.
+func TestLockHeldByOtherProcess(t *testing.T) {
+	journalPath := filepath.Join(t.TempDir(), journalFilename)
+	holder := helperCommand(t, "hold", journalPath)
+	stdout, err := holder.StdoutPipe()
+	if err != nil {
+		t.Fatalf("StdoutPipe() failed: %v", err)
+	}
+	if err := holder.Start(); err != nil {
+		t.Fatalf("Failed to start helper process: %v", err)
+	}
+	defer holder.Process.Kill()
+
+	line, _ := bufio.NewReader(stdout).ReadString('\n')
+	if strings.TrimSpace(line) != "locked" {
+		t.Fatalf("Helper process failed to lock: %q", line)
+	}
+
+	_, err = acquireFileLock(journalPath, 100*time.Millisecond)
+	if !errors.Is(err, ErrJournalLocked) {
+		t.Fatalf("Expected ErrJournalLocked while the helper holds the lock, got %v", err)
+	}
+	if !strings.Contains(err.Error(), strconv.Itoa(holder.Process.Pid)) {
+		t.Errorf("Expected the error to name the helper's PID %d, got: %v", holder.Process.Pid, err)
+	}
+
+	// A killed holder must not leave the journal locked
+	holder.Process.Kill()
+	holder.Wait()
+	release, err := acquireFileLock(journalPath, 2*staleLockAge)
+	if err != nil {
+		t.Fatalf("Expected the lock to be free after the holder died, got %v", err)
+	}
+	release()
+}
+
+// TestConcurrentSavesFromSeveralProcesses tests that no entry is lost when
+// several processes append to the same journal at once.
+func TestConcurrentSavesFromSeveralProcesses(t *testing.T) {
+	path := useTempJournal(t)
+	const processes, perProcess = 3, 15
+
+	var helpers []*exec.Cmd
+	for i := 0; i < processes; i++ {
+		cmd := helperCommand(t, "save", path, "JOURNAL_LOCK_HELPER_COUNT="+strconv.Itoa(perProcess))
+		if err := cmd.Start(); err != nil {
+			t.Fatalf("Failed to start helper process: %v", err)
+		}
+		helpers = append(helpers, cmd)
+	}
+	for i := 0; i < perProcess; i++ {
+		if err := SaveLogEntry(LogEntry{Timestamp: time.Now(), EmotionID: "parent"}); err != nil {
+			t.Fatalf("SaveLogEntry() returned an unexpected error: %v", err)
+		}
+	}
+	for _, cmd := range helpers {
+		if err := cmd.Wait(); err != nil {
+			t.Fatalf("Helper process failed: %v", err)
+		}
+	}
+
+	entries, err := GetJournalEntries()
+	if err != nil {
+		t.Fatalf("GetJournalEntries() returned an unexpected error: %v", err)
+	}
+	if want := (processes + 1) * perProcess; len(entries) != want {
+		t.Errorf("Expected %d entries, got %d: entries were lost", want, len(entries))
+	}
+}
@@ -0,0 +1,148 @@
+package journal
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// --- Entry Schema Versions ---
+//
+// Every entry records the schema version it was written with. Entries from
+// older versions are upgraded in memory when the journal is read, one step
+// at a time. The upgraded journal is written back explicitly, by Migrate at
+// startup, or by the next save or repair; reads never write.
+//
+//	0  Original format: bare JSON array of {timestamp, emotion_id,
+//	   emotion_name, notes}. No "schema_version" key (absent means 0).
+//	1  Adds a stable "id" (random UUID) and "schema_version".
+
+// CurrentSchemaVersion is the entry schema version written by this build.
+const CurrentSchemaVersion = 1
+
+// ErrNewerSchema is returned (wrapped) when the journal contains entries
+// written by a newer version of the app. Such a journal is never rewritten,
+// since fields this build doesn't know about would be lost.
+var ErrNewerSchema = errors.New("journal was written by a newer version")
+
+// migration upgrades a raw entry from schema version from to from+1.
+type migration struct {
+	from        int
+	description string
+	apply       func(raw map[string]any) error
+}
+
+// migrations lists every upgrade step, ordered by from.
+// To change the entry format: bump CurrentSchemaVersion, append a step here,
+// describe the new version above and add a fixture to migrate_test.go.
+var migrations = []migration{
+	{from: 0, description: "assign stable entry IDs", apply: func(raw map[string]any) error {
+		if id, _ := raw["id"].(string); id == "" {
+			raw["id"] = NewEntryID()
+		}
+		return nil
+	}},
+}
+
+// migrateEntry upgrades raw to the newest version covered by steps and
+// reports whether anything changed.
+func migrateEntry(raw map[string]any, steps []migration) (bool, error) {
+	version, err := schemaVersionOf(raw)
+	if err != nil {
+		return false, err
+	}
+	target := version
+	if len(steps) > 0 {
+		target = steps[len(steps)-1].from + 1
+	}
+	if version > target {
+		return false, fmt.Errorf("%w: entry schema version %d, this build supports up to %d", ErrNewerSchema, version, target)
+	}
+
+	migrated := false
+	for _, step := range steps {
+		if step.from != version {
+			continue
+		}
+		if err := step.apply(raw); err != nil {
+			return migrated, fmt.Errorf("migrating entry from schema version %d (%s): %w", step.from, step.description, err)
+		}
+		version++
+		raw["schema_version"] = version
+		migrated = true
+	}
+	return migrated, nil
+}
+
+// schemaVersionOf returns the "schema_version" of a raw entry (0 if absent).
+func schemaVersionOf(raw map[string]any) (int, error) {
+	value, present := raw["schema_version"]
+	if !present {
+		return 0, nil
+	}
+	number, ok := value.(float64) // encoding/json decodes numbers as float64
+	if !ok || number < 0 || number != float64(int(number)) {
+		return 0, fmt.Errorf("invalid schema_version %v", value)
+	}
+	return int(number), nil
+}
+
+// decodeEntry parses one journal element, upgrading it to the current
+// schema first. migrated reports whether the stored form is out of date.
+func decodeEntry(element json.RawMessage) (entry LogEntry, migrated bool, err error) {
+	var raw map[string]any
+	if err := json.Unmarshal(element, &raw); err != nil {
+		return entry, false, err
+	}
+	if raw == nil {
+		return entry, false, errors.New("entry is null")
+	}
+	migrated, err = migrateEntry(raw, migrations)
+	if err != nil {
+		return entry, false, err
+	}
+	upgraded, err := json.Marshal(raw)
+	if err != nil {
+		return entry, false, err
+	}
+	if err := json.Unmarshal(upgraded, &entry); err != nil {
+		return entry, false, err
+	}
+	return entry, migrated, nil
+}
+
+// decodeJournal parses a whole journal file (a JSON array of entries) and
+// upgrades every entry. Returns whether any entry was migrated.
+func decodeJournal(data []byte) ([]LogEntry, bool, error) {
+	var elements []json.RawMessage
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return nil, false, fmt.Errorf("%w: unmarshalling journal json: %v", ErrCorruptJournal, err)
+	}
+	entries := make([]LogEntry, 0, len(elements))
+	anyMigrated := false
+	for i, element := range elements {
+		entry, migrated, err := decodeEntry(element)
+		if errors.Is(err, ErrNewerSchema) {
+			return nil, false, err
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("%w: entry #%d: %v", ErrCorruptJournal, i, err)
+		}
+		anyMigrated = anyMigrated || migrated
+		entries = append(entries, entry)
+	}
+	return entries, anyMigrated, nil
+}
+
+// NewEntryID returns a new random (version 4) UUID for a journal entry.
+func NewEntryID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/rand only fails if the OS has no randomness source at all
+		panic(fmt.Sprintf("journal: generating entry ID: %v", err))
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // Version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
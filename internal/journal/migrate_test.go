@@ -0,0 +1,236 @@
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+)
+
+// Journal files as written by each historical schema version.
+// Add a fixture here whenever CurrentSchemaVersion is bumped.
+var historicalJournals = map[int]string{
+	// Version 0: bare array, no IDs (the sample journal.json in the repo)
+	0: `[
+  {
+    "timestamp": "2025-04-05T04:51:02.3902421-05:00",
+    "emotion_id": "joy-01",
+    "emotion_name": "Joy",
+    "notes": "Logged via system tray menu (placeholder)."
+  },
+  {
+    "timestamp": "2025-04-05T04:53:48.5172807-05:00",
+    "emotion_id": "inspired",
+    "emotion_name": "Inspired"
+  }
+]`,
+	// Version 1: stable IDs and schema_version
+	1: `[
+  {
+    "id": "0b6f7c8e-2a41-4d2b-9f3e-5c1d2e3f4a5b",
+    "schema_version": 1,
+    "timestamp": "2025-04-05T04:51:02.3902421-05:00",
+    "emotion_id": "joy-01",
+    "emotion_name": "Joy",
+    "notes": "Logged via system tray menu (placeholder)."
+  },
+  {
+    "id": "9d8c7b6a-5f4e-4d3c-8b2a-1f0e9d8c7b6a",
+    "schema_version": 1,
+    "timestamp": "2025-04-05T04:53:48.5172807-05:00",
+    "emotion_id": "inspired",
+    "emotion_name": "Inspired"
+  }
+]`,
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+// TestLoadHistoricalJournals tests that every historical format loads
+// without being written, is upgraded on disk by Migrate, and keeps the same
+// IDs on later loads.
+func TestLoadHistoricalJournals(t *testing.T) {
+	for version := 0; version <= CurrentSchemaVersion; version++ {
+		content, ok := historicalJournals[version]
+		if !ok {
+			t.Fatalf("No fixture for schema version %d", version)
+		}
+		t.Run(fmt.Sprintf("Schema version %d", version), func(t *testing.T) {
+			path := useTempJournal(t)
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write fixture: %v", err)
+			}
+
+			entries, err := GetJournalEntries()
+			if err != nil {
+				t.Fatalf("GetJournalEntries() returned an unexpected error: %v", err)
+			}
+			if len(entries) != 2 {
+				t.Fatalf("Expected 2 entries, got %d", len(entries))
+			}
+			if entries[0].EmotionID != "joy-01" || entries[0].Notes == "" || entries[1].EmotionName != "Inspired" {
+				t.Errorf("Entry fields lost during migration: %+v", entries)
+			}
+			for i, entry := range entries {
+				if entry.SchemaVersion != CurrentSchemaVersion {
+					t.Errorf("Entry #%d: expected schema version %d, got %d", i, CurrentSchemaVersion, entry.SchemaVersion)
+				}
+				if entry.ID == "" {
+					t.Errorf("Entry #%d has no ID after loading", i)
+				}
+			}
+			if entries[0].ID == entries[1].ID {
+				t.Errorf("Entries share the ID %s", entries[0].ID)
+			}
+			if raw, _ := os.ReadFile(path); string(raw) != content {
+				t.Errorf("Loading rewrote the journal file")
+			}
+
+			migrated, err := Migrate()
+			if err != nil {
+				t.Fatalf("Migrate() returned an unexpected error: %v", err)
+			}
+			if migrated != (version < CurrentSchemaVersion) {
+				t.Errorf("Migrate() = %v for schema version %d", migrated, version)
+			}
+			if again, _ := Migrate(); again {
+				t.Errorf("Second Migrate() upgraded the journal again")
+			}
+
+			// IDs are persisted, so loads return the same ones
+			entries, err = GetJournalEntries()
+			if err != nil {
+				t.Fatalf("GetJournalEntries() after Migrate() returned an unexpected error: %v", err)
+			}
+			reloaded, err := GetJournalEntries()
+			if err != nil {
+				t.Fatalf("Second GetJournalEntries() returned an unexpected error: %v", err)
+			}
+			for i := range entries {
+				if reloaded[i].ID != entries[i].ID {
+					t.Errorf("Entry #%d ID changed between loads: %s -> %s", i, entries[i].ID, reloaded[i].ID)
+				}
+			}
+		})
+	}
+}
+
+// TestSaveAssignsIDs tests that new entries get an ID and older entries are upgraded on save.
+func TestSaveAssignsIDs(t *testing.T) {
+	path := useTempJournal(t)
+	if err := os.WriteFile(path, []byte(historicalJournals[0]), 0644); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+
+	if err := SaveLogEntry(LogEntry{EmotionID: "happy", EmotionName: "Happy"}); err != nil {
+		t.Fatalf("SaveLogEntry() returned an unexpected error: %v", err)
+	}
+
+	var stored []map[string]any
+	raw, _ := os.ReadFile(path)
+	if err := json.Unmarshal(raw, &stored); err != nil {
+		t.Fatalf("Saved journal is not valid JSON: %v", err)
+	}
+	if len(stored) != 3 {
+		t.Fatalf("Expected 3 stored entries, got %d", len(stored))
+	}
+	for i, entry := range stored {
+		id, _ := entry["id"].(string)
+		if !uuidPattern.MatchString(id) {
+			t.Errorf("Entry #%d: expected a UUID, got %q", i, id)
+		}
+		if entry["schema_version"] != float64(CurrentSchemaVersion) {
+			t.Errorf("Entry #%d: expected schema_version %d, got %v", i, CurrentSchemaVersion, entry["schema_version"])
+		}
+	}
+}
+
+// TestNewerSchemaIsNotOverwritten tests that journals from a newer build are left alone.
+func TestNewerSchemaIsNotOverwritten(t *testing.T) {
+	path := useTempJournal(t)
+	newer := `[{"id": "x", "schema_version": 99, "timestamp": "2025-04-05T09:00:00Z", "emotion_id": "happy", "mood": 3}]`
+	if err := os.WriteFile(path, []byte(newer), 0644); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+
+	if _, err := GetJournalEntries(); !errors.Is(err, ErrNewerSchema) {
+		t.Errorf("GetJournalEntries(): expected ErrNewerSchema, got %v", err)
+	}
+	if err := SaveLogEntry(LogEntry{EmotionID: "happy"}); !errors.Is(err, ErrNewerSchema) {
+		t.Errorf("SaveLogEntry(): expected ErrNewerSchema, got %v", err)
+	}
+	if _, err := CheckFile(path, nil, testNow); !errors.Is(err, ErrNewerSchema) {
+		t.Errorf("CheckFile(): expected ErrNewerSchema, got %v", err)
+	}
+	after, _ := os.ReadFile(path)
+	if string(after) != newer {
+		t.Errorf("Journal from a newer schema was modified")
+	}
+}
+
+// TestMigrateEntry tests the step runner with a synthetic field rename.
+func TestMigrateEntry(t *testing.T) {
+	steps := []migration{
+		{from: 0, description: "add id", apply: func(raw map[string]any) error {
+			raw["id"] = "fixed"
+			return nil
+		}},
+		{from: 1, description: "rename notes", apply: func(raw map[string]any) error {
+			raw["note"] = raw["notes"]
+			delete(raw, "notes")
+			return nil
+		}},
+	}
+
+	testCases := []struct {
+		name          string
+		raw           map[string]any
+		expectChanged bool
+		expectErr     bool
+		expectNote    any
+	}{
+		{"From version 0", map[string]any{"notes": "hi"}, true, false, "hi"},
+		{"From version 1", map[string]any{"schema_version": float64(1), "notes": "hi"}, true, false, "hi"},
+		{"Already current", map[string]any{"schema_version": float64(2), "note": "hi"}, false, false, "hi"},
+		{"Newer than steps", map[string]any{"schema_version": float64(3)}, false, true, nil},
+		{"Invalid version", map[string]any{"schema_version": "one"}, false, true, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			changed, err := migrateEntry(tc.raw, steps)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("Expected error=%v, got %v", tc.expectErr, err)
+			}
+			if tc.expectErr {
+				return
+			}
+			if changed != tc.expectChanged {
+				t.Errorf("Expected changed=%v, got %v", tc.expectChanged, changed)
+			}
+			if tc.raw["schema_version"] != float64(2) && tc.raw["schema_version"] != 2 {
+				t.Errorf("Expected schema_version 2, got %v", tc.raw["schema_version"])
+			}
+			if tc.raw["note"] != tc.expectNote {
+				t.Errorf("Expected note %v, got %v", tc.expectNote, tc.raw["note"])
+			}
+		})
+	}
+}
+
+// TestNewEntryID tests the UUID format and that IDs don't repeat.
+func TestNewEntryID(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		id := NewEntryID()
+		if !uuidPattern.MatchString(id) {
+			t.Fatalf("NewEntryID() = %q, not a version 4 UUID", id)
+		}
+		if seen[id] {
+			t.Fatalf("NewEntryID() repeated %q", id)
+		}
+		seen[id] = true
+	}
+}
@@ -4,9 +4,19 @@ import "time"
 
 // LogEntry represents a single recorded emotion instance.
 type LogEntry struct {
-	Timestamp   time.Time `json:"timestamp"`
-	EmotionID   string    `json:"emotion_id"`      // Reference to data.Emotion.ID
-	EmotionName string    `json:"emotion_name"`    // Denormalized for easier display
-	Notes       string    `json:"notes,omitempty"` // Optional user notes
+	ID            string    `json:"id"`             // Stable UUID, assigned when the entry is first saved (see NewEntryID)
+	SchemaVersion int       `json:"schema_version"` // Entry format version, see migrate.go
+	Timestamp     time.Time `json:"timestamp"`
+	EmotionID     string    `json:"emotion_id"`      // Reference to data.Emotion.ID (used to render history in the current language)
+	EmotionName   string    `json:"emotion_name"`    // Default-language name, denormalized as a fallback if the ID disappears
+	Notes         string    `json:"notes,omitempty"` // Optional user notes
+	// Path is the route through the hierarchy the user took to the emotion,
+	// root first, ending at EmotionID. It only matters for emotions with
+	// several parents, where it records which family the entry belongs to.
+	Path []string `json:"path,omitempty"`
+	// Valence and Arousal are the point the user tapped on the mood meter
+	// (each -1 to 1), for entries logged that way.
+	Valence *float64 `json:"valence,omitempty"`
+	Arousal *float64 `json:"arousal,omitempty"`
 	// Optional: Intensity int `json:"intensity,omitempty"`
 }
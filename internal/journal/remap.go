@@ -0,0 +1,90 @@
+package journal
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+)
+
+// UnresolvedID describes an emotion ID in the journal that the current
+// dataset no longer knows, even after following aliases.
+type UnresolvedID struct {
+	EmotionID   string // ID stored in the entries
+	EmotionName string // Name stored alongside it (from the most recent entry)
+	Count       int    // Number of entries using this ID
+}
+
+// FindUnresolvedIDs returns the emotion IDs in entries that resolve cannot map
+// to a current emotion, most frequent first.
+// resolve is typically (*core.IDResolver).Resolve.
+func FindUnresolvedIDs(entries []LogEntry, resolve func(id string) (string, bool)) []UnresolvedID {
+	byID := make(map[string]*UnresolvedID)
+	for _, entry := range entries {
+		if _, ok := resolve(entry.EmotionID); ok {
+			continue
+		}
+		u, seen := byID[entry.EmotionID]
+		if !seen {
+			u = &UnresolvedID{EmotionID: entry.EmotionID}
+			byID[entry.EmotionID] = u
+		}
+		u.Count++
+		if entry.EmotionName != "" {
+			u.EmotionName = entry.EmotionName
+		}
+	}
+
+	unresolved := make([]UnresolvedID, 0, len(byID))
+	for _, u := range byID {
+		unresolved = append(unresolved, *u)
+	}
+	sort.Slice(unresolved, func(i, j int) bool {
+		if unresolved[i].Count != unresolved[j].Count {
+			return unresolved[i].Count > unresolved[j].Count
+		}
+		return unresolved[i].EmotionID < unresolved[j].EmotionID
+	})
+	return unresolved
+}
+
+// RemapEmotionIDs rewrites the journal so entries using an old ID (a key of
+// mapping) use the new ID instead. names supplies the default-language name
+// to store for each new ID; IDs missing from names keep their stored name.
+// Returns the number of entries changed. The file is only written if
+// something changed.
+func RemapEmotionIDs(mapping map[string]string, names map[string]string) (int, error) {
+	release, err := lockJournal()
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	entries, err := readJournalEntries()
+	if err != nil {
+		return 0, fmt.Errorf("loading journal for remap: %w", err)
+	}
+
+	changed := 0
+	for i := range entries {
+		newID, ok := mapping[entries[i].EmotionID]
+		if !ok || newID == "" || newID == entries[i].EmotionID {
+			continue
+		}
+		entries[i].EmotionID = newID
+		entries[i].Path = nil // The old route doesn't lead to the new emotion
+		if name, ok := names[newID]; ok {
+			entries[i].EmotionName = name
+		}
+		changed++
+	}
+
+	if changed == 0 {
+		slog.Info("Remap requested but no journal entries matched")
+		return 0, nil
+	}
+	if err := writeJournalEntries(entries); err != nil {
+		return 0, err
+	}
+	slog.Info("Remapped journal entries to new emotion IDs", "entries", changed)
+	return changed, nil
+}
@@ -0,0 +1,65 @@
+package journal
+
+import (
+	"testing"
+	"time"
+)
+
+// TestFindUnresolvedIDs tests grouping and ordering of unknown emotion IDs.
+func TestFindUnresolvedIDs(t *testing.T) {
+	known := map[string]bool{"happy": true, "inspired": true}
+	resolve := func(id string) (string, bool) { return id, known[id] }
+
+	entries := []LogEntry{
+		{EmotionID: "joy-01", EmotionName: "Joy"},
+		{EmotionID: "inspired", EmotionName: "Inspired"},
+		{EmotionID: "zoomed_out", EmotionName: "Zoomed out"},
+		{EmotionID: "joy-01", EmotionName: "Joy"},
+	}
+
+	unresolved := FindUnresolvedIDs(entries, resolve)
+	if len(unresolved) != 2 {
+		t.Fatalf("Expected 2 unresolved IDs, got %d: %+v", len(unresolved), unresolved)
+	}
+	// Most frequent first
+	if unresolved[0].EmotionID != "joy-01" || unresolved[0].Count != 2 || unresolved[0].EmotionName != "Joy" {
+		t.Errorf("Unexpected first unresolved ID: %+v", unresolved[0])
+	}
+	if unresolved[1].EmotionID != "zoomed_out" || unresolved[1].Count != 1 {
+		t.Errorf("Unexpected second unresolved ID: %+v", unresolved[1])
+	}
+}
+
+// TestRemapEmotionIDs tests rewriting entries from old to new emotion IDs.
+func TestRemapEmotionIDs(t *testing.T) {
+	useTempJournal(t)
+
+	base := time.Date(2025, 4, 5, 9, 0, 0, 0, time.UTC)
+	for i, id := range []string{"joy-01", "inspired", "joy-01"} {
+		entry := LogEntry{Timestamp: base.Add(time.Duration(i) * time.Hour), EmotionID: id, EmotionName: id}
+		if err := SaveLogEntry(entry); err != nil {
+			t.Fatalf("SaveLogEntry() returned an unexpected error: %v", err)
+		}
+	}
+
+	changed, err := RemapEmotionIDs(map[string]string{"joy-01": "happy"}, map[string]string{"happy": "Happy"})
+	if err != nil {
+		t.Fatalf("RemapEmotionIDs() returned an unexpected error: %v", err)
+	}
+	if changed != 2 {
+		t.Errorf("Expected 2 entries changed, got %d", changed)
+	}
+
+	entries, err := GetJournalEntries()
+	if err != nil {
+		t.Fatalf("GetJournalEntries() returned an unexpected error: %v", err)
+	}
+	for _, i := range []int{0, 2} {
+		if entries[i].EmotionID != "happy" || entries[i].EmotionName != "Happy" {
+			t.Errorf("Entry %d not remapped: %+v", i, entries[i])
+		}
+	}
+	if entries[1].EmotionID != "inspired" {
+		t.Errorf("Unrelated entry was changed: %+v", entries[1])
+	}
+}
@@ -3,151 +3,245 @@ package journal // <-- Make sure this line is present
 import (
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sync" // To prevent race conditions if called rapidly
 	"time"
 	// Import your data models if needed here, e.g.:
 	// "github.com/itsforsxm123/emotion-explorer/internal/data"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/paths"
 )
 
 const journalFilename = "journal.json"
 
 var journalFilePath string  // Full path to the journal file
-var journalMutex sync.Mutex // Mutex to protect file access
+var journalMutex sync.Mutex // Mutex to protect file access within this process (see lock.go for other processes)
 
 // init function to determine journal file path
 func init() {
-	// For simplicity now, place it next to the executable or in CWD
-	// TODO: Use os.UserConfigDir() for a better location in the future
-	// Example:
-	// configDir, err := os.UserConfigDir()
-	// if err == nil {
-	//     journalDir := filepath.Join(configDir, "EmotionExplorer")
-	//     if err := os.MkdirAll(journalDir, 0750); err == nil { // Ensure dir exists
-	//         journalFilePath = filepath.Join(journalDir, journalFilename)
-	//     } else {
-	//         log.Printf("Warning: Could not create config directory '%s': %v. Using CWD.", journalDir, err)
-	//     }
-	// } else {
-	//     log.Printf("Warning: Could not get user config directory: %v. Using CWD.", err)
-	// }
+	// The journal lives in the shared data directory (currently the CWD,
+	// see internal/paths for the planned move to os.UserConfigDir()).
+	journalFilePath = paths.File(journalFilename)
+	slog.Debug("Journal file path set", "path", journalFilePath)
+}
 
-	// Fallback to CWD if path wasn't set above
-	// if journalFilePath == "" {
-	cwd, err := os.Getwd() // Get current working directory
-	if err != nil {
-		log.Printf("Warning: Could not get current working directory for journal file: %v. Using filename only.", err)
-		journalFilePath = journalFilename // Fallback
-	} else {
-		journalFilePath = filepath.Join(cwd, journalFilename)
-	}
-	// }
-	log.Printf("Journal file path set to: %s", journalFilePath)
+// FilePath returns the full path of the journal file.
+func FilePath() string {
+	journalMutex.Lock()
+	defer journalMutex.Unlock()
+	return journalFilePath
+}
+
+// SetFilePath changes which journal file is read and written.
+// Mainly useful for tests and tools working on a copy of the journal.
+func SetFilePath(path string) {
+	journalMutex.Lock()
+	defer journalMutex.Unlock()
+	journalFilePath = path
+	slog.Debug("Journal file path set", "path", journalFilePath)
 }
 
 // loadJournalEntries reads the journal file and returns the list of entries.
 // Returns an empty slice if the file doesn't exist or is empty/invalid.
 func loadJournalEntries() ([]LogEntry, error) {
-	journalMutex.Lock()         // Lock before reading
-	defer journalMutex.Unlock() // Ensure unlock
+	release, err := lockJournal() // Lock before reading (this process and others)
+	if err != nil {
+		return nil, err
+	}
+	defer release() // Ensure unlock
+	return readJournalEntries()
+}
 
+// readJournalEntries does the work of loadJournalEntries.
+// The caller must hold the journal lock (see lockJournal).
+func readJournalEntries() ([]LogEntry, error) {
 	data, err := os.ReadFile(journalFilePath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			log.Printf("Journal file '%s' not found, starting fresh.", journalFilePath)
+			slog.Debug("Journal file not found, starting fresh", "path", journalFilePath)
 			return []LogEntry{}, nil // No file is not an error, just means no entries yet
 		}
-		log.Printf("Error reading journal file '%s': %v", journalFilePath, err)
+		slog.Error("Failed to read journal file", "path", journalFilePath, "err", err)
 		return nil, fmt.Errorf("reading journal file: %w", err) // Wrap error
 	}
 
 	if len(data) == 0 {
-		log.Println("Journal file is empty, starting fresh.")
+		slog.Debug("Journal file is empty, starting fresh", "path", journalFilePath)
 		return []LogEntry{}, nil // Empty file is okay
 	}
 
-	var entries []LogEntry
-	err = json.Unmarshal(data, &entries)
+	// Older entries are upgraded in memory only; Migrate writes the upgrade
+	entries, _, err := decodeJournal(data)
 	if err != nil {
-		log.Printf("Error unmarshalling journal JSON from '%s': %v", journalFilePath, err)
-		// Return error to signal corruption
-		return nil, fmt.Errorf("unmarshalling journal json: %w", err) // Wrap error
+		slog.Error("Failed to decode journal", "path", journalFilePath, "err", err)
+		return nil, err // Already wraps ErrCorruptJournal or ErrNewerSchema
 	}
-	log.Printf("Loaded %d entries from journal file '%s'", len(entries), journalFilePath)
+	slog.Debug("Loaded journal", "path", journalFilePath, "entries", len(entries))
 	return entries, nil
 }
 
+// Migrate upgrades the journal file to CurrentSchemaVersion if it has older
+// entries, so IDs assigned by the upgrade stay stable, and reports whether
+// it did. Reads never write; the app calls this once at startup (saves and
+// repairs write the current schema as well). A damaged or newer journal is
+// left untouched and its error returned.
+func Migrate() (migrated bool, err error) {
+	release, err := lockJournal()
+	if err != nil {
+		return false, err
+	}
+	defer release()
+
+	data, err := os.ReadFile(journalFilePath)
+	if os.IsNotExist(err) || (err == nil && len(data) == 0) {
+		return false, nil // Nothing to upgrade
+	}
+	if err != nil {
+		return false, fmt.Errorf("reading journal file: %w", err)
+	}
+	entries, migrated, err := decodeJournal(data)
+	if err != nil || !migrated {
+		return false, err
+	}
+	slog.Info("Upgrading journal schema", "path", journalFilePath, "version", CurrentSchemaVersion, "entries", len(entries))
+	if err := writeJournalEntries(entries); err != nil {
+		return false, fmt.Errorf("writing migrated journal: %w", err)
+	}
+	return true, nil
+}
+
 // SaveLogEntry appends a new entry to the journal file.
 // It loads existing entries, appends the new one, and writes back.
 func SaveLogEntry(newEntry LogEntry) error {
-	journalMutex.Lock()         // Lock for the entire load-append-save operation
-	defer journalMutex.Unlock() // Ensure unlock happens even on error/panic
+	// Lock for the entire load-append-save operation, against other
+	// goroutines and other processes (see lock.go)
+	release, err := lockJournal()
+	if err != nil {
+		slog.Error("Failed to lock journal before save", "err", err)
+		return err
+	}
+	defer release() // Ensure unlock happens even on error/panic
 
-	log.Printf("Attempting to save log entry: Emotion='%s', Time='%s'", newEntry.EmotionName, newEntry.Timestamp.Format(time.RFC3339))
+	// What the user logged stays out of Info logs, and notes out of all logs
+	// (see internal/logging)
+	slog.Debug("Saving journal entry", "emotionID", newEntry.EmotionID, "time", newEntry.Timestamp.Format(time.RFC3339))
 
 	// --- Load existing ---
-	// Note: loadJournalEntries already handles locking internally for the read,
-	// but we need the lock around the whole process to prevent race conditions
-	// between reading and writing back. We could refactor load to not lock
-	// if it's only called from SaveLogEntry which already holds the lock.
-	// For now, this nested locking is functionally okay, though slightly less efficient.
+	// The journal lock taken above (the in-process mutex plus the
+	// cross-process file lock, see lock.go) covers the read and the write
+	// back, so no other goroutine or process can save in between. The file
+	// is read directly rather than through loadJournalEntries, which would
+	// try to take the same lock again.
 
 	var entries []LogEntry // Declare entries here
 
-	// Read the file content directly within the main lock
+	// Read the file content directly within the journal lock
 	rawData, readErr := os.ReadFile(journalFilePath)
 	if readErr != nil && !os.IsNotExist(readErr) {
-		log.Printf("Error reading journal file '%s' before save: %v", journalFilePath, readErr)
+		slog.Error("Failed to read journal file before save", "path", journalFilePath, "err", readErr)
 		return fmt.Errorf("reading journal file before save: %w", readErr)
 	}
 
 	// Unmarshal if data exists
 	if readErr == nil && len(rawData) > 0 {
-		unmarshalErr := json.Unmarshal(rawData, &entries)
-		if unmarshalErr != nil {
-			log.Printf("Error unmarshalling existing journal JSON from '%s': %v. Starting fresh for this save.", journalFilePath, unmarshalErr)
-			// Decide recovery strategy: Here we overwrite corrupted data.
-			// Alternatively, could return error: return fmt.Errorf("unmarshalling existing journal: %w", unmarshalErr)
-			entries = []LogEntry{} // Reset to empty if corrupt
-		} else {
-			log.Printf("Loaded %d existing entries from journal file '%s' for saving.", len(entries), journalFilePath)
+		var decodeErr error
+		entries, _, decodeErr = decodeJournal(rawData) // Older entries are upgraded by the write below
+		if decodeErr != nil {
+			slog.Error("Failed to decode existing journal; refusing to overwrite it", "path", journalFilePath, "err", decodeErr)
+			// Never overwrite a damaged (or newer) journal: the entries can be salvaged with Repair
+			return fmt.Errorf("reading existing journal: %w", decodeErr)
 		}
+		slog.Debug("Loaded existing journal for saving", "path", journalFilePath, "entries", len(entries))
 	} else {
-		log.Println("Journal file empty or not found, initializing new entry list.")
+		slog.Debug("Journal file empty or not found, starting a new entry list", "path", journalFilePath)
 		entries = []LogEntry{} // Ensure entries is an empty slice if file didn't exist or was empty
 	}
 
 	// --- Append the new entry ---
+	if newEntry.ID == "" {
+		newEntry.ID = NewEntryID()
+	}
 	entries = append(entries, newEntry)
 
+	// --- Write the updated list back ---
+	if err := writeJournalEntries(entries); err != nil {
+		return err
+	}
+
+	slog.Info("Saved journal entry", "entries", len(entries))
+	return nil
+}
+
+// writeJournalEntries marshals entries and replaces the journal file.
+// The caller must hold the journal lock (see lockJournal).
+func writeJournalEntries(entries []LogEntry) error {
+	return writeJournalFile(journalFilePath, entries)
+}
+
+// writeJournalFile marshals entries and replaces the file at path with
+// them. Every entry is stamped with CurrentSchemaVersion, since that is the
+// format it is written in. The entries go to a temporary file next to the
+// journal, which is synced to disk and then renamed over it, so a crash or
+// a full disk mid-write leaves the previous journal intact.
+func writeJournalFile(path string, entries []LogEntry) error {
+	for i := range entries {
+		entries[i].SchemaVersion = CurrentSchemaVersion
+	}
+
 	// --- Marshal the updated list back to JSON ---
 	updatedData, marshalErr := json.MarshalIndent(entries, "", "  ") // Indent with 2 spaces
 	if marshalErr != nil {
-		log.Printf("Error marshalling updated journal entries to JSON: %v", marshalErr)
+		slog.Error("Failed to marshal journal entries", "err", marshalErr)
 		return fmt.Errorf("marshalling updated journal: %w", marshalErr)
 	}
 
 	// --- Ensure the directory exists (important if using os.UserConfigDir) ---
-	// dir := filepath.Dir(journalFilePath)
+	// dir := filepath.Dir(path)
 	// if err := os.MkdirAll(dir, 0750); err != nil {
-	//  log.Printf("Error creating directory '%s': %v", dir, err)
+	//  slog.Error("Failed to create journal directory", "path", dir, "err", err)
 	//  return fmt.Errorf("creating journal directory: %w", err)
 	// }
 
-	// --- Write the updated data back to the file (overwrite) ---
-	// Use 0644 permissions (owner read/write, group/other read)
-	writeErr := os.WriteFile(journalFilePath, updatedData, 0644)
-	if writeErr != nil {
-		log.Printf("Error writing updated journal file '%s': %v", journalFilePath, writeErr)
+	// --- Write a temporary file and swap it in ---
+	if writeErr := replaceFile(path, updatedData, 0644); writeErr != nil {
+		slog.Error("Failed to write journal file", "path", path, "err", writeErr)
 		return fmt.Errorf("writing updated journal file: %w", writeErr)
 	}
-
-	log.Printf("Successfully saved log entry. Total entries now: %d", len(entries))
 	return nil
 }
 
+// replaceFile atomically replaces the file at path with content: it writes
+// a temporary file in the same directory (so the rename can't cross file
+// systems), syncs it and renames it over path. On failure the temporary
+// file is removed and path is unchanged.
+func replaceFile(path string, content []byte, perm os.FileMode) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close() // Harmless if already closed
+			os.Remove(tmp.Name())
+		}
+	}()
+	if _, err = tmp.Write(content); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), perm); err != nil { // CreateTemp uses 0600
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
 // Add a function to load entries for potential display later
 // GetJournalEntries provides safe access to the loaded entries.
 func GetJournalEntries() ([]LogEntry, error) {
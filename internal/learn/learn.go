@@ -0,0 +1,200 @@
+// internal/learn/learn.go
+package learn
+
+import (
+	"math/rand"
+	"strings"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+)
+
+// --- Cards ---
+//
+// Learning mode quizzes the user on the vocabulary of the dataset. Every
+// question a word can be asked is a card; cards are scheduled for review
+// with SM-2 (see sm2.go) and the user's progress is kept in a local file
+// (see progress.go). Questions are drawn from the hierarchy itself, so they
+// work for custom and overlay datasets too.
+
+// Kind is the kind of question a card asks.
+type Kind string
+
+const (
+	KindFamily    Kind = "family"    // Which family (top-level emotion) does the word belong to?
+	KindIntensity Kind = "intensity" // Which of two words on an intensity ladder is stronger?
+)
+
+// Card is one question about one word, e.g. the family of Provoked.
+type Card struct {
+	Kind      Kind
+	EmotionID string
+}
+
+// Key identifies the card in the progress file, e.g. "family:provoked".
+func (c Card) Key() string {
+	return string(c.Kind) + ":" + c.EmotionID
+}
+
+// ParseKey reads a key written by Key. ok is false for malformed keys.
+func ParseKey(key string) (card Card, ok bool) {
+	kind, id, found := strings.Cut(key, ":")
+	if !found || id == "" || (Kind(kind) != KindFamily && Kind(kind) != KindIntensity) {
+		return Card{}, false
+	}
+	return Card{Kind: Kind(kind), EmotionID: id}, true
+}
+
+// Deck returns every card that can be asked about the emotions below scope
+// (emotion IDs, e.g. the families a coach assigned), or about the whole
+// dataset if scope is empty. Cards are in hierarchy order (each scope
+// emotion, then its descendants breadth-first), so new words are introduced
+// from the general to the specific. Hidden emotions and unknown scope IDs
+// are left out.
+func Deck(allEmotions map[string]data.Emotion, scope []string) []Card {
+	if len(scope) == 0 {
+		for _, root := range core.GetRootEmotions(allEmotions) {
+			scope = append(scope, root.ID)
+		}
+	}
+	var deck []Card
+	seen := make(map[string]bool)
+	for _, scopeID := range scope {
+		if emotion, ok := allEmotions[scopeID]; !ok || emotion.Hidden {
+			continue
+		}
+		for _, id := range core.Subtree(scopeID, allEmotions) {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			for _, kind := range []Kind{KindFamily, KindIntensity} {
+				if card := (Card{Kind: kind, EmotionID: id}); askable(card, allEmotions) {
+					deck = append(deck, card)
+				}
+			}
+		}
+	}
+	return deck
+}
+
+// askable reports whether a question can be made for card: family cards
+// need a word below the top level with a visible family and another family
+// to confuse it with, intensity cards a word with a milder word on its
+// ladder.
+func askable(card Card, allEmotions map[string]data.Emotion) bool {
+	emotion, ok := allEmotions[card.EmotionID]
+	if !ok || emotion.Hidden {
+		return false
+	}
+	switch card.Kind {
+	case KindFamily:
+		_, ok := familyPath(card.EmotionID, allEmotions)
+		return ok && len(familyDistractors(card.EmotionID, allEmotions)) > 0
+	case KindIntensity:
+		return core.LadderRung(core.IntensityLadder(card.EmotionID, nil, allEmotions), card.EmotionID) > 0
+	}
+	return false
+}
+
+// --- Questions ---
+
+// MaxChoices is how many answers a family question offers.
+const MaxChoices = 4
+
+// Question is a card made concrete: the choices to pick from, shuffled.
+type Question struct {
+	Card     Card
+	Subject  data.Emotion   // The word asked about
+	Choices  []data.Emotion // Possible answers
+	AnswerID string         // ID of the right choice
+	Path     []data.Emotion // Family questions: from the answer down to the word
+}
+
+// IsCorrect reports whether choiceID answers the question.
+func (q Question) IsCorrect(choiceID string) bool {
+	return choiceID == q.AnswerID
+}
+
+// Answer returns the right choice.
+func (q Question) Answer() data.Emotion {
+	for _, choice := range q.Choices {
+		if choice.ID == q.AnswerID {
+			return choice
+		}
+	}
+	return data.Emotion{}
+}
+
+// NewQuestion makes a question for card, picking distractors and the order
+// of the choices with rng. ok is false if the card can't be asked on this
+// dataset (any more), e.g. after its word was removed.
+func NewQuestion(card Card, allEmotions map[string]data.Emotion, rng *rand.Rand) (question Question, ok bool) {
+	if !askable(card, allEmotions) {
+		return Question{}, false
+	}
+	question = Question{Card: card, Subject: allEmotions[card.EmotionID]}
+	switch card.Kind {
+	case KindFamily:
+		question.Path, _ = familyPath(card.EmotionID, allEmotions)
+		family := question.Path[0]
+		distractors := familyDistractors(card.EmotionID, allEmotions)
+		rng.Shuffle(len(distractors), func(i, j int) { distractors[i], distractors[j] = distractors[j], distractors[i] })
+		if len(distractors) > MaxChoices-1 {
+			distractors = distractors[:MaxChoices-1]
+		}
+		question.Choices = append(distractors, family)
+		question.AnswerID = family.ID
+	case KindIntensity:
+		ladder := core.IntensityLadder(card.EmotionID, nil, allEmotions)
+		milder := ladder[rng.Intn(core.LadderRung(ladder, card.EmotionID))] // Any word below it
+		question.Choices = []data.Emotion{question.Subject, milder}
+		question.AnswerID = card.EmotionID
+	}
+	rng.Shuffle(len(question.Choices), func(i, j int) {
+		question.Choices[i], question.Choices[j] = question.Choices[j], question.Choices[i]
+	})
+	return question, true
+}
+
+// familyPath returns the path from the family that answers a family
+// question down to the emotion: through the first family not hidden by a
+// user overlay along the emotion's parents, the primary parent first. ok is
+// false if every family of the emotion is hidden.
+func familyPath(emotionID string, allEmotions map[string]data.Emotion) (path []data.Emotion, ok bool) {
+	seen := make(map[string]bool)
+	var walk func(id string) ([]data.Emotion, bool)
+	walk = func(id string) ([]data.Emotion, bool) {
+		emotion, known := allEmotions[id]
+		if !known || seen[id] {
+			return nil, false
+		}
+		seen[id] = true
+		parents := emotion.Parents()
+		if len(parents) == 0 {
+			return []data.Emotion{emotion}, !emotion.Hidden
+		}
+		for _, parentID := range parents {
+			if path, ok := walk(parentID); ok {
+				return append(path, emotion), true
+			}
+		}
+		return nil, false
+	}
+	if len(allEmotions[emotionID].Parents()) == 0 {
+		return nil, false // A family itself
+	}
+	return walk(emotionID)
+}
+
+// familyDistractors returns the families an emotion does not belong to
+// through any of its parents, in dataset order.
+func familyDistractors(emotionID string, allEmotions map[string]data.Emotion) []data.Emotion {
+	var distractors []data.Emotion
+	for _, root := range core.GetRootEmotions(allEmotions) {
+		if _, related := core.NearestCommonAncestor([]string{emotionID, root.ID}, allEmotions); !related {
+			distractors = append(distractors, root)
+		}
+	}
+	return distractors
+}
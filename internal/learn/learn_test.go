@@ -0,0 +1,222 @@
+// internal/learn/learn_test.go
+package learn_test
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/itsforsxm123/emotion-explorer/internal/learn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// learnEmotions is a small dataset with three families, a ladder under
+// Angry, a word with two parents and a hidden word.
+func learnEmotions() map[string]data.Emotion {
+	return map[string]data.Emotion{
+		"angry":      {ID: "angry", Name: "Angry", Position: 0},
+		"sad":        {ID: "sad", Name: "Sad", Position: 1},
+		"happy":      {ID: "happy", Name: "Happy", Position: 2},
+		"annoyed":    {ID: "annoyed", Name: "Annoyed", ParentID: "angry", Intensity: 1, Position: 0},
+		"furious":    {ID: "furious", Name: "Furious", ParentID: "angry", Intensity: 2, Position: 1},
+		"frustrated": {ID: "frustrated", Name: "Frustrated", ParentID: "angry", ParentIDs: []string{"sad"}, Position: 2},
+		"provoked":   {ID: "provoked", Name: "Provoked", ParentID: "annoyed"},
+		"lonely":     {ID: "lonely", Name: "Lonely", ParentID: "sad"},
+		"smug":       {ID: "smug", Name: "Smug", ParentID: "happy", Hidden: true},
+	}
+}
+
+// card is shorthand for a Card.
+func card(kind learn.Kind, id string) learn.Card {
+	return learn.Card{Kind: kind, EmotionID: id}
+}
+
+// TestDeck tests which cards a dataset yields, whole and scoped.
+func TestDeck(t *testing.T) {
+	emotions := learnEmotions()
+	assert.Equal(t, []learn.Card{
+		card(learn.KindFamily, "annoyed"),
+		card(learn.KindFamily, "furious"),
+		card(learn.KindIntensity, "furious"),
+		card(learn.KindFamily, "frustrated"),
+		card(learn.KindFamily, "provoked"),
+		card(learn.KindFamily, "lonely"),
+	}, learn.Deck(emotions, nil))
+
+	assert.Equal(t, []learn.Card{card(learn.KindFamily, "lonely"), card(learn.KindFamily, "frustrated")},
+		learn.Deck(emotions, []string{"sad", "gone", "smug"}), "scoped to Sad; unknown and hidden scopes are skipped")
+
+	key := card(learn.KindIntensity, "furious").Key()
+	parsed, ok := learn.ParseKey(key)
+	assert.True(t, ok)
+	assert.Equal(t, card(learn.KindIntensity, "furious"), parsed)
+	for _, bad := range []string{"", "family", "family:", "colour:red"} {
+		_, ok := learn.ParseKey(bad)
+		assert.False(t, ok, bad)
+	}
+}
+
+// TestNewQuestion tests the choices of both kinds of questions.
+func TestNewQuestion(t *testing.T) {
+	emotions := learnEmotions()
+	rng := rand.New(rand.NewSource(1))
+
+	question, ok := learn.NewQuestion(card(learn.KindFamily, "provoked"), emotions, rng)
+	require.True(t, ok)
+	assert.Equal(t, "provoked", question.Subject.ID)
+	assert.Equal(t, "angry", question.AnswerID)
+	assert.ElementsMatch(t, []string{"angry", "sad", "happy"}, core.PathIDs(question.Choices))
+	assert.True(t, question.IsCorrect("angry"))
+	assert.Equal(t, "Angry", question.Answer().Name)
+	assert.Equal(t, []string{"angry", "annoyed", "provoked"}, core.PathIDs(question.Path))
+
+	question, ok = learn.NewQuestion(card(learn.KindFamily, "frustrated"), emotions, rng)
+	require.True(t, ok)
+	assert.ElementsMatch(t, []string{"angry", "happy"}, core.PathIDs(question.Choices), "Sad is a family of Frustrated too, so not a wrong answer")
+
+	question, ok = learn.NewQuestion(card(learn.KindIntensity, "furious"), emotions, rng)
+	require.True(t, ok)
+	assert.Equal(t, "furious", question.AnswerID)
+	assert.ElementsMatch(t, []string{"furious", "annoyed"}, core.PathIDs(question.Choices))
+
+	// A family hidden by an overlay is never the answer: the word's other
+	// family is, and a word with no visible family can't be asked
+	emotions["angry"] = data.Emotion{ID: "angry", Name: "Angry", Position: 0, Hidden: true}
+	question, ok = learn.NewQuestion(card(learn.KindFamily, "frustrated"), emotions, rng)
+	require.True(t, ok)
+	assert.Equal(t, "sad", question.AnswerID)
+	assert.Equal(t, []string{"sad", "frustrated"}, core.PathIDs(question.Path), "explained through Sad")
+	assert.ElementsMatch(t, []string{"sad", "happy"}, core.PathIDs(question.Choices))
+	_, ok = learn.NewQuestion(card(learn.KindFamily, "provoked"), emotions, rng)
+	assert.False(t, ok, "Provoked only belongs to the hidden Angry")
+	emotions = learnEmotions()
+
+	for _, c := range []learn.Card{card(learn.KindFamily, "angry"), card(learn.KindIntensity, "annoyed"), card(learn.KindFamily, "gone")} {
+		_, ok := learn.NewQuestion(c, emotions, rng)
+		assert.False(t, ok, c.Key())
+	}
+}
+
+// TestReviewGrade tests the SM-2 schedule.
+func TestReviewGrade(t *testing.T) {
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name         string
+		grades       []int
+		wantInterval int
+		wantReps     int
+		wantEase     float64
+		wantLapses   int
+	}{
+		{name: "First pass", grades: []int{4}, wantInterval: 1, wantReps: 1, wantEase: 2.5},
+		{name: "Second pass", grades: []int{4, 4}, wantInterval: 6, wantReps: 2, wantEase: 2.5},
+		{name: "Third pass", grades: []int{4, 4, 4}, wantInterval: 15, wantReps: 3, wantEase: 2.5},
+		{name: "Perfect answers ease up", grades: []int{5, 5, 5}, wantInterval: 16, wantReps: 3, wantEase: 2.8},
+		{name: "A miss starts over", grades: []int{4, 4, 1}, wantInterval: 1, wantReps: 0, wantEase: 1.96, wantLapses: 1},
+		{name: "Ease bottoms out", grades: []int{0, 0, 0, 0}, wantInterval: 1, wantReps: 0, wantEase: learn.MinEase},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var review learn.Review
+			assert.True(t, review.IsNew())
+			for _, grade := range tc.grades {
+				review = review.Grade(grade, now)
+			}
+			assert.Equal(t, tc.wantInterval, review.IntervalDays)
+			assert.Equal(t, tc.wantReps, review.Repetitions)
+			assert.InDelta(t, tc.wantEase, review.Ease, 1e-9)
+			assert.Equal(t, tc.wantLapses, review.Lapses)
+			assert.Equal(t, now.AddDate(0, 0, tc.wantInterval), review.Due)
+			assert.False(t, review.IsDue(now))
+			assert.True(t, review.IsDue(review.Due))
+		})
+	}
+}
+
+// TestSession tests a session: due reviews before new cards, wrong answers
+// coming back, and the progress it leaves.
+func TestSession(t *testing.T) {
+	emotions := learnEmotions()
+	deck := learn.Deck(emotions, []string{"angry"})
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	var progress learn.Progress
+	progress.Record(card(learn.KindFamily, "provoked"), learn.GradeCorrect, now.AddDate(0, 0, -3)) // Due two days ago
+	progress.Record(card(learn.KindFamily, "annoyed"), learn.GradeCorrect, now.AddDate(0, 0, -2))  // Due yesterday
+	progress.Record(card(learn.KindFamily, "furious"), learn.GradeCorrect, now)                    // Due tomorrow
+
+	assert.Equal(t, []learn.Card{
+		card(learn.KindFamily, "provoked"),
+		card(learn.KindFamily, "annoyed"),
+		card(learn.KindIntensity, "furious"),
+	}, learn.DueCards(deck, progress, now, 1), "overdue first, then one new card")
+	assert.Equal(t, learn.Stats{Total: 5, New: 2, Due: 2}, learn.DeckStats(deck, progress, now))
+
+	session := learn.NewSession(emotions, deck, progress, now, rand.New(rand.NewSource(1)))
+	asked := []string{}
+	for {
+		question, ok := session.Next()
+		if !ok {
+			break
+		}
+		again, _ := session.Next()
+		assert.Equal(t, question, again, "asked until answered")
+		asked = append(asked, question.Card.Key())
+		choice := question.AnswerID
+		if question.Card.EmotionID == "annoyed" && session.Answered == 1 {
+			choice = "sad" // Wrong the first time
+		}
+		session.Answer(choice, now)
+	}
+	assert.Equal(t, []string{"family:provoked", "family:annoyed", "intensity:furious", "family:frustrated", "family:annoyed"}, asked)
+	assert.Equal(t, 5, session.Answered)
+	assert.Equal(t, 4, session.Correct)
+	assert.Equal(t, 0, session.Remaining())
+	assert.False(t, session.Answer("angry", now), "nothing left to answer")
+
+	after := session.Progress()
+	assert.Equal(t, 2, after.Review(card(learn.KindFamily, "provoked")).Repetitions)
+	assert.Equal(t, 1, after.Review(card(learn.KindFamily, "annoyed")).Repetitions, "relearned after the miss")
+	assert.Equal(t, learn.Stats{Total: 5, Learned: 1}, session.Stats(now))
+	next, ok := session.NextReview(now)
+	assert.True(t, ok)
+	assert.Equal(t, now.AddDate(0, 0, 1), next)
+}
+
+// TestProgressFile tests saving and loading progress, and moving it along
+// the dataset's aliases.
+func TestProgressFile(t *testing.T) {
+	original := learn.FilePath()
+	defer learn.SetFilePath(original)
+	learn.SetFilePath(filepath.Join(t.TempDir(), "learning.json"))
+
+	loaded, err := learn.Load()
+	require.NoError(t, err, "a missing file is no progress")
+	assert.Empty(t, loaded.Reviews)
+
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	var progress learn.Progress
+	progress.Record(card(learn.KindFamily, "irritated"), learn.GradeCorrect, now)
+	progress.Record(card(learn.KindFamily, "lonely"), learn.GradeWrong, now)
+	require.NoError(t, learn.Save(progress))
+	loaded, err = learn.Load()
+	require.NoError(t, err)
+	assert.Equal(t, progress, loaded)
+
+	resolver := core.NewIDResolver(data.EmotionData{
+		Emotions: learnEmotions(),
+		Aliases:  []data.IDAlias{{From: "irritated", To: "annoyed"}},
+	})
+	resolved := loaded.Resolved(resolver)
+	assert.Equal(t, 1, resolved.Review(card(learn.KindFamily, "annoyed")).Repetitions, "renamed word keeps its progress")
+	assert.Len(t, resolved.Reviews, 2)
+
+	require.NoError(t, os.WriteFile(learn.FilePath(), []byte("{not json"), 0644))
+	_, err = learn.Load()
+	assert.Error(t, err)
+}
@@ -0,0 +1,124 @@
+// internal/learn/progress.go
+package learn
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/paths"
+)
+
+// --- Progress ---
+
+const progressFilename = "learning.json"
+
+var progressFilePath = paths.File(progressFilename) // Full path to the progress file
+var progressMutex sync.Mutex                        // Mutex to protect file access
+
+// Progress is what the user learned: the review state of every card they
+// answered, by card key (see Card.Key). It stays on this machine.
+type Progress struct {
+	Reviews map[string]Review `json:"reviews"`
+}
+
+// Review returns the review state of card; the zero Review if it is new.
+func (p Progress) Review(card Card) Review {
+	return p.Reviews[card.Key()]
+}
+
+// Record grades an answer to card at now and returns the card's new state.
+func (p *Progress) Record(card Card, grade int, now time.Time) Review {
+	if p.Reviews == nil {
+		p.Reviews = make(map[string]Review)
+	}
+	review := p.Review(card).Grade(grade, now)
+	p.Reviews[card.Key()] = review
+	return review
+}
+
+// Resolved returns the progress with cards of renamed emotions moved to
+// their current IDs (through the dataset's aliases), so a dataset update
+// doesn't reset what the user learned. Where both IDs have a review the one
+// under the current ID wins. Cards of unknown emotions are kept as they are,
+// in case the emotion comes back (e.g. a different dataset is switched to).
+func (p Progress) Resolved(resolver *core.IDResolver) Progress {
+	resolved := Progress{Reviews: make(map[string]Review, len(p.Reviews))}
+	for key, review := range p.Reviews {
+		card, ok := ParseKey(key)
+		if !ok {
+			resolved.Reviews[key] = review
+			continue
+		}
+		if id, found := resolver.Resolve(card.EmotionID); found && id != card.EmotionID {
+			card.EmotionID = id
+			if _, taken := p.Reviews[card.Key()]; taken {
+				continue
+			}
+		}
+		resolved.Reviews[card.Key()] = review
+	}
+	return resolved
+}
+
+// FilePath returns the path of the progress file.
+func FilePath() string {
+	progressMutex.Lock()
+	defer progressMutex.Unlock()
+	return progressFilePath
+}
+
+// SetFilePath changes where progress is read from and written to.
+// Mainly useful for tests.
+func SetFilePath(path string) {
+	progressMutex.Lock()
+	defer progressMutex.Unlock()
+	progressFilePath = path
+}
+
+// Load reads the progress file.
+// A missing or empty file is not an error and yields no progress.
+func Load() (Progress, error) {
+	progressMutex.Lock()
+	defer progressMutex.Unlock()
+
+	progress := Progress{Reviews: make(map[string]Review)}
+	raw, err := os.ReadFile(progressFilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			slog.Info("Learning progress file not found, starting fresh", "path", progressFilePath)
+			return progress, nil
+		}
+		return progress, fmt.Errorf("reading learning progress file: %w", err)
+	}
+	if len(raw) == 0 {
+		return progress, nil
+	}
+	if err := json.Unmarshal(raw, &progress); err != nil {
+		return Progress{}, fmt.Errorf("unmarshalling learning progress json: %w", err)
+	}
+	if progress.Reviews == nil {
+		progress.Reviews = make(map[string]Review)
+	}
+	return progress, nil
+}
+
+// Save writes the progress file, replacing any previous contents.
+func Save(progress Progress) error {
+	progressMutex.Lock()
+	defer progressMutex.Unlock()
+
+	raw, err := json.MarshalIndent(progress, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling learning progress: %w", err)
+	}
+	if err := os.WriteFile(progressFilePath, raw, 0644); err != nil {
+		return fmt.Errorf("writing learning progress file: %w", err)
+	}
+	slog.Debug("Learning progress saved", "path", progressFilePath, "cards", len(progress.Reviews))
+	return nil
+}
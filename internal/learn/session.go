@@ -0,0 +1,166 @@
+// internal/learn/session.go
+package learn
+
+import (
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+)
+
+// --- Sessions ---
+
+// NewCardsPerSession caps how many never-seen cards a session introduces,
+// so a big dataset doesn't bury the reviews.
+const NewCardsPerSession = 10
+
+// LearnedRepetitions is how many passing reviews in a row make a card count
+// as learned in Stats.
+const LearnedRepetitions = 2
+
+// Stats summarizes a deck's progress.
+type Stats struct {
+	Total   int // Cards in the deck
+	New     int // Never answered
+	Due     int // Answered before and due for review
+	Learned int // Answered right at least LearnedRepetitions times in a row
+}
+
+// DeckStats counts the deck's cards by progress at now.
+func DeckStats(deck []Card, progress Progress, now time.Time) Stats {
+	stats := Stats{Total: len(deck)}
+	for _, card := range deck {
+		review := progress.Review(card)
+		switch {
+		case review.IsNew():
+			stats.New++
+		case review.IsDue(now):
+			stats.Due++
+		}
+		if review.Repetitions >= LearnedRepetitions {
+			stats.Learned++
+		}
+	}
+	return stats
+}
+
+// DueCards returns what to study at now: the reviews that are due, the most
+// overdue first, then up to newLimit new cards in deck order.
+func DueCards(deck []Card, progress Progress, now time.Time, newLimit int) []Card {
+	var due, fresh []Card
+	for _, card := range deck {
+		review := progress.Review(card)
+		switch {
+		case review.IsNew():
+			if len(fresh) < newLimit {
+				fresh = append(fresh, card)
+			}
+		case review.IsDue(now):
+			due = append(due, card)
+		}
+	}
+	slices.SortStableFunc(due, func(a, b Card) int {
+		return progress.Review(a).Due.Compare(progress.Review(b).Due)
+	})
+	return append(due, fresh...)
+}
+
+// Session is one sitting of the quiz: the cards due when it started, asked
+// one at a time. As SM-2 asks, cards answered wrong come back at the end of
+// the session until they are answered right. Not safe for concurrent use.
+type Session struct {
+	allEmotions map[string]data.Emotion
+	deck        []Card
+	progress    Progress
+	queue       []Card
+	rng         *rand.Rand
+	current     *Question // Asked and not answered yet
+
+	Answered int // Answers given, repeats included
+	Correct  int // Right answers among them
+}
+
+// NewSession starts a session on deck (see Deck) with the user's progress
+// at now. rng picks distractors and shuffles the choices.
+func NewSession(allEmotions map[string]data.Emotion, deck []Card, progress Progress, now time.Time, rng *rand.Rand) *Session {
+	return &Session{
+		allEmotions: allEmotions,
+		deck:        deck,
+		progress:    progress,
+		queue:       DueCards(deck, progress, now, NewCardsPerSession),
+		rng:         rng,
+	}
+}
+
+// Next returns the question to answer, asking the same one until it is
+// answered. ok is false when the session is over. Cards that can no longer
+// be asked (e.g. after a dataset change) are skipped.
+func (s *Session) Next() (question Question, ok bool) {
+	if s.current != nil {
+		return *s.current, true
+	}
+	for len(s.queue) > 0 {
+		question, ok = NewQuestion(s.queue[0], s.allEmotions, s.rng)
+		if ok {
+			s.current = &question
+			return question, true
+		}
+		s.queue = s.queue[1:]
+	}
+	return Question{}, false
+}
+
+// Answer answers the current question with choiceID at now, records the
+// grade in the progress and reports whether it was right. Does nothing
+// (and reports false) if no question is being asked.
+func (s *Session) Answer(choiceID string, now time.Time) bool {
+	if s.current == nil {
+		return false
+	}
+	question := *s.current
+	s.current = nil
+	s.queue = s.queue[1:]
+
+	correct := question.IsCorrect(choiceID)
+	grade := GradeWrong
+	if correct {
+		grade = GradeCorrect
+		s.Correct++
+	} else {
+		s.queue = append(s.queue, question.Card) // Again before the session ends
+	}
+	s.Answered++
+	s.progress.Record(question.Card, grade, now)
+	return correct
+}
+
+// Remaining returns how many questions are left, the current one included.
+func (s *Session) Remaining() int {
+	return len(s.queue)
+}
+
+// Progress returns the user's progress including this session's answers.
+func (s *Session) Progress() Progress {
+	return s.progress
+}
+
+// Stats counts the session's deck by progress at now.
+func (s *Session) Stats(now time.Time) Stats {
+	return DeckStats(s.deck, s.progress, now)
+}
+
+// NextReview returns when the next card of the deck that was answered
+// before becomes due after now. ok is false if there is none.
+func (s *Session) NextReview(now time.Time) (next time.Time, ok bool) {
+	for _, card := range s.deck {
+		review := s.progress.Review(card)
+		if review.IsNew() || !review.Due.After(now) {
+			continue
+		}
+		if !ok || review.Due.Before(next) {
+			next, ok = review.Due, true
+		}
+	}
+	return next, ok
+}
@@ -0,0 +1,84 @@
+// internal/learn/sm2.go
+package learn
+
+import (
+	"math"
+	"time"
+)
+
+// --- Spaced Repetition (SM-2) ---
+//
+// Reviews follow the SuperMemo 2 algorithm: every answer is graded 0-5; a
+// passing grade (3 or more) pushes the next review out by a growing interval
+// (1 day, 6 days, then the previous interval times the card's ease), a
+// failing one starts the card over. The ease factor grows with easy answers
+// and shrinks with hard ones, but never below MinEase.
+
+// SM-2 parameters.
+const (
+	InitialEase  = 2.5 // Ease of a card never reviewed
+	MinEase      = 1.3 // Lowest ease a card can reach
+	PassingGrade = 3   // Lowest grade that counts as remembered
+)
+
+// Grades given to quiz answers. A right answer isn't a perfect recall (5),
+// since multiple choice helps; a wrong one shows the word was familiar.
+const (
+	GradeCorrect = 4
+	GradeWrong   = 1
+)
+
+// Review is the scheduling state of one card. The zero value is a card
+// that was never reviewed.
+type Review struct {
+	Repetitions  int       `json:"repetitions"`            // Passing reviews in a row
+	IntervalDays int       `json:"intervalDays"`           // Days until the next review
+	Ease         float64   `json:"ease"`                   // SM-2 easiness factor
+	Due          time.Time `json:"due"`                    // When the card should be reviewed next
+	LastReviewed time.Time `json:"lastReviewed,omitempty"` // When the card was last answered
+	Lapses       int       `json:"lapses,omitempty"`       // Times a learned card was forgotten
+}
+
+// IsNew reports whether the card was never reviewed.
+func (r Review) IsNew() bool {
+	return r.LastReviewed.IsZero()
+}
+
+// IsDue reports whether the card should be reviewed at now. New cards are
+// always due.
+func (r Review) IsDue(now time.Time) bool {
+	return r.IsNew() || !r.Due.After(now)
+}
+
+// Grade returns the review after answering with grade (0-5, clamped) at now.
+func (r Review) Grade(grade int, now time.Time) Review {
+	grade = max(0, min(5, grade))
+	if r.Ease == 0 {
+		r.Ease = InitialEase
+	}
+
+	if grade < PassingGrade {
+		if r.Repetitions > 0 {
+			r.Lapses++
+		}
+		r.Repetitions = 0
+		r.IntervalDays = 1
+	} else {
+		switch r.Repetitions {
+		case 0:
+			r.IntervalDays = 1
+		case 1:
+			r.IntervalDays = 6
+		default:
+			r.IntervalDays = int(math.Round(float64(r.IntervalDays) * r.Ease))
+		}
+		r.Repetitions++
+	}
+
+	// The ease changes with every answer, passing or not
+	miss := float64(5 - grade)
+	r.Ease = math.Max(MinEase, r.Ease+0.1-miss*(0.08+miss*0.02))
+	r.LastReviewed = now
+	r.Due = now.AddDate(0, 0, r.IntervalDays)
+	return r
+}
@@ -0,0 +1,217 @@
+// internal/logging/logging.go
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// --- Logging ---
+//
+// The app logs through log/slog. Setup installs the default logger: text
+// lines on stderr and in a rotating log file in the data directory, at Info
+// level (or Debug with --debug). The log package's functions end up there
+// too, at Info level.
+//
+// Privacy policy: logs are meant to be sent to support, so they never
+// contain what the user wrote. Note contents are never logged; the emotions
+// of journal entries are logged (by ID) only at Debug level. As a safety
+// net, attributes named NoteKey are redacted by the handler.
+
+// FileName is the name of the log file in the data directory. Rotated
+// files get a numeric suffix (emotion-explorer.log.1 is the newest).
+const FileName = "emotion-explorer.log"
+
+// Rotation limits of the log file.
+const (
+	MaxFileSize = 1 << 20 // Bytes a log file grows to before it is rotated
+	MaxBackups  = 3       // Rotated files kept next to the current one
+)
+
+// NoteKey is the attribute key whose values are always redacted.
+const NoteKey = "note"
+
+// Options configures Setup.
+type Options struct {
+	Debug  bool      // Log Debug messages too
+	Dir    string    // Directory of the log file; "" logs to Stderr only
+	Stderr io.Writer // Console output; nil for none
+}
+
+// Setup makes slog's default logger write to the console and the log file
+// as configured. The returned closer closes the log file. If the file can't
+// be opened, logging continues on the console and the error is returned.
+func Setup(options Options) (io.Closer, error) {
+	level := slog.LevelInfo
+	if options.Debug {
+		level = slog.LevelDebug
+	}
+
+	var writers []io.Writer
+	if options.Stderr != nil {
+		writers = append(writers, options.Stderr)
+	}
+	var file *RotatingFile
+	var err error
+	if options.Dir != "" {
+		file, err = OpenRotatingFile(filepath.Join(options.Dir, FileName), MaxFileSize, MaxBackups)
+		if err != nil {
+			err = fmt.Errorf("opening log file: %w", err)
+		} else {
+			file.stderr = options.Stderr
+			writers = append(writers, file)
+		}
+	}
+
+	slog.SetDefault(slog.New(NewHandler(io.MultiWriter(writers...), level)))
+	if file == nil {
+		return io.NopCloser(nil), err
+	}
+	return file, err
+}
+
+// NewHandler returns the app's handler: text lines at or above level, with
+// note attributes redacted.
+func NewHandler(w io.Writer, level slog.Leveler) slog.Handler {
+	return slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == NoteKey {
+				return slog.String(NoteKey, "[redacted]")
+			}
+			return attr
+		},
+	})
+}
+
+// DebugEnabled reports whether the default logger writes Debug messages,
+// e.g. to skip building expensive debug output.
+func DebugEnabled() bool {
+	return slog.Default().Enabled(context.Background(), slog.LevelDebug)
+}
+
+// --- Rotating File ---
+
+// RotatingFile is an append-only file that is rotated once it would grow
+// past a size limit: the file becomes path.1, path.1 becomes path.2 and so
+// on, and the oldest is removed. If the file can't be moved aside, writes go
+// on appending to it and the failure is reported once on stderr. It is safe
+// for concurrent use.
+type RotatingFile struct {
+	path    string
+	maxSize int64
+	backups int
+	stderr  io.Writer // Where rotation failures are reported; nil for nowhere
+
+	mu           sync.Mutex
+	file         *os.File
+	size         int64
+	rotateFailed bool // A rotation failure was reported; quiet until one succeeds
+}
+
+// OpenRotatingFile opens (or creates) path for appending.
+func OpenRotatingFile(path string, maxSize int64, backups int) (*RotatingFile, error) {
+	r := &RotatingFile{path: path, maxSize: maxSize, backups: backups, stderr: os.Stderr}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Path returns the path of the current file.
+func (r *RotatingFile) Path() string {
+	return r.path
+}
+
+// open opens the current file and picks up its size.
+func (r *RotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	r.file, r.size = file, info.Size()
+	return nil
+}
+
+// Write appends p, rotating first if p would not fit. A single write larger
+// than the limit still goes into one (fresh) file. If rotating fails, p is
+// appended to the current file and rotation is tried again on the next write.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			if !r.rotateFailed && r.stderr != nil {
+				// Not through slog: its handler is writing to this file
+				fmt.Fprintf(r.stderr, "Log file rotation failed, appending to %s: %v\n", r.path, err)
+			}
+			r.rotateFailed = true
+			if r.file == nil {
+				return 0, fmt.Errorf("rotating log file: %w", err)
+			}
+		} else {
+			r.rotateFailed = false
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate shifts the backups and reopens the current file: a new one, or the
+// old one to append to if it couldn't be moved aside. The file is nil
+// afterwards only if it can't be reopened.
+func (r *RotatingFile) rotate() error {
+	closeErr := r.file.Close()
+	r.file = nil
+	if err := errors.Join(closeErr, r.shift()); err != nil {
+		return errors.Join(err, r.open())
+	}
+	return r.open()
+}
+
+// shift moves the current file to path.1, each backup one number up and
+// removes the oldest. Backups that don't exist yet are skipped.
+func (r *RotatingFile) shift() error {
+	var errs []error
+	if err := os.Remove(fmt.Sprintf("%s.%d", r.path, r.backups)); err != nil && !os.IsNotExist(err) {
+		errs = append(errs, err)
+	}
+	for i := r.backups - 1; i >= 1; i-- {
+		if err := os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1)); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	if r.backups > 0 {
+		errs = append(errs, os.Rename(r.path, r.path+".1"))
+	} else {
+		errs = append(errs, os.Remove(r.path))
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes the current file.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
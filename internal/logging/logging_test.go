@@ -0,0 +1,194 @@
+// internal/logging/logging_test.go
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRotatingFile tests that the file is rotated at its size limit and that
+// only the configured number of backups is kept.
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	file, err := OpenRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile failed: %v", err)
+	}
+	defer file.Close()
+
+	for _, line := range []string{"aaaa\n", "bbbb\n", "cccc\n", "dddd\n", "eeee\n", "ffff\n", "gggg\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	want := map[string]string{
+		path:        "gggg\n",
+		path + ".1": "eeee\nffff\n",
+		path + ".2": "cccc\ndddd\n",
+	}
+	for name, content := range want {
+		got, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("Reading %s failed: %v", name, err)
+		}
+		if string(got) != content {
+			t.Errorf("%s = %q, want %q", filepath.Base(name), got, content)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("Expected no third backup, got err %v", err)
+	}
+}
+
+// TestRotatingFileReopen tests that an existing file's size counts towards
+// the limit.
+func TestRotatingFileReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte("12345678\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	file, err := OpenRotatingFile(path, 10, 1)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile failed: %v", err)
+	}
+	defer file.Close()
+	if _, err := file.Write([]byte("next\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if got, _ := os.ReadFile(path + ".1"); string(got) != "12345678\n" {
+		t.Errorf("Expected the old content to be rotated, got %q", got)
+	}
+}
+
+// TestRotatingFileRenameFails tests that logging goes on in the current file
+// when it can't be rotated, that the failure is reported once, and that
+// rotation resumes once it can.
+func TestRotatingFileRenameFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	blocker := path + ".1"
+	if err := os.MkdirAll(filepath.Join(blocker, "busy"), 0700); err != nil { // Can't be replaced by a file
+		t.Fatal(err)
+	}
+	file, err := OpenRotatingFile(path, 10, 1)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile failed: %v", err)
+	}
+	defer file.Close()
+	var stderr bytes.Buffer
+	file.stderr = &stderr
+
+	for _, line := range []string{"aaaa\n", "bbbb\n", "cccc\n", "dddd\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if got, _ := os.ReadFile(path); string(got) != "aaaa\nbbbb\ncccc\ndddd\n" {
+		t.Errorf("Expected every line in the current file, got %q", got)
+	}
+	if got := strings.Count(stderr.String(), "rotation failed"); got != 1 {
+		t.Errorf("Expected the failure reported once, got %d times:\n%s", got, stderr.String())
+	}
+
+	if err := os.RemoveAll(blocker); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.Write([]byte("eeee\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "eeee\n" {
+		t.Errorf("Expected a fresh file after the rotation, got %q", got)
+	}
+	if got, _ := os.ReadFile(blocker); string(got) != "aaaa\nbbbb\ncccc\ndddd\n" {
+		t.Errorf("Expected the old lines rotated, got %q", got)
+	}
+}
+
+// TestHandler tests level filtering and note redaction.
+func TestHandler(t *testing.T) {
+	testCases := []struct {
+		name    string
+		level   slog.Level
+		log     func(logger *slog.Logger)
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "Info hides debug",
+			level:   slog.LevelInfo,
+			log:     func(l *slog.Logger) { l.Debug("rendering cards"); l.Info("journal saved") },
+			want:    []string{"journal saved"},
+			notWant: []string{"rendering cards"},
+		},
+		{
+			name:  "Debug shows debug",
+			level: slog.LevelDebug,
+			log:   func(l *slog.Logger) { l.Debug("rendering cards", "count", 3) },
+			want:  []string{"level=DEBUG", "rendering cards", "count=3"},
+		},
+		{
+			name:    "Notes are redacted",
+			level:   slog.LevelDebug,
+			log:     func(l *slog.Logger) { l.Info("entry saved", NoteKey, "my secret day", "emotionID", "lonely") },
+			want:    []string{"note=[redacted]", "emotionID=lonely"},
+			notWant: []string{"secret"},
+		},
+		{
+			name:    "Notes in groups are redacted",
+			level:   slog.LevelDebug,
+			log:     func(l *slog.Logger) { l.WithGroup("entry").Info("saved", NoteKey, "my secret day") },
+			want:    []string{"entry.note=[redacted]"},
+			notWant: []string{"secret"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			tc.log(slog.New(NewHandler(&out, tc.level)))
+			for _, s := range tc.want {
+				if !strings.Contains(out.String(), s) {
+					t.Errorf("Expected %q in output:\n%s", s, out.String())
+				}
+			}
+			for _, s := range tc.notWant {
+				if strings.Contains(out.String(), s) {
+					t.Errorf("Did not expect %q in output:\n%s", s, out.String())
+				}
+			}
+		})
+	}
+}
+
+// TestSetup tests that Setup logs to the console and the log file.
+func TestSetup(t *testing.T) {
+	previous := slog.Default()
+	defer slog.SetDefault(previous)
+
+	dir := t.TempDir()
+	var console bytes.Buffer
+	closer, err := Setup(Options{Debug: true, Dir: dir, Stderr: &console})
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	if !DebugEnabled() {
+		t.Error("Expected debug logging to be enabled")
+	}
+	slog.Debug("hello", "n", 1)
+	if err := closer.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	fileContent, err := os.ReadFile(filepath.Join(dir, FileName))
+	if err != nil {
+		t.Fatalf("Reading log file failed: %v", err)
+	}
+	for name, got := range map[string]string{"console": console.String(), "file": string(fileContent)} {
+		if !strings.Contains(got, "msg=hello n=1") {
+			t.Errorf("Expected the message in the %s output, got %q", name, got)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+// internal/paths/paths.go
+package paths
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// DataDir returns the directory where the app keeps its files
+// (journal, settings, ...).
+// For simplicity this is the current working directory, matching where
+// journal.json has always been stored.
+// TODO: Use os.UserConfigDir() for a better location in the future.
+func DataDir() string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		slog.Warn("Could not get the working directory for data files; using relative paths", "err", err)
+		return "."
+	}
+	return cwd
+}
+
+// File returns the full path of a named file inside the data directory.
+func File(name string) string {
+	return filepath.Join(DataDir(), name)
+}
@@ -0,0 +1,82 @@
+// internal/settings/settings.go
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/paths"
+)
+
+const settingsFilename = "settings.json"
+
+var settingsFilePath = paths.File(settingsFilename) // Full path to the settings file
+var settingsMutex sync.Mutex                        // Mutex to protect file access
+
+// Settings holds user preferences that persist between launches.
+// The zero value is the default configuration.
+type Settings struct {
+	ColorblindPalette bool   `json:"colorblindPalette,omitempty"` // Use the colorblind-safe family palette
+	Locale            string `json:"locale,omitempty"`            // UI/dataset language, e.g. "es"; empty means system default
+	DatasetPath       string `json:"datasetPath,omitempty"`       // Custom dataset file; empty means the built-in emotions.json
+	SortMode          string `json:"sortMode,omitempty"`          // How emotion lists are sorted (core.SortMode); empty means dataset order
+	LastRoute         string `json:"lastRoute,omitempty"`         // Where the user was on exit (a ui.Route), restored on the next launch
+}
+
+// FilePath returns the path of the settings file.
+func FilePath() string {
+	settingsMutex.Lock()
+	defer settingsMutex.Unlock()
+	return settingsFilePath
+}
+
+// SetFilePath changes where settings are read from and written to.
+// Mainly useful for tests.
+func SetFilePath(path string) {
+	settingsMutex.Lock()
+	defer settingsMutex.Unlock()
+	settingsFilePath = path
+}
+
+// Load reads the settings file.
+// A missing or empty file is not an error and yields the defaults.
+func Load() (Settings, error) {
+	settingsMutex.Lock()
+	defer settingsMutex.Unlock()
+
+	var s Settings
+	raw, err := os.ReadFile(settingsFilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			slog.Info("Settings file not found, using defaults", "path", settingsFilePath)
+			return s, nil
+		}
+		return s, fmt.Errorf("reading settings file: %w", err)
+	}
+	if len(raw) == 0 {
+		return s, nil
+	}
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return Settings{}, fmt.Errorf("unmarshalling settings json: %w", err)
+	}
+	return s, nil
+}
+
+// Save writes the settings file, replacing any previous contents.
+func Save(s Settings) error {
+	settingsMutex.Lock()
+	defer settingsMutex.Unlock()
+
+	raw, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling settings: %w", err)
+	}
+	if err := os.WriteFile(settingsFilePath, raw, 0644); err != nil {
+		return fmt.Errorf("writing settings file: %w", err)
+	}
+	slog.Debug("Settings saved", "path", settingsFilePath)
+	return nil
+}
@@ -0,0 +1,45 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLoadSaveRoundTrip tests that saved settings are read back unchanged
+// and that a missing file yields the defaults.
+func TestLoadSaveRoundTrip(t *testing.T) {
+	original := FilePath()
+	defer SetFilePath(original)
+	SetFilePath(filepath.Join(t.TempDir(), settingsFilename))
+
+	// 1. Missing file gives defaults without error
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load() on missing file returned an unexpected error: %v", err)
+	}
+	if loaded != (Settings{}) {
+		t.Errorf("Expected default settings, got %+v", loaded)
+	}
+
+	// 2. Saved values survive a round trip
+	want := Settings{ColorblindPalette: true, Locale: "es", DatasetPath: "/tmp/custom.json", SortMode: "recent", LastRoute: "browse/happy/playful"}
+	if err := Save(want); err != nil {
+		t.Fatalf("Save() returned an unexpected error: %v", err)
+	}
+	loaded, err = Load()
+	if err != nil {
+		t.Fatalf("Load() returned an unexpected error: %v", err)
+	}
+	if loaded != want {
+		t.Errorf("Expected %+v after round trip, got %+v", want, loaded)
+	}
+
+	// 3. Corrupt file is reported
+	if err := os.WriteFile(FilePath(), []byte("{not json"), 0644); err != nil {
+		t.Fatalf("Failed to write corrupt settings file: %v", err)
+	}
+	if _, err := Load(); err == nil {
+		t.Errorf("Expected an error loading a corrupt settings file, got nil")
+	}
+}
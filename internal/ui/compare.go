@@ -0,0 +1,178 @@
+// internal/ui/compare.go
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/analytics"
+	"github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/itsforsxm123/emotion-explorer/internal/i18n"
+	"github.com/itsforsxm123/emotion-explorer/internal/journal"
+)
+
+// --- Comparison View ---
+
+// maxCompareResults caps the search results offered for adding an emotion.
+const maxCompareResults = 5
+
+// CreateCompareView shows emotions side by side: for each its ancestry,
+// description, dimensional coordinates (where the dataset has them) and how
+// often the user logged it, alone and on the same day as the others. Above
+// the columns is the nearest common ancestor of all of them. IDs the dataset
+// doesn't know (any more) are left out. onChange is called with the new
+// list of IDs when the user adds or removes an emotion.
+func CreateCompareView(
+	emotionIDs []string, // Emotions to compare (current IDs)
+	entries []journal.LogEntry, // The user's journal, for frequencies
+	resolver *core.IDResolver, // Dataset the emotions come from
+	onChange func(emotionIDs []string), // Callback when the compared emotions change
+) fyne.CanvasObject {
+	emotions := resolver.Emotions()
+	var compared []data.Emotion
+	for _, id := range emotionIDs {
+		if emotion, ok := resolver.Lookup(id); ok {
+			compared = append(compared, emotion)
+		}
+	}
+	ids := core.PathIDs(compared)
+	slog.Debug("Creating compare view", "emotions", len(ids), "entries", len(entries))
+
+	top := newHeader(i18n.T("compare.title"))
+	top = []fyne.CanvasObject{top[0], wrappedLabel(compareSummary(ids, emotions)), top[1]} // Between the title and the separator
+	top = append(top, compareAdder(emotions, ids, onChange))
+
+	var content fyne.CanvasObject
+	if len(compared) == 0 {
+		content = container.NewCenter(widget.NewLabel(i18n.T("compare.empty")))
+	} else {
+		usage := analytics.CompareUsage(entries, ids, resolver)
+		columns := make([]fyne.CanvasObject, len(compared))
+		for i, emotion := range compared {
+			remaining := slices.Delete(slices.Clone(ids), i, i+1)
+			columns[i] = compareColumn(emotion, usage[i], emotions, func() { onChange(remaining) })
+		}
+		content = container.NewVScroll(container.NewGridWithColumns(len(columns), columns...))
+	}
+
+	return container.NewBorder(
+		container.NewVBox(top...), // Top: Header, common ancestor and the search to add more
+		nil,                       // Bottom
+		nil,                       // Left
+		nil,                       // Right
+		content,                   // Center: One column per emotion
+	)
+}
+
+// compareSummary describes what the compared emotions have in common: their
+// nearest common ancestor, or that they belong to different families.
+func compareSummary(emotionIDs []string, allEmotions map[string]data.Emotion) string {
+	if len(emotionIDs) < 2 {
+		return i18n.T("compare.hint")
+	}
+	ancestor, ok := core.NearestCommonAncestor(emotionIDs, allEmotions)
+	if !ok {
+		return i18n.T("compare.noCommon")
+	}
+	return i18n.T("compare.common", AncestryPath(core.GetAncestry(ancestor.ID, allEmotions)))
+}
+
+// compareAdder is a search box whose results add an emotion to the
+// comparison. Submitting the search adds the first result.
+func compareAdder(allEmotions map[string]data.Emotion, emotionIDs []string, onChange func(emotionIDs []string)) fyne.CanvasObject {
+	results := container.NewVBox()
+	add := func(emotion data.Emotion) {
+		slog.Debug("Adding emotion to comparison", "emotionID", emotion.ID)
+		onChange(append(slices.Clone(emotionIDs), emotion.ID))
+	}
+	matches := func(query string) []data.Emotion {
+		var found []data.Emotion
+		for _, emotion := range core.SearchEmotions(query, allEmotions) {
+			if !slices.Contains(emotionIDs, emotion.ID) && len(found) < maxCompareResults {
+				found = append(found, emotion)
+			}
+		}
+		return found
+	}
+
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder(i18n.T("compare.add"))
+	searchEntry.OnChanged = func(query string) {
+		results.RemoveAll()
+		for _, emotion := range matches(query) {
+			label := DisplayName(emotion)
+			if ancestry := core.GetAncestry(emotion.ID, allEmotions); len(ancestry) > 1 {
+				label = fmt.Sprintf("%s (%s)", label, AncestryPath(ancestry[:len(ancestry)-1])) // Tell same-named words apart
+			}
+			results.Add(widget.NewButton(label, func() { add(emotion) }))
+		}
+	}
+	searchEntry.OnSubmitted = func(query string) {
+		if found := matches(query); len(found) > 0 {
+			add(found[0])
+		}
+	}
+	return container.NewVBox(searchEntry, results)
+}
+
+// compareColumn lists what is known about one compared emotion.
+func compareColumn(emotion data.Emotion, usage analytics.EmotionUsage, allEmotions map[string]data.Emotion, onRemove func()) fyne.CanvasObject {
+	name := widget.NewLabelWithStyle(DisplayName(emotion), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	items := []fyne.CanvasObject{
+		name,
+		wrappedLabel(i18n.T("compare.path", AncestryPath(core.GetAncestry(emotion.ID, allEmotions)))),
+	}
+
+	description := emotion.LocalizedDescription(i18n.Chain())
+	if description == "" {
+		description = i18n.T("compare.noDescription")
+	}
+	items = append(items, wrappedLabel(description), wrappedLabel(formatCoordinates(emotion)), widget.NewSeparator())
+
+	items = append(items, wrappedLabel(i18n.T("compare.usage", usage.Count, usage.Days)))
+	others := make([]string, 0, len(usage.SameDay))
+	for id := range usage.SameDay {
+		others = append(others, id)
+	}
+	slices.SortFunc(others, func(a, b string) int { // Alphabetically by display name
+		return strings.Compare(DisplayName(allEmotions[a]), DisplayName(allEmotions[b]))
+	})
+	for _, id := range others {
+		items = append(items, wrappedLabel(i18n.T("compare.sameDay", DisplayName(allEmotions[id]), usage.SameDay[id])))
+	}
+
+	items = append(items, widget.NewButton(i18n.T("compare.remove"), onRemove))
+	return container.NewVBox(items...)
+}
+
+// formatCoordinates describes an emotion's own valence, arousal and
+// dominance, e.g. "Valence -0.60 · Arousal +0.20 · Dominance –". Emotions
+// without any are said to have none; inherited values aren't shown, since
+// they'd make siblings look identical.
+func formatCoordinates(emotion data.Emotion) string {
+	if emotion.Valence == nil && emotion.Arousal == nil && emotion.Dominance == nil {
+		return i18n.T("compare.noCoordinates")
+	}
+	format := func(value *float64) string {
+		if value == nil {
+			return "–"
+		}
+		return fmt.Sprintf("%+.2f", *value)
+	}
+	return i18n.T("compare.coordinates", format(emotion.Valence), format(emotion.Arousal), format(emotion.Dominance))
+}
+
+// wrappedLabel is a label that wraps at word boundaries, for text that may
+// not fit a column.
+func wrappedLabel(text string) *widget.Label {
+	label := widget.NewLabel(text)
+	label.Wrapping = fyne.TextWrapWord
+	return label
+}
@@ -0,0 +1,54 @@
+// internal/ui/contrast.go
+package ui
+
+import (
+	"image/color"
+	"math"
+
+	"fyne.io/fyne/v2/theme"
+)
+
+// WCAG 2.x contrast thresholds.
+const (
+	MinContrastText      = 4.5 // AA for normal-size text
+	MinContrastLargeText = 3.0 // AA for large/bold text and UI components
+)
+
+// RelativeLuminance computes the WCAG relative luminance of a color,
+// from 0 (black) to 1 (white).
+func RelativeLuminance(c color.Color) float64 {
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	linear := func(channel uint8) float64 {
+		v := float64(channel) / 255
+		if v <= 0.03928 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	return 0.2126*linear(nrgba.R) + 0.7152*linear(nrgba.G) + 0.0722*linear(nrgba.B)
+}
+
+// ContrastRatio returns the WCAG contrast ratio between two colors,
+// from 1 (identical luminance) to 21 (black on white). Order does not matter.
+func ContrastRatio(a, b color.Color) float64 {
+	la, lb := RelativeLuminance(a), RelativeLuminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// ContrastAgainstTheme returns the contrast ratio of c against the active
+// theme's background color.
+func ContrastAgainstTheme(c color.Color) float64 {
+	return ContrastRatio(c, theme.Color(theme.ColorNameBackground))
+}
+
+// ReadableTextColor picks black or white, whichever contrasts more with bg.
+// Use it for text drawn on top of an emotion color.
+func ReadableTextColor(bg color.Color) color.Color {
+	if ContrastRatio(bg, color.Black) >= ContrastRatio(bg, color.White) {
+		return color.Black
+	}
+	return color.White
+}
@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestContrastRatio tests the WCAG contrast ratio against known values.
+func TestContrastRatio(t *testing.T) {
+	white := color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+	black := color.NRGBA{A: 255}
+	grey := color.NRGBA{R: 119, G: 119, B: 119, A: 255} // #777777, the classic 4.48:1 on white
+
+	assert.InDelta(t, 21.0, ContrastRatio(black, white), 0.01)
+	assert.InDelta(t, 21.0, ContrastRatio(white, black), 0.01, "Order should not matter")
+	assert.InDelta(t, 1.0, ContrastRatio(grey, grey), 0.001)
+	assert.InDelta(t, 4.48, ContrastRatio(grey, white), 0.01)
+}
+
+// TestReadableTextColor tests automatic text color selection on emotion colors.
+func TestReadableTextColor(t *testing.T) {
+	testCases := []struct {
+		name     string
+		hex      string
+		expected color.Color
+	}{
+		{name: "Light orange (Happy)", hex: "#F29727", expected: color.Black},
+		{name: "Dark purple (Sad)", hex: "#5B4B8A", expected: color.White},
+		{name: "Dark slate (Bad)", hex: "#4A5568", expected: color.White},
+		{name: "Yellow", hex: "#F0E442", expected: color.Black},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bg, err := parseHexColor(tc.hex)
+			assert.NoError(t, err)
+			textColor := ReadableTextColor(bg)
+			assert.Equal(t, tc.expected, textColor)
+			assert.GreaterOrEqual(t, ContrastRatio(bg, textColor), MinContrastLargeText,
+				"Chosen text color should at least meet the large-text threshold")
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// internal/ui/dialogs.go
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/itsforsxm123/emotion-explorer/internal/i18n"
+	"github.com/itsforsxm123/emotion-explorer/internal/journal"
+)
+
+// ShowRemapDialog guides the user through mapping journal emotion IDs that
+// the dataset no longer knows onto current emotions.
+// Each unresolved ID gets a picker, preselected with the closest name match
+// when there is one. onApply receives old ID -> new ID for every ID the user
+// chose to remap (IDs left on "keep as is" are omitted).
+func ShowRemapDialog(
+	unresolved []journal.UnresolvedID,
+	allEmotions map[string]data.Emotion,
+	onApply func(mapping map[string]string),
+	win fyne.Window,
+) {
+	slog.Info("Showing remap dialog", "unresolvedIDs", len(unresolved))
+
+	// Build the picker options once: "Name (id)", sorted by display name
+	keepOption := i18n.T("remap.keep")
+	optionToID := make(map[string]string, len(allEmotions))
+	idToOption := make(map[string]string, len(allEmotions))
+	options := make([]string, 0, len(allEmotions)+1)
+	for id, emotion := range allEmotions {
+		option := fmt.Sprintf("%s (%s)", DisplayName(emotion), id)
+		optionToID[option] = id
+		idToOption[id] = option
+		options = append(options, option)
+	}
+	sort.Strings(options)
+	options = append([]string{keepOption}, options...)
+
+	selects := make(map[string]*widget.Select, len(unresolved))
+	items := []*widget.FormItem{
+		widget.NewFormItem("", widget.NewLabel(i18n.T("remap.explanation"))),
+	}
+	for _, u := range unresolved {
+		picker := widget.NewSelect(options, nil)
+		picker.SetSelected(keepOption)
+		// Suggest the best name match, if the stored name still exists under another ID
+		if guesses := core.SearchEmotions(u.EmotionName, allEmotions); len(guesses) > 0 {
+			picker.SetSelected(idToOption[guesses[0].ID])
+		}
+		selects[u.EmotionID] = picker
+		label := i18n.T("remap.item", u.EmotionID, u.EmotionName, u.Count)
+		items = append(items, widget.NewFormItem(label, picker))
+	}
+
+	form := dialog.NewForm(i18n.T("remap.title"), i18n.T("remap.apply"), i18n.T("remap.later"), items,
+		func(confirmed bool) {
+			if !confirmed {
+				slog.Info("Remap dialog dismissed")
+				return
+			}
+			mapping := make(map[string]string)
+			for oldID, picker := range selects {
+				if newID, ok := optionToID[picker.Selected]; ok {
+					mapping[oldID] = newID
+				}
+			}
+			slog.Info("Remap dialog confirmed", "mappings", len(mapping))
+			if onApply != nil && len(mapping) > 0 {
+				onApply(mapping)
+			}
+		}, win)
+	form.Resize(fyne.NewSize(480, 0)) // Wide enough for the pickers; height follows content
+	form.Show()
+}
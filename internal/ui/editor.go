@@ -0,0 +1,425 @@
+// internal/ui/editor.go
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"log/slog"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/itsforsxm123/emotion-explorer/internal/i18n"
+)
+
+// datasetEditorView holds the widgets and state of an open dataset editor window.
+type datasetEditorView struct {
+	win      fyne.Window
+	editor   *core.DatasetEditor
+	usage    map[string]int // Journal entries per emotion ID, for delete impact checks
+	path     string         // File the dataset is saved to ("" until "Save As")
+	dirty    bool           // Unsaved changes
+	selected string         // ID of the emotion shown in the detail form
+	onSaved  func(path string)
+
+	tree         *widget.Tree
+	idLabel      *widget.Label
+	nameEntry    *widget.Entry
+	colorEntry   *widget.Entry
+	colorSwatch  *canvas.Rectangle
+	parentSelect *widget.Select
+	parentIDs    map[string]string // Parent picker option -> emotion ID
+	detail       *fyne.Container
+	status       *widget.Label
+	saveButton   *widget.Button
+}
+
+// ShowDatasetEditor opens a window for editing a copy of base: adding,
+// renaming, recoloring, reparenting and deleting emotions. The result is
+// validated and saved as a custom dataset file; onSaved receives the path
+// after every successful save. path is the file base was loaded from ("" for
+// the built-in dataset, which can only be saved under a new name). usage maps
+// emotion IDs to journal entry counts so deletions can warn about history.
+func ShowDatasetEditor(app fyne.App, base data.EmotionData, path string, usage map[string]int, onSaved func(path string)) fyne.Window {
+	v := newDatasetEditorView(app, base, path, usage, onSaved)
+	v.win.Show()
+	slog.Info("Dataset editor opened", "emotions", len(base.Emotions), "path", path)
+	return v.win
+}
+
+// newDatasetEditorView creates the editor window without showing it.
+func newDatasetEditorView(app fyne.App, base data.EmotionData, path string, usage map[string]int, onSaved func(path string)) *datasetEditorView {
+	v := &datasetEditorView{
+		win:     app.NewWindow(i18n.T("editor.title")),
+		editor:  core.NewDatasetEditor(base),
+		usage:   usage,
+		path:    path,
+		onSaved: onSaved,
+	}
+	v.win.SetContent(v.build())
+	v.win.SetCloseIntercept(v.confirmClose)
+	v.win.Resize(fyne.NewSize(720, 520))
+	v.refresh()
+	return v
+}
+
+// build creates the editor layout: hierarchy tree on the left, detail form
+// on the right, actions and validation status at the bottom.
+func (v *datasetEditorView) build() fyne.CanvasObject {
+	v.tree = widget.NewTree(v.childIDs, v.isBranch,
+		func(bool) fyne.CanvasObject {
+			swatch := canvas.NewRectangle(color.Transparent)
+			swatch.SetMinSize(fyne.NewSize(14, 14))
+			swatch.CornerRadius = 3
+			return container.NewHBox(container.NewCenter(swatch), widget.NewLabel(""))
+		},
+		func(id widget.TreeNodeID, _ bool, obj fyne.CanvasObject) {
+			row := obj.(*fyne.Container)
+			emotion, _ := v.editor.Emotion(id)
+			swatch := row.Objects[0].(*fyne.Container).Objects[0].(*canvas.Rectangle)
+			swatch.FillColor = v.colorOf(id)
+			swatch.Refresh()
+			row.Objects[1].(*widget.Label).SetText(emotion.Name)
+		})
+	v.tree.OnSelected = func(id widget.TreeNodeID) { v.selectEmotion(id) }
+
+	v.idLabel = widget.NewLabel("")
+	v.nameEntry = widget.NewEntry()
+	v.colorEntry = widget.NewEntry()
+	v.colorEntry.SetPlaceHolder(i18n.T("editor.colorInherited"))
+	v.colorEntry.Validator = func(s string) error {
+		if s == "" {
+			return nil // Inherit the parent's color
+		}
+		_, err := data.ParseHexColor(s)
+		return err
+	}
+	v.colorEntry.OnChanged = func(string) { v.updateSwatch() }
+	v.colorSwatch = canvas.NewRectangle(color.Transparent)
+	v.colorSwatch.SetMinSize(fyne.NewSize(24, 24))
+	v.colorSwatch.CornerRadius = 4
+	pickButton := widget.NewButton(i18n.T("editor.pickColor"), v.showColorPicker)
+	v.parentSelect = widget.NewSelect(nil, nil)
+
+	form := widget.NewForm(
+		widget.NewFormItem(i18n.T("editor.id"), v.idLabel),
+		widget.NewFormItem(i18n.T("editor.name"), v.nameEntry),
+		widget.NewFormItem(i18n.T("editor.color"), container.NewBorder(nil, nil, nil,
+			container.NewHBox(container.NewCenter(v.colorSwatch), pickButton), v.colorEntry)),
+		widget.NewFormItem(i18n.T("editor.parent"), v.parentSelect),
+	)
+	actions := container.NewHBox(
+		widget.NewButton(i18n.T("editor.apply"), v.applyChanges),
+		widget.NewButton(i18n.T("editor.addChild"), func() { v.promptAdd(v.selected) }),
+		widget.NewButton(i18n.T("editor.delete"), v.confirmDelete),
+	)
+	v.detail = container.NewVBox(form, actions)
+	v.detail.Hide() // Until an emotion is selected
+
+	v.status = widget.NewLabel("")
+	v.status.Wrapping = fyne.TextWrapWord
+	v.saveButton = widget.NewButton(i18n.T("editor.save"), func() { v.save(v.path) })
+	bottom := container.NewBorder(nil, nil,
+		widget.NewButton(i18n.T("editor.addRoot"), func() { v.promptAdd("") }),
+		container.NewHBox(v.saveButton, widget.NewButton(i18n.T("editor.saveAs"), v.showSaveAs)),
+		v.status)
+
+	split := container.NewHSplit(v.tree, container.NewVScroll(v.detail))
+	split.Offset = 0.4
+	return container.NewBorder(nil, bottom, nil, nil, split)
+}
+
+// --- Tree Data ---
+
+// childIDs lists the children of a tree node in dataset order ("" is the invisible root).
+// An emotion with several parents is only shown under its primary parent, as
+// tree node IDs must be unique.
+func (v *datasetEditorView) childIDs(id widget.TreeNodeID) []widget.TreeNodeID {
+	ids := make([]widget.TreeNodeID, 0)
+	if id == "" {
+		for _, root := range core.GetRootEmotions(v.editor.Emotions()) {
+			ids = append(ids, root.ID)
+		}
+		return ids
+	}
+	for _, child := range core.GetChildrenOf(id, v.editor.Emotions()) {
+		if child.PrimaryParent() == id {
+			ids = append(ids, child.ID)
+		}
+	}
+	return ids
+}
+
+// isBranch reports whether a tree node has children.
+func (v *datasetEditorView) isBranch(id widget.TreeNodeID) bool {
+	return id == "" || len(v.childIDs(id)) > 0
+}
+
+// colorOf returns the color an emotion is drawn with in the edited dataset:
+// its own, or the nearest ancestor's.
+func (v *datasetEditorView) colorOf(id string) color.Color {
+	ancestry := core.GetAncestry(id, v.editor.Emotions())
+	for i := len(ancestry) - 1; i >= 0; i-- {
+		if c, err := data.ParseHexColor(ancestry[i].Color); err == nil {
+			return c
+		}
+	}
+	return fallbackEmotionColor
+}
+
+// --- Detail Form ---
+
+// selectEmotion shows the emotion with the given ID in the detail form.
+func (v *datasetEditorView) selectEmotion(id string) {
+	emotion, ok := v.editor.Emotion(id)
+	if !ok {
+		v.selected = ""
+		v.detail.Hide()
+		return
+	}
+	v.selected = id
+	v.idLabel.SetText(emotion.ID)
+	v.nameEntry.SetText(emotion.Name)
+	v.colorEntry.SetText(emotion.Color)
+
+	// Any emotion outside the selected subtree can become the new parent
+	inSubtree := make(map[string]bool)
+	for _, descendant := range core.Subtree(id, v.editor.Emotions()) {
+		inSubtree[descendant] = true
+	}
+	topLevel := i18n.T("editor.topLevel")
+	v.parentIDs = map[string]string{topLevel: ""}
+	options := []string{}
+	selected := topLevel
+	for otherID, other := range v.editor.Emotions() {
+		if inSubtree[otherID] {
+			continue
+		}
+		option := fmt.Sprintf("%s (%s)", other.Name, otherID)
+		v.parentIDs[option] = otherID
+		options = append(options, option)
+		if otherID == emotion.PrimaryParent() {
+			selected = option
+		}
+	}
+	sort.Strings(options)
+	v.parentSelect.Options = append([]string{topLevel}, options...)
+	v.parentSelect.SetSelected(selected)
+
+	v.updateSwatch()
+	v.detail.Show()
+}
+
+// updateSwatch previews the color typed into the color entry.
+func (v *datasetEditorView) updateSwatch() {
+	if c, err := data.ParseHexColor(v.colorEntry.Text); err == nil {
+		v.colorSwatch.FillColor = c
+	} else if v.selected != "" {
+		emotion, _ := v.editor.Emotion(v.selected)
+		v.colorSwatch.FillColor = v.colorOf(emotion.PrimaryParent()) // Inherited (or invalid) color
+	}
+	v.colorSwatch.Refresh()
+}
+
+// showColorPicker lets the user pick the selected emotion's color visually.
+func (v *datasetEditorView) showColorPicker() {
+	picker := dialog.NewColorPicker(i18n.T("editor.pickColor"), "", func(c color.Color) {
+		v.colorEntry.SetText(data.FormatHexColor(c))
+	}, v.win)
+	picker.Advanced = true
+	if c, err := data.ParseHexColor(v.colorEntry.Text); err == nil {
+		picker.SetColor(c)
+	}
+	picker.Show()
+}
+
+// applyChanges writes the detail form back to the selected emotion.
+func (v *datasetEditorView) applyChanges() {
+	if v.selected == "" {
+		return
+	}
+	id := v.selected
+	err := v.editor.Rename(id, v.nameEntry.Text)
+	if err == nil {
+		err = v.editor.Recolor(id, v.colorEntry.Text)
+	}
+	if err == nil {
+		if parentID, ok := v.parentIDs[v.parentSelect.Selected]; ok {
+			if emotion, _ := v.editor.Emotion(id); emotion.PrimaryParent() != parentID {
+				err = v.editor.Reparent(id, parentID)
+			}
+		}
+	}
+	if err != nil {
+		slog.Info("Dataset editor refused a change", "emotionID", id, "err", err)
+		dialog.ShowError(err, v.win)
+	}
+	v.changed()
+	v.revealAndSelect(id)
+}
+
+// promptAdd asks for a name and adds a new emotion under parentID.
+func (v *datasetEditorView) promptAdd(parentID string) {
+	nameEntry := widget.NewEntry()
+	title := i18n.T("editor.addRoot")
+	if parentID != "" {
+		parent, _ := v.editor.Emotion(parentID)
+		title = i18n.T("editor.addChildOf", parent.Name)
+	}
+	dialog.ShowForm(title, i18n.T("editor.add"), i18n.T("editor.cancel"),
+		[]*widget.FormItem{widget.NewFormItem(i18n.T("editor.name"), nameEntry)},
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			added, err := v.editor.Add(parentID, nameEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, v.win)
+				return
+			}
+			slog.Debug("Dataset editor added an emotion", "emotionID", added.ID, "parentID", parentID)
+			v.changed()
+			v.revealAndSelect(added.ID)
+		}, v.win)
+}
+
+// confirmDelete shows what deleting the selected emotion affects and deletes it if confirmed.
+func (v *datasetEditorView) confirmDelete() {
+	if v.selected == "" {
+		return
+	}
+	id := v.selected
+	emotion, _ := v.editor.Emotion(id)
+	emotions, entries := v.editor.DeleteImpact(id, v.usage)
+	message := i18n.T("editor.deleteConfirm", emotion.Name, emotions-1)
+	if entries > 0 {
+		if emotion.PrimaryParent() != "" {
+			parent, _ := v.editor.Emotion(emotion.PrimaryParent())
+			message += "\n" + i18n.T("editor.deleteEntriesMoved", entries, parent.Name)
+		} else {
+			message += "\n" + i18n.T("editor.deleteEntriesOrphaned", entries)
+		}
+	}
+	dialog.ShowConfirm(i18n.T("editor.delete"), message, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		removed, err := v.editor.Delete(id)
+		if err != nil {
+			dialog.ShowError(err, v.win)
+			return
+		}
+		slog.Debug("Dataset editor deleted emotions", "count", len(removed), "emotionID", id)
+		v.tree.UnselectAll()
+		v.selectEmotion("")
+		v.changed()
+	}, v.win)
+}
+
+// --- Saving ---
+
+// showSaveAs asks for a file name and saves the dataset there.
+func (v *datasetEditorView) showSaveAs() {
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, v.win)
+			return
+		}
+		if writer == nil {
+			return // Cancelled
+		}
+		path := writer.URI().Path()
+		writer.Close()
+		v.save(path)
+	}, v.win)
+	save.SetFilter(storage.NewExtensionFileFilter(data.FileExtensions))
+	save.SetFileName("emotions-custom.json")
+	save.Show()
+}
+
+// save validates the dataset and writes it to path.
+func (v *datasetEditorView) save(path string) {
+	if path == "" {
+		v.showSaveAs()
+		return
+	}
+	if err := data.SaveEmotionsFile(path, v.editor.Data()); err != nil {
+		slog.Error("Dataset editor failed to save", "path", path, "err", err)
+		dialog.ShowError(err, v.win)
+		return
+	}
+	slog.Info("Dataset editor saved", "path", path)
+	v.path = path
+	v.dirty = false
+	v.refresh()
+	if v.onSaved != nil {
+		v.onSaved(path)
+	}
+}
+
+// confirmClose asks before discarding unsaved changes.
+func (v *datasetEditorView) confirmClose() {
+	if !v.dirty {
+		v.win.Close()
+		return
+	}
+	dialog.ShowConfirm(i18n.T("editor.title"), i18n.T("editor.discard"), func(discard bool) {
+		if discard {
+			v.win.Close()
+		}
+	}, v.win)
+}
+
+// --- Refresh ---
+
+// changed marks the dataset as modified and redraws.
+func (v *datasetEditorView) changed() {
+	v.dirty = true
+	v.refresh()
+}
+
+// revealAndSelect opens the branches leading to id and selects it.
+func (v *datasetEditorView) revealAndSelect(id string) {
+	for _, ancestor := range core.GetAncestry(id, v.editor.Emotions()) {
+		if ancestor.ID != id {
+			v.tree.OpenBranch(ancestor.ID)
+		}
+	}
+	v.tree.Select(id)
+	v.selectEmotion(id) // Select doesn't fire OnSelected for the already selected node
+}
+
+// refresh redraws the tree and the validation status.
+func (v *datasetEditorView) refresh() {
+	v.tree.Refresh()
+
+	problems := data.Validate(v.editor.Data())
+	switch {
+	case len(problems) > 0:
+		v.status.SetText(i18n.T("editor.invalid", len(problems), problems[0].Error()))
+	case v.dirty:
+		v.status.SetText(i18n.T("editor.unsaved"))
+	default:
+		v.status.SetText(i18n.T("editor.valid"))
+	}
+	if v.path == "" {
+		v.saveButton.Disable() // The built-in dataset can only be saved under a new name
+	} else {
+		v.saveButton.Enable()
+	}
+	title := i18n.T("editor.title")
+	if v.path != "" {
+		title = fmt.Sprintf("%s – %s", title, v.path)
+	}
+	if v.dirty {
+		title = "* " + title
+	}
+	v.win.SetTitle(title)
+}
@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+)
+
+// TestDatasetEditorApplyAndSave tests editing through the form and saving a valid file.
+func TestDatasetEditorApplyAndSave(t *testing.T) {
+	app := test.NewApp()
+	defer app.Quit()
+
+	base := data.EmotionData{
+		EmotionTypes: map[string]data.EmotionType{
+			"primary":   {ID: "primary", Level: 1},
+			"secondary": {ID: "secondary", Level: 2},
+		},
+		Emotions: map[string]data.Emotion{
+			"happy":   {ID: "happy", Name: "Happy", Type: "primary", Color: "#F29727"},
+			"bad":     {ID: "bad", Name: "Bad", Type: "primary", Color: "#4B8A5B"},
+			"playful": {ID: "playful", Name: "Playful", Type: "secondary", ParentID: "happy"},
+		},
+	}
+	path := t.TempDir() + "/custom.json"
+	var savedTo string
+	editorView := newDatasetEditorView(app, base, path, nil, func(p string) { savedTo = p })
+	defer editorView.win.Close()
+
+	// Select "playful", rename, recolor and move it under "bad"
+	editorView.revealAndSelect("playful")
+	editorView.nameEntry.SetText("Playful Mood")
+	editorView.colorEntry.SetText("#abc")
+	editorView.parentSelect.SetSelected("Bad (bad)")
+	editorView.applyChanges()
+
+	playful, _ := editorView.editor.Emotion("playful")
+	if playful.Name != "Playful Mood" || playful.Color != "#AABBCC" || playful.ParentID != "bad" {
+		t.Errorf("Form changes not applied: %+v", playful)
+	}
+	if !editorView.dirty {
+		t.Errorf("Expected unsaved changes to be tracked")
+	}
+
+	editorView.save(path)
+	if savedTo != path || editorView.dirty {
+		t.Errorf("Expected a save to '%s' (got '%s', dirty=%v)", path, savedTo, editorView.dirty)
+	}
+	loaded, err := data.LoadEmotionsFile(path)
+	if err != nil {
+		t.Fatalf("Saved dataset does not load: %v", err)
+	}
+	if loaded.Emotions["playful"].ParentID != "bad" {
+		t.Errorf("Saved dataset is missing the edits: %+v", loaded.Emotions["playful"])
+	}
+}
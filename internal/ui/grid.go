@@ -0,0 +1,227 @@
+// internal/ui/grid.go
+package ui
+
+import (
+	"image/color"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/itsforsxm123/emotion-explorer/internal/i18n"
+)
+
+// cardSize is the size of one emotion card in a grid.
+var cardSize = fyne.NewSize(200, 60)
+
+// --- Card Cache ---
+
+// cardModel is everything a card needs to draw one emotion, worked out once
+// per emotion instead of on every render: names, ancestry and colors are
+// resolved here, cells only copy them.
+type cardModel struct {
+	name        string      // Display name in the active language
+	description string      // Accessible description (see describeEmotion)
+	color       color.Color // Card background (see EmotionColor)
+	textColor   color.Color // Readable text on the background
+}
+
+// cardCache holds the card models of the configured dataset by emotion ID.
+// It is emptied by ConfigureColors (new dataset or palette) and whenever the
+// language changes, so it never outgrows the dataset.
+type cardCache struct {
+	mu     sync.Mutex
+	locale string               // Language the names were resolved in
+	models map[string]cardModel // Emotion ID -> card model
+}
+
+var cards cardCache
+
+// cardFor returns the card model for an emotion, building it on first use.
+func cardFor(emotion data.Emotion) cardModel {
+	cards.mu.Lock()
+	defer cards.mu.Unlock()
+	if locale := i18n.Locale(); cards.models == nil || cards.locale != locale {
+		cards.models = make(map[string]cardModel)
+		cards.locale = locale
+	}
+	if model, ok := cards.models[emotion.ID]; ok {
+		return model
+	}
+	background := EmotionColor(emotion)
+	model := cardModel{
+		name:        DisplayName(emotion),
+		description: describeEmotion(emotion),
+		color:       background,
+		textColor:   ReadableTextColor(background),
+	}
+	cards.models[emotion.ID] = model
+	return model
+}
+
+// resetCardCache forgets every card model, e.g. after the colors changed.
+func resetCardCache() {
+	cards.mu.Lock()
+	defer cards.mu.Unlock()
+	cards.models = nil
+}
+
+// --- Emotion Grid ---
+
+// EmotionGrid is a virtualized grid of emotion cards built on
+// widget.GridWrap: only the visible cells exist and they are recycled as the
+// user scrolls, so lists of thousands of emotions stay cheap.
+// The grid holds keyboard focus as a whole and highlights one card:
+// Enter/Return (or Space) selects it, arrow keys move between cards and
+// typed letters jump to a card by name.
+type EmotionGrid struct {
+	widget.GridWrap
+
+	emotions   []data.Emotion
+	onSelected func(emotion data.Emotion)
+	minRows    int // Rows the grid asks room for (0: one, like any GridWrap)
+
+	// Keyboard navigation state
+	current   int       // Index of the highlighted card
+	focused   bool      // True while the grid holds keyboard focus
+	typed     string    // Current type-ahead buffer
+	lastTyped time.Time // When the last rune was typed
+}
+
+// NewEmotionGrid creates a grid showing emotions (in the given order) that
+// calls onSelected when one is tapped or activated from the keyboard.
+func NewEmotionGrid(emotions []data.Emotion, onSelected func(emotion data.Emotion)) *EmotionGrid {
+	g := &EmotionGrid{emotions: emotions, onSelected: onSelected}
+	g.Length = func() int { return len(g.emotions) }
+	g.CreateItem = func() fyne.CanvasObject { return newEmotionCell(g) }
+	g.UpdateItem = func(id widget.GridWrapItemID, item fyne.CanvasObject) {
+		item.(*emotionCell).show(id, cardFor(g.emotions[id]), g.focused && id == g.current)
+	}
+	g.ExtendBaseWidget(g)
+	return g
+}
+
+// SetEmotions replaces the emotions shown, e.g. new search results, and
+// scrolls back to the first one.
+func (g *EmotionGrid) SetEmotions(emotions []data.Emotion) {
+	g.emotions = emotions
+	g.current = 0
+	g.typed = ""
+	g.ScrollToTop()
+	g.Refresh()
+}
+
+// Emotions returns the emotions shown, in grid order.
+func (g *EmotionGrid) Emotions() []data.Emotion {
+	return g.emotions
+}
+
+// Current returns the highlighted emotion, and false if the grid is empty.
+func (g *EmotionGrid) Current() (data.Emotion, bool) {
+	if g.current < 0 || g.current >= len(g.emotions) {
+		return data.Emotion{}, false
+	}
+	return g.emotions[g.current], true
+}
+
+// MinSize asks room for minRows rows of cards, if set.
+func (g *EmotionGrid) MinSize() fyne.Size {
+	size := g.GridWrap.MinSize()
+	if g.minRows > 1 {
+		rows := float32(g.minRows)
+		height := rows*cardSize.Height + (rows-1)*theme.Padding()
+		if height > size.Height {
+			size.Height = height
+		}
+	}
+	return size
+}
+
+// activate selects the emotion at index, as a tap or Enter does.
+func (g *EmotionGrid) activate(index int) {
+	if index < 0 || index >= len(g.emotions) {
+		return
+	}
+	g.moveTo(index)
+	if g.onSelected != nil {
+		g.onSelected(g.emotions[index])
+	}
+}
+
+// moveTo highlights the card at index and scrolls it into view.
+func (g *EmotionGrid) moveTo(index int) {
+	if index < 0 || index >= len(g.emotions) || index == g.current {
+		return
+	}
+	previous := g.current
+	g.current = index
+	g.RefreshItem(previous)
+	g.ScrollTo(index)
+	g.RefreshItem(index)
+}
+
+// --- fyne.Focusable implementation ---
+//
+// These replace GridWrap's own keyboard handling, which knows nothing about
+// Enter or type-ahead; GridWrap's focus highlight stays off and the cells
+// draw theirs instead.
+
+// FocusGained is called when the grid receives keyboard focus.
+func (g *EmotionGrid) FocusGained() {
+	g.focused = true
+	g.ScrollTo(g.current)
+	g.RefreshItem(g.current)
+}
+
+// FocusLost is called when the grid loses keyboard focus.
+func (g *EmotionGrid) FocusLost() {
+	g.focused = false
+	g.RefreshItem(g.current)
+}
+
+// TypedRune feeds printable characters into the type-ahead search.
+// A single letter moves past the current card so repeated presses cycle.
+func (g *EmotionGrid) TypedRune(r rune) {
+	now := time.Now()
+	if now.Sub(g.lastTyped) > typeAheadTimeout {
+		g.typed = ""
+	}
+	g.lastTyped = now
+	g.typed += strings.ToLower(string(r))
+
+	labels := make([]string, len(g.emotions))
+	for i, emotion := range g.emotions {
+		labels[i] = cardFor(emotion).name // Type-ahead matches what the user sees
+	}
+	start := g.current
+	if len([]rune(g.typed)) == 1 {
+		start = g.current + 1
+	}
+	if match := matchTypeAhead(labels, g.typed, start); match >= 0 {
+		g.moveTo(match)
+	}
+}
+
+// TypedKey handles activation and arrow-key movement.
+// Keys the grid does not use are forwarded to the canvas key handler so
+// window-level bindings (e.g. Escape for back) keep working while the grid
+// holds focus.
+func (g *EmotionGrid) TypedKey(ev *fyne.KeyEvent) {
+	switch ev.Name {
+	case fyne.KeyReturn, fyne.KeyEnter, fyne.KeySpace:
+		g.activate(g.current)
+	case fyne.KeyUp, fyne.KeyDown, fyne.KeyLeft, fyne.KeyRight:
+		g.moveTo(nextGridIndex(g.current, len(g.emotions), g.ColumnCount(), ev.Name))
+	default:
+		if c := canvasFor(g); c != nil && c.OnTypedKey() != nil {
+			c.OnTypedKey()(ev)
+		}
+	}
+}
+
+// Ensure EmotionGrid implements the interfaces the event system checks for.
+var _ fyne.Focusable = (*EmotionGrid)(nil)
@@ -0,0 +1,77 @@
+// internal/ui/grid_test.go
+package ui
+
+import (
+	"fmt"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/itsforsxm123/emotion-explorer/internal/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEmotionGrid tests that a large grid only creates the visible cells and
+// that the keyboard moves through all of it.
+func TestEmotionGrid(t *testing.T) {
+	app := test.NewApp()
+	defer app.Quit()
+
+	emotions := make([]data.Emotion, 5000)
+	for i := range emotions {
+		emotions[i] = data.Emotion{ID: fmt.Sprintf("e%d", i), Name: fmt.Sprintf("Emotion %d", i)}
+	}
+	emotions[4321].Name = "Zest"
+	var selected []string
+	grid := NewEmotionGrid(emotions, func(e data.Emotion) { selected = append(selected, e.ID) })
+	created := 0
+	createItem := grid.CreateItem
+	grid.CreateItem = func() fyne.CanvasObject {
+		created++
+		return createItem()
+	}
+
+	w := test.NewWindow(grid)
+	defer w.Close()
+	w.Resize(fyne.NewSize(640, 480))
+	assert.Greater(t, created, 0)
+	assert.Less(t, created, 100, "Only the visible cells are created")
+
+	assert.True(t, FocusInitial(w.Canvas(), grid))
+	grid.TypedKey(&fyne.KeyEvent{Name: fyne.KeyRight})
+	grid.TypedKey(&fyne.KeyEvent{Name: fyne.KeyDown})
+	current, _ := grid.Current()
+	assert.Equal(t, emotions[1+grid.ColumnCount()].ID, current.ID)
+
+	grid.TypedRune('z') // Far below the visible cells
+	grid.TypedKey(&fyne.KeyEvent{Name: fyne.KeyReturn})
+	assert.Equal(t, []string{"e4321"}, selected)
+	assert.Less(t, created, 100, "Scrolling recycles cells")
+
+	grid.SetEmotions(emotions[:2])
+	current, _ = grid.Current()
+	assert.Equal(t, "e0", current.ID, "New emotions start at the first card")
+}
+
+// TestCardCache tests that card models are built once per emotion and
+// rebuilt when the colors or the language change.
+func TestCardCache(t *testing.T) {
+	previousLocale := i18n.Locale()
+	defer i18n.SetLocale(previousLocale)
+	i18n.SetLocale("en")
+
+	calm := data.Emotion{ID: "calm", Name: "Calm", Color: "#336699", Names: map[string]string{"es": "Tranquilo"}}
+	ConfigureColors(map[string]data.Emotion{"calm": calm}, false)
+	assert.Equal(t, "Calm", cardFor(calm).name)
+
+	renamed := calm
+	renamed.Name = "Serene"
+	assert.Equal(t, "Calm", cardFor(renamed).name, "Built once per ID")
+
+	ConfigureColors(map[string]data.Emotion{"calm": renamed}, false)
+	assert.Equal(t, "Serene", cardFor(renamed).name, "Rebuilt for a new dataset")
+
+	i18n.SetLocale("es")
+	assert.Equal(t, "Tranquilo", cardFor(renamed).name, "Rebuilt for a new language")
+}
@@ -0,0 +1,107 @@
+// internal/ui/keyboard.go
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// typeAheadTimeout is how long the type-ahead buffer survives between key presses.
+const typeAheadTimeout = time.Second
+
+// nextGridIndex computes which cell an arrow key moves to in a grid with the
+// given number of items laid out in rows of cols cells.
+// Movement stops at the edges instead of wrapping.
+func nextGridIndex(current, count, cols int, key fyne.KeyName) int {
+	if count == 0 {
+		return -1
+	}
+	if cols < 1 {
+		cols = 1
+	}
+	next := current
+	switch key {
+	case fyne.KeyLeft:
+		next = current - 1
+	case fyne.KeyRight:
+		next = current + 1
+	case fyne.KeyUp:
+		next = current - cols
+	case fyne.KeyDown:
+		next = current + cols
+	}
+	if next < 0 || next >= count {
+		return current
+	}
+	return next
+}
+
+// matchTypeAhead returns the index of the first label (searching from start
+// and wrapping around) that begins with prefix, case-insensitively.
+// Returns -1 if nothing matches.
+func matchTypeAhead(labels []string, prefix string, start int) int {
+	if len(labels) == 0 || prefix == "" {
+		return -1
+	}
+	prefix = strings.ToLower(prefix)
+	for i := 0; i < len(labels); i++ {
+		idx := (start + i) % len(labels)
+		if idx < 0 {
+			idx += len(labels)
+		}
+		if strings.HasPrefix(strings.ToLower(labels[idx]), prefix) {
+			return idx
+		}
+	}
+	return -1
+}
+
+// FocusInitial places keyboard focus on the natural starting point of a view:
+// the first text entry if the view has one (e.g. the search box), otherwise
+// the first emotion grid with cards in it. Returns false if nothing focusable was found.
+func FocusInitial(c fyne.Canvas, view fyne.CanvasObject) bool {
+	if c == nil || view == nil {
+		return false
+	}
+	if entry := findObject(view, func(o fyne.CanvasObject) bool {
+		_, ok := o.(*widget.Entry)
+		return ok
+	}); entry != nil {
+		c.Focus(entry.(fyne.Focusable))
+		return true
+	}
+	if grid := findObject(view, func(o fyne.CanvasObject) bool {
+		grid, ok := o.(*EmotionGrid)
+		return ok && len(grid.Emotions()) > 0
+	}); grid != nil {
+		c.Focus(grid.(fyne.Focusable))
+		return true
+	}
+	return false
+}
+
+// findObject walks containers and scroll containers depth-first and returns
+// the first visible object matching the predicate.
+func findObject(obj fyne.CanvasObject, match func(fyne.CanvasObject) bool) fyne.CanvasObject {
+	if obj == nil || !obj.Visible() {
+		return nil
+	}
+	if match(obj) {
+		return obj
+	}
+	switch o := obj.(type) {
+	case *fyne.Container:
+		for _, child := range o.Objects {
+			if found := findObject(child, match); found != nil {
+				return found
+			}
+		}
+	case *container.Scroll:
+		return findObject(o.Content, match)
+	}
+	return nil
+}
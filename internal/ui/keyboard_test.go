@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNextGridIndex tests arrow-key movement within a grid of cards.
+func TestNextGridIndex(t *testing.T) {
+	// A grid of 7 cards in rows of 3:
+	//   0 1 2
+	//   3 4 5
+	//   6
+	testCases := []struct {
+		name     string
+		current  int
+		key      fyne.KeyName
+		expected int
+	}{
+		{name: "Right moves to next card", current: 0, key: fyne.KeyRight, expected: 1},
+		{name: "Right wraps into next row", current: 2, key: fyne.KeyRight, expected: 3},
+		{name: "Left stops at first card", current: 0, key: fyne.KeyLeft, expected: 0},
+		{name: "Down moves one row", current: 1, key: fyne.KeyDown, expected: 4},
+		{name: "Down stops below last row", current: 4, key: fyne.KeyDown, expected: 4},
+		{name: "Up moves one row", current: 6, key: fyne.KeyUp, expected: 3},
+		{name: "Up stops at top row", current: 2, key: fyne.KeyUp, expected: 2},
+		{name: "Other keys do not move", current: 5, key: fyne.KeyTab, expected: 5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, nextGridIndex(tc.current, 7, 3, tc.key))
+		})
+	}
+
+	t.Run("Empty grid", func(t *testing.T) {
+		assert.Equal(t, -1, nextGridIndex(0, 0, 3, fyne.KeyRight))
+	})
+}
+
+// TestMatchTypeAhead tests prefix matching used for type-ahead jumps.
+func TestMatchTypeAhead(t *testing.T) {
+	labels := []string{"Angry", "Bad", "Disgusted", "Fearful", "Happy", "Sad", "Surprised"}
+
+	testCases := []struct {
+		name     string
+		prefix   string
+		start    int
+		expected int
+	}{
+		{name: "Single letter", prefix: "h", start: 0, expected: 4},
+		{name: "Case insensitive", prefix: "FE", start: 0, expected: 3},
+		{name: "Search starts at given index", prefix: "s", start: 6, expected: 6},
+		{name: "Search wraps around", prefix: "a", start: 3, expected: 0},
+		{name: "Longer prefix narrows match", prefix: "su", start: 0, expected: 6},
+		{name: "No match", prefix: "z", start: 0, expected: -1},
+		{name: "Empty prefix", prefix: "", start: 0, expected: -1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, matchTypeAhead(labels, tc.prefix, tc.start))
+		})
+	}
+}
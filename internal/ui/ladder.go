@@ -0,0 +1,115 @@
+// internal/ui/ladder.go
+package ui
+
+import (
+	"log/slog"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/analytics"
+	"github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/itsforsxm123/emotion-explorer/internal/i18n"
+)
+
+// --- Intensity Ladder ---
+
+// CreateIntensityLadder generates a slider over the words of an intensity
+// ladder (see core.IntensityLadder), mildest on the left, starting at
+// currentID. The word under the slider updates as it moves; onPicked
+// receives the word the user settles on (after a drag, a tap or an arrow
+// key), so callers can swap views without interrupting a drag.
+func CreateIntensityLadder(ladder []data.Emotion, currentID string, onPicked func(emotion data.Emotion)) fyne.CanvasObject {
+	slog.Debug("Creating intensity ladder", "words", len(ladder), "current", currentID)
+	rung := core.LadderRung(ladder, currentID)
+	if rung < 0 {
+		rung = 0
+	}
+
+	word := widget.NewLabelWithStyle(DisplayName(ladder[rung]), fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	position := widget.NewLabelWithStyle(i18n.T("ladder.position", rung+1, len(ladder)), fyne.TextAlignCenter, fyne.TextStyle{})
+
+	slider := widget.NewSlider(0, float64(len(ladder)-1))
+	slider.Step = 1
+	slider.SetValue(float64(rung)) // Before the callbacks, so nothing fires yet
+	slider.OnChanged = func(value float64) {
+		current := ladderRungAt(value, len(ladder))
+		word.SetText(DisplayName(ladder[current]))
+		position.SetText(i18n.T("ladder.position", current+1, len(ladder)))
+	}
+	slider.OnChangeEnded = func(value float64) {
+		picked := ladder[ladderRungAt(value, len(ladder))]
+		slog.Debug("Intensity ladder settled", "emotionID", picked.ID)
+		if onPicked != nil {
+			onPicked(picked)
+		}
+	}
+
+	ends := container.NewBorder(nil, nil,
+		widget.NewLabel(i18n.T("ladder.milder")),   // Left
+		widget.NewLabel(i18n.T("ladder.stronger")), // Right
+	)
+	return container.NewVBox(word, position, slider, ends)
+}
+
+// ladderRungAt converts a slider value to a rung index on a ladder of n
+// words, rounding to the nearest word and clamping to the ends.
+func ladderRungAt(value float64, n int) int {
+	rung := int(math.Round(value))
+	if rung < 0 {
+		return 0
+	}
+	if rung > n-1 {
+		return n - 1
+	}
+	return rung
+}
+
+// CreateEscalationView lists each intensity ladder the journal has entries
+// on, with the words logged in order and how often consecutive entries got
+// stronger or milder (see analytics.Escalation).
+func CreateEscalationView(trends []analytics.LadderTrend, resolver *core.IDResolver) fyne.CanvasObject {
+	if len(trends) == 0 {
+		return container.NewCenter(widget.NewLabel(i18n.T("escalation.empty")))
+	}
+	return widget.NewList(
+		func() int { return len(trends) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			item.(*widget.Label).SetText(formatLadderTrend(trends[id], resolver))
+		},
+	)
+}
+
+// maxTrendWords caps how many of a ladder's latest words a trend line shows.
+const maxTrendWords = 6
+
+// formatLadderTrend renders a ladder's entries as e.g.
+// "Angry: Annoyed → Furious → Annoyed (↑1 ↓1)", with only the latest words
+// when there are many.
+func formatLadderTrend(trend analytics.LadderTrend, resolver *core.IDResolver) string {
+	family := i18n.T("escalation.topLevel")
+	if emotion, ok := resolver.Lookup(trend.FamilyID); ok {
+		family = DisplayName(emotion)
+	}
+	steps := trend.Steps
+	words := ""
+	if len(steps) > maxTrendWords {
+		steps = steps[len(steps)-maxTrendWords:]
+		words = "… → "
+	}
+	for i, step := range steps {
+		if i > 0 {
+			words += " → "
+		}
+		if emotion, ok := resolver.Lookup(step.EmotionID); ok {
+			words += DisplayName(emotion)
+		} else {
+			words += step.EmotionID
+		}
+	}
+	return i18n.T("escalation.line", family, words, trend.Escalations, trend.DeEscalations)
+}
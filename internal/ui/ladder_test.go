@@ -0,0 +1,72 @@
+// internal/ui/ladder_test.go
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/itsforsxm123/emotion-explorer/internal/analytics"
+	"github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/stretchr/testify/assert"
+)
+
+// ladderWords is a three-word intensity ladder, mildest first.
+var ladderWords = []data.Emotion{
+	{ID: "annoyed", Name: "Annoyed", ParentID: "angry", Intensity: 1},
+	{ID: "frustrated", Name: "Frustrated", ParentID: "angry", Intensity: 2},
+	{ID: "furious", Name: "Furious", ParentID: "angry", Intensity: 3},
+}
+
+// TestIntensityLadder tests sliding between the words of a ladder.
+func TestIntensityLadder(t *testing.T) {
+	app := test.NewApp()
+	defer app.Quit()
+
+	var picked []string
+	view := CreateIntensityLadder(ladderWords, "frustrated", func(e data.Emotion) { picked = append(picked, e.ID) })
+	objects := view.(*fyne.Container).Objects
+	word, slider := objects[0].(*widget.Label), objects[2].(*widget.Slider)
+	assert.Equal(t, "Frustrated", word.Text)
+	assert.Equal(t, 1.0, slider.Value)
+
+	slider.TypedKey(&fyne.KeyEvent{Name: fyne.KeyRight})
+	assert.Equal(t, "Furious", word.Text)
+	slider.TypedKey(&fyne.KeyEvent{Name: fyne.KeyRight}) // Already the strongest
+	slider.TypedKey(&fyne.KeyEvent{Name: fyne.KeyLeft})
+	assert.Equal(t, "Frustrated", word.Text)
+	assert.Equal(t, []string{"furious", "frustrated"}, picked)
+}
+
+// TestLadderRungAt tests converting slider values to words.
+func TestLadderRungAt(t *testing.T) {
+	assert.Equal(t, 1, ladderRungAt(0.6, 3), "Rounds to the nearest word")
+	assert.Equal(t, 0, ladderRungAt(-1, 3))
+	assert.Equal(t, 2, ladderRungAt(7, 3))
+}
+
+// TestFormatLadderTrend tests the lines of the escalation tab.
+func TestFormatLadderTrend(t *testing.T) {
+	emotions := map[string]data.Emotion{"angry": {ID: "angry", Name: "Angry"}}
+	for _, word := range ladderWords {
+		emotions[word.ID] = word
+	}
+	resolver := core.NewIDResolver(data.EmotionData{Emotions: emotions})
+	step := func(id string) analytics.LadderStep { return analytics.LadderStep{Time: time.Now(), EmotionID: id} }
+
+	trend := analytics.LadderTrend{
+		FamilyID:      "angry",
+		Steps:         []analytics.LadderStep{step("annoyed"), step("furious"), step("annoyed")},
+		Escalations:   1,
+		DeEscalations: 1,
+	}
+	assert.Equal(t, "Angry: Annoyed → Furious → Annoyed (↑1 ↓1)", formatLadderTrend(trend, resolver))
+
+	for len(trend.Steps) < maxTrendWords+2 {
+		trend.Steps = append(trend.Steps, step("gone"))
+	}
+	assert.Equal(t, "Angry: … → Annoyed → gone → gone → gone → gone → gone (↑1 ↓1)", formatLadderTrend(trend, resolver), "Only the latest words")
+}
@@ -0,0 +1,126 @@
+// internal/ui/learn.go
+package ui
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/itsforsxm123/emotion-explorer/internal/i18n"
+	"github.com/itsforsxm123/emotion-explorer/internal/learn"
+)
+
+// --- Learning View ---
+
+// CreateLearnView runs a quiz session: one question at a time with its
+// choices as buttons, then whether the answer was right (with the family
+// path or the intensity ladder that explains it) and a Next button, and a
+// summary once nothing is left. onAnswered is called with the updated
+// progress after every answer, e.g. to save it. now is the clock answers
+// are graded with.
+func CreateLearnView(
+	session *learn.Session, // The session to run
+	allEmotions map[string]data.Emotion, // Dataset the questions come from
+	now func() time.Time, // Clock for grading
+	onAnswered func(progress learn.Progress), // Callback after every answer
+) fyne.CanvasObject {
+	stats := widget.NewLabel("")
+	updateStats := func() {
+		s := session.Stats(now())
+		stats.SetText(i18n.T("learn.stats", s.Learned, s.Due, s.New, s.Total))
+	}
+	updateStats()
+
+	body := container.NewVBox()
+	show := func(objects ...fyne.CanvasObject) {
+		body.Objects = objects
+		body.Refresh()
+	}
+
+	var showQuestion func()
+	showQuestion = func() {
+		question, ok := session.Next()
+		if !ok {
+			show(learnSummary(session, now())...)
+			return
+		}
+		prompt := wrappedLabel(learnPrompt(question))
+		prompt.TextStyle = fyne.TextStyle{Bold: true}
+		objects := []fyne.CanvasObject{prompt}
+		for _, choice := range question.Choices {
+			objects = append(objects, widget.NewButton(DisplayName(choice), func() {
+				correct := session.Answer(choice.ID, now())
+				slog.Debug("Quiz answered", "card", question.Card.Key(), "correct", correct)
+				updateStats()
+				if onAnswered != nil {
+					onAnswered(session.Progress())
+				}
+
+				verdict := i18n.T("learn.correct")
+				if !correct {
+					verdict = i18n.T("learn.wrong", DisplayName(question.Answer()))
+				}
+				next := widget.NewButton(i18n.T("learn.next"), showQuestion)
+				show(prompt, wrappedLabel(verdict), wrappedLabel(learnExplanation(question, allEmotions)), next)
+			}))
+		}
+		objects = append(objects, widget.NewLabel(i18n.T("learn.remaining", session.Remaining())))
+		show(objects...)
+	}
+	showQuestion()
+
+	topItems := append(newHeader(i18n.T("learn.title")), stats)
+	return container.NewBorder(
+		container.NewVBox(topItems...), // Top: Header and progress
+		nil,                            // Bottom
+		nil,                            // Left
+		nil,                            // Right
+		container.NewVScroll(body),     // Center: The question, or how it went
+	)
+}
+
+// learnPrompt returns the text of a question.
+func learnPrompt(question learn.Question) string {
+	if question.Card.Kind == learn.KindIntensity {
+		return i18n.T("learn.intensity")
+	}
+	return i18n.T("learn.family", DisplayName(question.Subject))
+}
+
+// learnExplanation shows why the answer is right: the way from the family
+// down to the word, or the ladder it stands on.
+func learnExplanation(question learn.Question, allEmotions map[string]data.Emotion) string {
+	if question.Card.Kind == learn.KindIntensity {
+		ladder := core.IntensityLadder(question.Subject.ID, nil, allEmotions)
+		names := make([]string, len(ladder))
+		for i, rung := range ladder {
+			names[i] = DisplayName(rung)
+		}
+		return i18n.T("learn.ladder", strings.Join(names, " < "))
+	}
+	return i18n.T("learn.path", AncestryPath(question.Path))
+}
+
+// learnSummary is shown when a session has no questions left: the score,
+// and when to come back.
+func learnSummary(session *learn.Session, now time.Time) []fyne.CanvasObject {
+	var summary []fyne.CanvasObject
+	switch {
+	case session.Answered > 0:
+		summary = append(summary, wrappedLabel(i18n.T("learn.done", session.Correct, session.Answered)))
+	case session.Stats(now).Total == 0:
+		return []fyne.CanvasObject{wrappedLabel(i18n.T("learn.emptyDeck"))}
+	default:
+		summary = append(summary, wrappedLabel(i18n.T("learn.nothingDue")))
+	}
+	if next, ok := session.NextReview(now); ok {
+		summary = append(summary, widget.NewLabel(i18n.T("learn.nextReview", next.Local().Format("2006-01-02"))))
+	}
+	return summary
+}
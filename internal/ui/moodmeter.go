@@ -0,0 +1,374 @@
+// internal/ui/moodmeter.go
+package ui
+
+import (
+	"image/color"
+	"log/slog"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/analytics"
+	"github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/itsforsxm123/emotion-explorer/internal/i18n"
+)
+
+// MoodMeter is a valence/arousal plane (see core.Coordinates) split into
+// four colored quadrants, mood-meter style: pleasant to the right, high
+// energy at the top. Tapping picks a point; the arrow keys move the marker
+// and Enter picks it. Dots can be drawn on the plane, e.g. journal entries.
+type MoodMeter struct {
+	widget.BaseWidget
+	OnPicked func(valence, arousal float64) // nil makes the meter read-only
+
+	dots      []MoodDot
+	valence   float64 // Marker position
+	arousal   float64
+	hasMarker bool
+	focused   bool
+}
+
+// MoodDot is a colored point drawn on a MoodMeter.
+type MoodDot struct {
+	Valence, Arousal float64
+	Color            color.Color
+}
+
+// moodMeterStep is how far one arrow key press moves the marker.
+const moodMeterStep = 0.1
+
+// Quadrant colors, translucent so they work on light and dark themes.
+var (
+	quadrantHighUnpleasant = color.NRGBA{R: 0xE5, G: 0x39, B: 0x35, A: 0x70} // Red: angry, anxious
+	quadrantHighPleasant   = color.NRGBA{R: 0xFD, G: 0xD8, B: 0x35, A: 0x70} // Yellow: excited, joyful
+	quadrantLowUnpleasant  = color.NRGBA{R: 0x1E, G: 0x88, B: 0xE5, A: 0x70} // Blue: sad, tired
+	quadrantLowPleasant    = color.NRGBA{R: 0x43, G: 0xA0, B: 0x47, A: 0x70} // Green: calm, content
+)
+
+// NewMoodMeter creates a mood meter that calls onPicked with the chosen point.
+func NewMoodMeter(onPicked func(valence, arousal float64)) *MoodMeter {
+	m := &MoodMeter{OnPicked: onPicked}
+	m.ExtendBaseWidget(m)
+	return m
+}
+
+// SetDots replaces the dots drawn on the plane.
+func (m *MoodMeter) SetDots(dots []MoodDot) {
+	m.dots = dots
+	m.Refresh()
+}
+
+// pick moves the marker to a point and reports it.
+func (m *MoodMeter) pick(valence, arousal float64) {
+	m.valence, m.arousal, m.hasMarker = clampDimension(valence), clampDimension(arousal), true
+	m.Refresh()
+	slog.Debug("Mood meter picked", "valence", m.valence, "arousal", m.arousal)
+	m.OnPicked(m.valence, m.arousal)
+}
+
+// Tapped picks the tapped point.
+func (m *MoodMeter) Tapped(ev *fyne.PointEvent) {
+	if m.OnPicked == nil {
+		return
+	}
+	valence, arousal := positionToPlane(ev.Position, m.Size())
+	m.pick(valence, arousal)
+}
+
+// --- fyne.Focusable implementation ---
+
+// FocusGained shows the focus outline.
+func (m *MoodMeter) FocusGained() {
+	m.focused = true
+	m.Refresh()
+}
+
+// FocusLost hides the focus outline.
+func (m *MoodMeter) FocusLost() {
+	m.focused = false
+	m.Refresh()
+}
+
+// TypedRune is unused; typing has no meaning on the plane.
+func (m *MoodMeter) TypedRune(rune) {}
+
+// TypedKey moves the marker with the arrow keys and picks it with Enter.
+// Other keys are forwarded to the canvas, as for EmotionGrid.
+func (m *MoodMeter) TypedKey(ev *fyne.KeyEvent) {
+	moves := map[fyne.KeyName][2]float64{
+		fyne.KeyLeft:  {-moodMeterStep, 0},
+		fyne.KeyRight: {moodMeterStep, 0},
+		fyne.KeyUp:    {0, moodMeterStep},
+		fyne.KeyDown:  {0, -moodMeterStep},
+	}
+	switch move, ok := moves[ev.Name]; {
+	case ok && m.OnPicked != nil:
+		m.valence = clampDimension(m.valence + move[0])
+		m.arousal = clampDimension(m.arousal + move[1])
+		m.hasMarker = true
+		m.Refresh()
+	case (ev.Name == fyne.KeyReturn || ev.Name == fyne.KeyEnter || ev.Name == fyne.KeySpace) && m.OnPicked != nil:
+		m.pick(m.valence, m.arousal)
+	default:
+		if c := canvasFor(m); c != nil && c.OnTypedKey() != nil {
+			c.OnTypedKey()(ev)
+		}
+	}
+}
+
+// AccessibleName describes the control.
+func (m *MoodMeter) AccessibleName() string {
+	return i18n.T("moodMeter.title")
+}
+
+// AccessibleRole reports that the meter is picked like a button.
+func (m *MoodMeter) AccessibleRole() string {
+	return RoleButton
+}
+
+// AccessibleDescription explains how to use the meter.
+func (m *MoodMeter) AccessibleDescription() string {
+	return i18n.T("moodMeter.hint")
+}
+
+// Ensure MoodMeter implements the interfaces the event system checks for.
+var (
+	_ fyne.Tappable  = (*MoodMeter)(nil)
+	_ fyne.Focusable = (*MoodMeter)(nil)
+	_ Accessible     = (*MoodMeter)(nil)
+)
+
+// --- Plane Geometry ---
+
+// planeToPosition converts a point on the plane to a position in a widget
+// of the given size: valence -1..1 runs left to right, arousal -1..1
+// bottom to top.
+func planeToPosition(valence, arousal float64, size fyne.Size) fyne.Position {
+	return fyne.NewPos(
+		float32((clampDimension(valence)+1)/2)*size.Width,
+		float32((1-clampDimension(arousal))/2)*size.Height,
+	)
+}
+
+// positionToPlane is the inverse of planeToPosition. Positions outside the
+// widget are clamped to its edges.
+func positionToPlane(pos fyne.Position, size fyne.Size) (valence, arousal float64) {
+	if size.Width <= 0 || size.Height <= 0 {
+		return 0, 0
+	}
+	valence = float64(pos.X/size.Width)*2 - 1
+	arousal = 1 - float64(pos.Y/size.Height)*2
+	return clampDimension(valence), clampDimension(arousal)
+}
+
+// clampDimension limits a valence or arousal value to -1..1.
+func clampDimension(v float64) float64 {
+	return math.Max(-1, math.Min(1, v))
+}
+
+// --- Renderer ---
+
+// CreateRenderer returns the renderer for the mood meter.
+func (m *MoodMeter) CreateRenderer() fyne.WidgetRenderer {
+	r := &moodMeterRenderer{meter: m}
+	for _, fill := range []color.Color{quadrantHighUnpleasant, quadrantHighPleasant, quadrantLowUnpleasant, quadrantLowPleasant} {
+		r.quadrants = append(r.quadrants, canvas.NewRectangle(fill))
+	}
+	r.axes = []*canvas.Line{canvas.NewLine(color.Black), canvas.NewLine(color.Black)}
+	for _, key := range []string{"moodMeter.highEnergy", "moodMeter.lowEnergy", "moodMeter.unpleasant", "moodMeter.pleasant"} {
+		label := canvas.NewText(i18n.T(key), color.Black)
+		label.TextSize = theme.CaptionTextSize()
+		r.labels = append(r.labels, label)
+	}
+	r.marker = canvas.NewCircle(color.Transparent)
+	r.marker.StrokeWidth = 3
+	r.outline = canvas.NewRectangle(color.Transparent)
+	r.outline.StrokeWidth = 2
+	r.Refresh()
+	return r
+}
+
+// moodMeterRenderer draws the quadrants, axes, labels, dots and marker.
+type moodMeterRenderer struct {
+	meter     *MoodMeter
+	quadrants []*canvas.Rectangle // High/unpleasant, high/pleasant, low/unpleasant, low/pleasant
+	axes      []*canvas.Line      // Vertical (valence 0), horizontal (arousal 0)
+	labels    []*canvas.Text      // Top, bottom, left, right
+	dots      []*canvas.Circle
+	marker    *canvas.Circle
+	outline   *canvas.Rectangle // Focus outline
+}
+
+const (
+	moodDotSize    = 10
+	moodMarkerSize = 18
+)
+
+func (r *moodMeterRenderer) Layout(size fyne.Size) {
+	half := fyne.NewSize(size.Width/2, size.Height/2)
+	for i, quadrant := range r.quadrants {
+		quadrant.Resize(half)
+		quadrant.Move(fyne.NewPos(float32(i%2)*half.Width, float32(i/2)*half.Height))
+	}
+	r.axes[0].Position1, r.axes[0].Position2 = fyne.NewPos(half.Width, 0), fyne.NewPos(half.Width, size.Height)
+	r.axes[1].Position1, r.axes[1].Position2 = fyne.NewPos(0, half.Height), fyne.NewPos(size.Width, half.Height)
+
+	padding := theme.Padding()
+	top, bottom, left, right := r.labels[0], r.labels[1], r.labels[2], r.labels[3]
+	top.Move(fyne.NewPos(half.Width+padding, padding))
+	bottom.Move(fyne.NewPos(half.Width+padding, size.Height-bottom.MinSize().Height-padding))
+	left.Move(fyne.NewPos(padding, half.Height+padding))
+	right.Move(fyne.NewPos(size.Width-right.MinSize().Width-padding, half.Height+padding))
+	for _, label := range r.labels {
+		label.Resize(label.MinSize())
+	}
+
+	for i, dot := range r.dots {
+		d := r.meter.dots[i]
+		centerCircle(dot, planeToPosition(d.Valence, d.Arousal, size), moodDotSize)
+	}
+	centerCircle(r.marker, planeToPosition(r.meter.valence, r.meter.arousal, size), moodMarkerSize)
+	r.outline.Resize(size)
+}
+
+// centerCircle sizes a circle to diameter and centers it on center.
+func centerCircle(circle *canvas.Circle, center fyne.Position, diameter float32) {
+	circle.Resize(fyne.NewSquareSize(diameter))
+	circle.Move(center.SubtractXY(diameter/2, diameter/2))
+}
+
+func (r *moodMeterRenderer) MinSize() fyne.Size {
+	return fyne.NewSquareSize(240)
+}
+
+func (r *moodMeterRenderer) Refresh() {
+	foreground := theme.Color(theme.ColorNameForeground)
+	for _, axis := range r.axes {
+		axis.StrokeColor = foreground
+		axis.StrokeWidth = 1
+		axis.Refresh()
+	}
+	for _, label := range r.labels {
+		label.Color = foreground
+		label.Refresh()
+	}
+
+	// One circle per dot; rebuilt only when the number changes
+	if len(r.dots) != len(r.meter.dots) {
+		r.dots = make([]*canvas.Circle, len(r.meter.dots))
+		for i := range r.dots {
+			r.dots[i] = canvas.NewCircle(color.Transparent)
+		}
+	}
+	for i, dot := range r.dots {
+		dot.FillColor = r.meter.dots[i].Color
+		dot.Refresh()
+	}
+
+	r.marker.StrokeColor = foreground
+	r.marker.Hidden = !r.meter.hasMarker
+	r.outline.StrokeColor = theme.Color(theme.ColorNameFocus)
+	r.outline.Hidden = !r.meter.focused
+	r.Layout(r.meter.Size())
+}
+
+func (r *moodMeterRenderer) Objects() []fyne.CanvasObject {
+	objects := make([]fyne.CanvasObject, 0, len(r.quadrants)+len(r.axes)+len(r.labels)+len(r.dots)+2)
+	for _, quadrant := range r.quadrants {
+		objects = append(objects, quadrant)
+	}
+	for _, axis := range r.axes {
+		objects = append(objects, axis)
+	}
+	for _, label := range r.labels {
+		objects = append(objects, label)
+	}
+	for _, dot := range r.dots {
+		objects = append(objects, dot)
+	}
+	return append(objects, r.marker, r.outline)
+}
+
+func (r *moodMeterRenderer) Destroy() {}
+
+// --- Views ---
+
+// moodMeterSuggestions is how many nearby words the mood meter offers.
+const moodMeterSuggestions = 6
+
+// CreateMoodMeterView generates the mood meter logging view: the user taps a
+// point on the plane and is offered the closest emotions (see
+// core.NearestEmotions). onSelected receives the chosen emotion and the
+// tapped point.
+func CreateMoodMeterView(
+	allEmotions map[string]data.Emotion,
+	onSelected func(emotion data.Emotion, valence, arousal float64),
+) fyne.CanvasObject {
+	slog.Debug("Creating mood meter view", "emotions", len(allEmotions))
+
+	hint := widget.NewLabel(i18n.T("moodMeter.hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	if len(core.NearestEmotions(0, 0, allEmotions, 1)) == 0 {
+		hint.SetText(i18n.T("moodMeter.noCoordinates"))
+	}
+
+	var picked struct{ valence, arousal float64 } // Point the suggestions are for
+	suggestions := NewEmotionGrid(nil, func(emotion data.Emotion) {
+		onSelected(emotion, picked.valence, picked.arousal)
+	})
+	suggestions.minRows = 2 // Room for every suggestion at the usual window width
+	meter := NewMoodMeter(func(valence, arousal float64) {
+		picked.valence, picked.arousal = valence, arousal
+		suggestions.SetEmotions(core.NearestEmotions(valence, arousal, allEmotions, moodMeterSuggestions))
+		if len(suggestions.Emotions()) > 0 {
+			if c := canvasFor(suggestions); c != nil {
+				c.Focus(suggestions) // Keyboard users continue with the words
+			}
+		}
+	})
+
+	top := append(newHeader(i18n.T("moodMeter.title")), hint)
+	return container.NewBorder(
+		container.NewVBox(top...),  // Top: Header and hint
+		suggestions,                // Bottom: Closest words for the last point
+		nil,                        // Left
+		nil,                        // Right
+		container.NewPadded(meter), // Center: The plane
+	)
+}
+
+// CreateMoodPlotView draws journal entries on the valence/arousal plane.
+// Points are drawn oldest first in their emotion's color, fading with age so
+// the most recent entries stand out.
+func CreateMoodPlotView(points []analytics.MoodPoint, resolver *core.IDResolver) fyne.CanvasObject {
+	if len(points) == 0 {
+		return container.NewCenter(widget.NewLabel(i18n.T("moodPlot.empty")))
+	}
+	dots := make([]MoodDot, len(points))
+	for i, point := range points {
+		dotColor := color.Color(fallbackEmotionColor)
+		if emotion, ok := resolver.Lookup(point.EmotionID); ok {
+			dotColor = EmotionColor(emotion)
+		}
+		faded := color.NRGBAModel.Convert(dotColor).(color.NRGBA)
+		faded.A = moodDotAlpha(i, len(points))
+		dots[i] = MoodDot{Valence: point.Valence, Arousal: point.Arousal, Color: faded}
+	}
+	meter := NewMoodMeter(nil) // Read-only
+	meter.SetDots(dots)
+	return container.NewBorder(nil, widget.NewLabel(i18n.T("moodPlot.caption", len(points))), nil, nil, container.NewPadded(meter))
+}
+
+// moodDotAlpha returns the opacity of the i-th of n dots, oldest first:
+// from a faint 0x40 for the oldest to fully opaque for the newest.
+func moodDotAlpha(i, n int) uint8 {
+	if n <= 1 {
+		return 0xFF
+	}
+	return uint8(0x40 + (0xFF-0x40)*i/(n-1))
+}
@@ -0,0 +1,46 @@
+// internal/ui/moodmeter_test.go
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMoodMeterGeometry tests converting between plane points and widget positions.
+func TestMoodMeterGeometry(t *testing.T) {
+	size := fyne.NewSize(200, 100)
+	testCases := []struct {
+		name             string
+		valence, arousal float64
+		position         fyne.Position
+	}{
+		{"Center is neutral", 0, 0, fyne.NewPos(100, 50)},
+		{"Top right is pleasant and energetic", 1, 1, fyne.NewPos(200, 0)},
+		{"Bottom left is unpleasant and calm", -1, -1, fyne.NewPos(0, 100)},
+		{"Quarter points", 0.5, -0.5, fyne.NewPos(150, 75)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.position, planeToPosition(tc.valence, tc.arousal, size))
+			valence, arousal := positionToPlane(tc.position, size)
+			assert.InDelta(t, tc.valence, valence, 1e-6)
+			assert.InDelta(t, tc.arousal, arousal, 1e-6)
+		})
+	}
+
+	// Taps outside the plane land on its edge
+	valence, arousal := positionToPlane(fyne.NewPos(-20, 130), size)
+	assert.Equal(t, []float64{-1, -1}, []float64{valence, arousal})
+	valence, arousal = positionToPlane(fyne.NewPos(10, 10), fyne.NewSize(0, 0))
+	assert.Equal(t, []float64{0, 0}, []float64{valence, arousal}, "An unsized meter has no plane yet")
+}
+
+// TestMoodDotAlpha tests fading older dots.
+func TestMoodDotAlpha(t *testing.T) {
+	assert.Equal(t, uint8(0xFF), moodDotAlpha(0, 1), "A single dot is opaque")
+	assert.Equal(t, uint8(0x40), moodDotAlpha(0, 5), "Oldest is faintest")
+	assert.Equal(t, uint8(0xFF), moodDotAlpha(4, 5), "Newest is opaque")
+	assert.Less(t, moodDotAlpha(1, 5), moodDotAlpha(2, 5))
+}
@@ -0,0 +1,122 @@
+// internal/ui/palette.go
+package ui
+
+import (
+	"image/color"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/itsforsxm123/emotion-explorer/internal/i18n"
+)
+
+// colorblindSafePalette is the Okabe-Ito palette, designed to stay
+// distinguishable for the common forms of color vision deficiency.
+// In colorblind mode each emotion family (root) gets one of these colors.
+var colorblindSafePalette = []color.NRGBA{
+	{R: 0xE6, G: 0x9F, B: 0x00, A: 0xFF}, // Orange
+	{R: 0x56, G: 0xB4, B: 0xE9, A: 0xFF}, // Sky blue
+	{R: 0x00, G: 0x9E, B: 0x73, A: 0xFF}, // Bluish green
+	{R: 0xF0, G: 0xE4, B: 0x42, A: 0xFF}, // Yellow
+	{R: 0x00, G: 0x72, B: 0xB2, A: 0xFF}, // Blue
+	{R: 0xD5, G: 0x5E, B: 0x00, A: 0xFF}, // Vermillion
+	{R: 0xCC, G: 0x79, B: 0xA7, A: 0xFF}, // Reddish purple
+}
+
+// fallbackEmotionColor is used when no emotion in the ancestry has a valid color.
+var fallbackEmotionColor = color.NRGBA{R: 128, G: 128, B: 128, A: 255}
+
+// colorScheme holds what the views need to resolve emotion colors and
+// describe emotions: the full dataset and the palette mode.
+type colorScheme struct {
+	emotions     map[string]data.Emotion
+	colorblind   bool
+	familyColors map[string]color.Color // Root emotion ID -> palette color (colorblind mode only)
+}
+
+var (
+	activeColors colorScheme
+	colorsMutex  sync.RWMutex // Datasets are reloaded off the UI goroutine
+)
+
+// currentColors returns the scheme set by the last ConfigureColors.
+func currentColors() colorScheme {
+	colorsMutex.RLock()
+	defer colorsMutex.RUnlock()
+	return activeColors
+}
+
+// ConfigureColors sets the dataset used to resolve emotion colors and
+// whether the colorblind-safe palette is active.
+// Call it after loading data and whenever the palette setting changes;
+// views created afterwards use the new colors (and names).
+func ConfigureColors(allEmotions map[string]data.Emotion, colorblindSafe bool) {
+	scheme := colorScheme{
+		emotions:     allEmotions,
+		colorblind:   colorblindSafe,
+		familyColors: make(map[string]color.Color),
+	}
+	// Roots come back in dataset order, so the assignment is stable between runs
+	for i, root := range core.GetRootEmotions(allEmotions) {
+		scheme.familyColors[root.ID] = colorblindSafePalette[i%len(colorblindSafePalette)]
+	}
+	colorsMutex.Lock()
+	activeColors = scheme
+	colorsMutex.Unlock()
+	resetCardCache() // Cards were drawn from the previous dataset and palette
+	slog.Debug("Colors configured", "colorblindSafe", colorblindSafe)
+}
+
+// EmotionColor resolves the display color of an emotion.
+// In colorblind mode the color comes from the emotion's family in the safe
+// palette. Otherwise it is the emotion's own color, or the nearest ancestor's
+// color if the emotion has none (many tertiary emotions don't).
+func EmotionColor(emotion data.Emotion) color.Color {
+	colors := currentColors()
+	ancestry := core.GetAncestry(emotion.ID, colors.emotions)
+	if len(ancestry) == 0 {
+		ancestry = []data.Emotion{emotion} // Not in the configured dataset; use it as-is
+	}
+
+	if colors.colorblind {
+		if c, ok := colors.familyColors[ancestry[0].ID]; ok {
+			return c
+		}
+	}
+
+	// Walk from the emotion itself up towards the root
+	for i := len(ancestry) - 1; i >= 0; i-- {
+		if ancestry[i].Color == "" {
+			continue
+		}
+		c, err := parseHexColor(ancestry[i].Color)
+		if err != nil {
+			slog.Warn("Failed to parse emotion color", "color", ancestry[i].Color, "emotionID", ancestry[i].ID, "err", err)
+			continue
+		}
+		return c
+	}
+	return fallbackEmotionColor
+}
+
+// describeEmotion builds the accessible description for an emotion card,
+// e.g. "Emotion in Happy › Playful" or "Emotion family".
+func describeEmotion(emotion data.Emotion) string {
+	ancestry := core.GetAncestry(emotion.ID, currentColors().emotions)
+	if len(ancestry) <= 1 {
+		return i18n.T("a11y.family")
+	}
+	return i18n.T("a11y.emotionIn", AncestryPath(ancestry[:len(ancestry)-1]))
+}
+
+// AncestryPath joins the display names of an ancestry chain (see
+// core.GetAncestry) into a breadcrumb such as "Happy › Playful".
+func AncestryPath(ancestry []data.Emotion) string {
+	names := make([]string, len(ancestry))
+	for i, ancestor := range ancestry {
+		names[i] = DisplayName(ancestor)
+	}
+	return strings.Join(names, " › ")
+}
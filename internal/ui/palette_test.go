@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEmotionColor tests color inheritance and the colorblind-safe palette.
+func TestEmotionColor(t *testing.T) {
+	// --- Test Data Setup ---
+	emotionBad := data.Emotion{ID: "bad", Name: "Bad", Type: "primary", Color: "#4A5568"}
+	emotionHappy := data.Emotion{ID: "happy", Name: "Happy", Type: "primary", Color: "#F29727"}
+	emotionPlayful := data.Emotion{ID: "playful", Name: "Playful", Type: "secondary", Color: "#F9A826", ParentID: "happy"}
+	emotionAroused := data.Emotion{ID: "aroused", Name: "Aroused", Type: "tertiary", ParentID: "playful"} // No color of its own
+	emotionBroken := data.Emotion{ID: "broken", Name: "Broken", Type: "primary", Color: "not-a-color"}
+
+	allTestEmotions := map[string]data.Emotion{
+		"bad":     emotionBad,
+		"happy":   emotionHappy,
+		"playful": emotionPlayful,
+		"aroused": emotionAroused,
+		"broken":  emotionBroken,
+	}
+	defer ConfigureColors(nil, false) // Don't leak configuration into other tests
+
+	t.Run("Dataset colors with inheritance", func(t *testing.T) {
+		ConfigureColors(allTestEmotions, false)
+		playfulColor, _ := parseHexColor("#F9A826")
+		assert.Equal(t, playfulColor, EmotionColor(emotionPlayful))
+		assert.Equal(t, playfulColor, EmotionColor(emotionAroused), "Aroused should inherit Playful's color")
+		assert.Equal(t, fallbackEmotionColor, EmotionColor(emotionBroken))
+	})
+
+	t.Run("Colorblind palette by family", func(t *testing.T) {
+		ConfigureColors(allTestEmotions, true)
+		// Roots sorted by name: Bad, Broken, Happy
+		assert.Equal(t, colorblindSafePalette[0], EmotionColor(emotionBad))
+		assert.Equal(t, colorblindSafePalette[2], EmotionColor(emotionHappy))
+		assert.Equal(t, colorblindSafePalette[2], EmotionColor(emotionAroused), "Descendants share their family color")
+	})
+}
+
+// TestDescribeEmotion tests accessible descriptions for emotion cards.
+func TestDescribeEmotion(t *testing.T) {
+	emotionHappy := data.Emotion{ID: "happy", Name: "Happy", Type: "primary"}
+	emotionPlayful := data.Emotion{ID: "playful", Name: "Playful", Type: "secondary", ParentID: "happy"}
+	emotionAroused := data.Emotion{ID: "aroused", Name: "Aroused", Type: "tertiary", ParentID: "playful"}
+	ConfigureColors(map[string]data.Emotion{
+		"happy":   emotionHappy,
+		"playful": emotionPlayful,
+		"aroused": emotionAroused,
+	}, false)
+	defer ConfigureColors(nil, false)
+
+	assert.Equal(t, "Emotion family", describeEmotion(emotionHappy))
+	assert.Equal(t, "Emotion in Happy › Playful", describeEmotion(emotionAroused))
+}
@@ -0,0 +1,280 @@
+// internal/ui/route.go
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/core"
+)
+
+// --- Routes ---
+//
+// A route is a short, serializable address of a place in the app, so where
+// the user was can be saved on exit and restored on the next launch, and
+// places can be opened from the command line or another launch:
+//
+//	browse                 the top-level emotions
+//	browse/happy/playful   the sub-emotions of Playful, reached through Happy
+//	log, log/sad           a logging session, at the top level or under Sad
+//	search                 emotion search
+//	moodmeter              logging on the mood meter
+//	history?range=7d       the journal history, optionally only the last days (d) or weeks (w)
+//	emotion/aroused        an emotion wherever it lives: its sub-emotions, or its details if it has none
+//	compare/lonely/bored   emotions side by side (any number; none to pick them in the app)
+//	learn, learn/sad       the vocabulary quiz, on all words or those below some emotions
+//
+// Routes name screens, never widgets; opening one builds the navigation
+// stack leading to it, which the view layer renders like any other.
+
+// RouteKind is the first segment of a route.
+type RouteKind string
+
+const (
+	RouteBrowse    RouteKind = "browse"
+	RouteLog       RouteKind = "log"
+	RouteSearch    RouteKind = "search"
+	RouteMoodMeter RouteKind = "moodmeter"
+	RouteHistory   RouteKind = "history"
+	RouteEmotion   RouteKind = "emotion"
+	RouteCompare   RouteKind = "compare"
+	RouteLearn     RouteKind = "learn"
+)
+
+// Route is a parsed route. The zero value is not a valid route.
+type Route struct {
+	Kind RouteKind
+	Path []string // RouteBrowse, RouteLog: emotion IDs from the root down; RouteEmotion: the emotion's ID; RouteCompare: the compared IDs; RouteLearn: the quizzed families
+	Days int      // RouteHistory: only entries from the last Days days (0: all)
+}
+
+// ParseRoute parses a route such as "browse/happy/playful" or
+// "history?range=7d". Leading and trailing slashes are ignored.
+func ParseRoute(s string) (Route, error) {
+	raw, query, _ := strings.Cut(strings.TrimSpace(s), "?")
+	raw = strings.Trim(raw, "/")
+	if raw == "" {
+		return Route{}, fmt.Errorf("empty route")
+	}
+	segments := strings.Split(raw, "/")
+	route := Route{Kind: RouteKind(segments[0])}
+	if len(segments) > 1 {
+		route.Path = segments[1:]
+	}
+	for _, id := range route.Path {
+		if id == "" {
+			return Route{}, fmt.Errorf("route '%s' has an empty emotion ID", s)
+		}
+	}
+
+	switch route.Kind {
+	case RouteBrowse, RouteLog, RouteCompare, RouteLearn:
+	case RouteSearch, RouteMoodMeter, RouteHistory:
+		if len(route.Path) > 0 {
+			return Route{}, fmt.Errorf("route '%s' takes no emotion IDs", s)
+		}
+	case RouteEmotion:
+		if len(route.Path) != 1 {
+			return Route{}, fmt.Errorf("route '%s' needs exactly one emotion ID", s)
+		}
+	default:
+		return Route{}, fmt.Errorf("unknown route '%s'", s)
+	}
+
+	if query == "" {
+		return route, nil
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return Route{}, fmt.Errorf("route '%s': %w", s, err)
+	}
+	for key := range values {
+		if key != "range" || route.Kind != RouteHistory {
+			return Route{}, fmt.Errorf("route '%s' does not take '%s'", s, key)
+		}
+	}
+	if route.Days, err = parseRange(values.Get("range")); err != nil {
+		return Route{}, fmt.Errorf("route '%s': %w", s, err)
+	}
+	return route, nil
+}
+
+// parseRange parses a history range such as "7d" or "2w" into days.
+// "all" (or nothing) means no limit.
+func parseRange(s string) (int, error) {
+	if s == "" || s == "all" {
+		return 0, nil
+	}
+	unit := 1
+	switch {
+	case strings.HasSuffix(s, "d"):
+	case strings.HasSuffix(s, "w"):
+		unit = 7
+	default:
+		return 0, fmt.Errorf("range '%s' must end in d (days) or w (weeks)", s)
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid range '%s'", s)
+	}
+	return n * unit, nil
+}
+
+// String formats the route so that ParseRoute reads it back.
+func (r Route) String() string {
+	s := strings.Join(append([]string{string(r.Kind)}, r.Path...), "/")
+	if r.Kind == RouteHistory && r.Days > 0 {
+		s += fmt.Sprintf("?range=%dd", r.Days)
+	}
+	return s
+}
+
+// Restorable returns the route to reopen on the next launch: logging routes
+// become the browsing place they were at (log/sad is browse/sad, the mood
+// meter the top-level emotions), so a relaunch never drops the user into a
+// logging session they quit in the middle of. Other routes are unchanged.
+func (r Route) Restorable() Route {
+	switch r.Kind {
+	case RouteLog:
+		return Route{Kind: RouteBrowse, Path: r.Path}
+	case RouteMoodMeter:
+		return Route{Kind: RouteBrowse}
+	}
+	return r
+}
+
+// Route returns the route of the screen shown, or of the closest screen a
+// route can name (e.g. a search opened while logging is left out).
+func (c *AppController) Route() Route {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stack := *c.activeLocked()
+	top := stack[len(stack)-1]
+	switch {
+	case top.Kind == ScreenMoodMeter:
+		return Route{Kind: RouteMoodMeter}
+	case top.Kind == ScreenHistory:
+		return Route{Kind: RouteHistory, Days: top.Days}
+	case top.Kind == ScreenCompare:
+		return Route{Kind: RouteCompare, Path: append([]string(nil), top.Path...)}
+	case top.Kind == ScreenLearn:
+		return Route{Kind: RouteLearn, Path: append([]string(nil), top.Path...)}
+	case top.Kind == ScreenSearch && c.mode == ModeBrowsing:
+		return Route{Kind: RouteSearch}
+	}
+
+	route := Route{Kind: RouteBrowse}
+	if c.mode == ModeLogging {
+		route.Kind = RouteLog
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		if stack[i].Kind == ScreenEmotion {
+			route.Path = append([]string(nil), stack[i].Path...)
+			break
+		}
+	}
+	return route
+}
+
+// Open goes to a route, replacing the navigation stack of its mode with the
+// screens leading to it; browsing routes end a logging session. Emotion IDs
+// are followed through the dataset's aliases. A route the dataset can't show
+// (unknown emotions, or a path that doesn't lead anywhere) is an error and
+// changes nothing.
+func (c *AppController) Open(route Route) error {
+	c.mu.Lock()
+	mode := ModeBrowsing
+	var screens []Screen
+	var err error
+	var details []string // Path of an emotion to show once the stack is open
+	switch route.Kind {
+	case RouteBrowse:
+		screens, err = c.pathScreensLocked(route.Path)
+	case RouteLog:
+		mode = ModeLogging
+		screens, err = c.pathScreensLocked(route.Path)
+	case RouteSearch:
+		screens = []Screen{{Kind: ScreenRoot}, {Kind: ScreenSearch}}
+	case RouteMoodMeter:
+		mode = ModeLogging
+		screens = []Screen{{Kind: ScreenRoot}, {Kind: ScreenMoodMeter}}
+	case RouteHistory:
+		screens = []Screen{{Kind: ScreenRoot}, {Kind: ScreenHistory, Days: route.Days}}
+	case RouteEmotion:
+		emotion, ok := c.resolver.Lookup(route.Path[0])
+		if !ok {
+			err = fmt.Errorf("unknown emotion ID '%s'", route.Path[0])
+			break
+		}
+		path := core.PathIDs(core.GetAncestry(emotion.ID, c.resolver.Emotions()))
+		if len(core.GetChildrenOf(emotion.ID, c.resolver.Emotions())) == 0 {
+			details = path // Open its parent and show the emotion itself
+			path = path[:len(path)-1]
+		}
+		screens, err = c.pathScreensLocked(path)
+	case RouteCompare, RouteLearn:
+		for _, id := range route.Path {
+			if _, ok := c.resolver.Resolve(id); !ok {
+				err = fmt.Errorf("unknown emotion ID '%s'", id)
+				break
+			}
+		}
+		kind := ScreenCompare
+		if route.Kind == RouteLearn {
+			kind = ScreenLearn
+		}
+		screens = []Screen{{Kind: ScreenRoot}, {Kind: kind, Path: c.resolveIDsLocked(route.Path)}}
+	default:
+		err = fmt.Errorf("unknown route '%s'", route)
+	}
+	if err != nil {
+		c.mu.Unlock()
+		return fmt.Errorf("opening route '%s': %w", route, err)
+	}
+
+	if mode == ModeLogging {
+		c.mode = ModeLogging
+		c.logging = screens
+	} else {
+		c.stopLoggingLocked()
+		c.browsing = screens
+	}
+	slog.Debug("Opened route", "route", route.String(), "depth", len(screens), "mode", c.mode)
+	emotions := c.resolver.Emotions()
+	c.mu.Unlock()
+	c.emit()
+
+	if details != nil && c.hooks.ShowDetails != nil {
+		c.hooks.ShowDetails(emotions[details[len(details)-1]], details)
+	}
+	return nil
+}
+
+// pathScreensLocked returns the stack of screens leading down path: the
+// top-level emotions, then one emotion screen per ID.
+func (c *AppController) pathScreensLocked(path []string) ([]Screen, error) {
+	ids := make([]string, len(path))
+	for i, id := range path {
+		resolved, ok := c.resolver.Resolve(id)
+		if !ok {
+			return nil, fmt.Errorf("unknown emotion ID '%s'", id)
+		}
+		ids[i] = resolved
+	}
+	chain := core.ResolvePath(ids, c.resolver.Emotions())
+	if len(path) > 0 && chain == nil {
+		return nil, fmt.Errorf("'%s' is not a path from a top-level emotion", strings.Join(path, "/"))
+	}
+
+	screens := []Screen{{Kind: ScreenRoot}}
+	for i, emotion := range chain {
+		if len(core.GetChildrenOf(emotion.ID, c.resolver.Emotions())) == 0 {
+			return nil, fmt.Errorf("'%s' has no sub-emotions", emotion.ID)
+		}
+		screens = append(screens, Screen{Kind: ScreenEmotion, Path: ids[: i+1 : i+1]})
+	}
+	return screens, nil
+}
@@ -0,0 +1,210 @@
+// internal/ui/route_test.go
+package ui
+
+import (
+	"testing"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseRoute tests reading routes and writing them back.
+func TestParseRoute(t *testing.T) {
+	testCases := []struct {
+		input     string
+		want      Route
+		canonical string // String() of the parsed route; "" if it equals input
+		expectErr bool
+	}{
+		{input: "browse", want: Route{Kind: RouteBrowse}},
+		{input: "browse/happy/playful", want: Route{Kind: RouteBrowse, Path: []string{"happy", "playful"}}},
+		{input: "/log/sad/", want: Route{Kind: RouteLog, Path: []string{"sad"}}, canonical: "log/sad"},
+		{input: "search", want: Route{Kind: RouteSearch}},
+		{input: "moodmeter", want: Route{Kind: RouteMoodMeter}},
+		{input: "history", want: Route{Kind: RouteHistory}},
+		{input: "history?range=7d", want: Route{Kind: RouteHistory, Days: 7}},
+		{input: "history?range=2w", want: Route{Kind: RouteHistory, Days: 14}, canonical: "history?range=14d"},
+		{input: "history?range=all", want: Route{Kind: RouteHistory}, canonical: "history"},
+		{input: "emotion/aroused", want: Route{Kind: RouteEmotion, Path: []string{"aroused"}}},
+		{input: "compare", want: Route{Kind: RouteCompare}},
+		{input: "compare/lonely/stuck", want: Route{Kind: RouteCompare, Path: []string{"lonely", "stuck"}}},
+		{input: "learn", want: Route{Kind: RouteLearn}},
+		{input: "learn/sad", want: Route{Kind: RouteLearn, Path: []string{"sad"}}},
+
+		{input: "", expectErr: true},
+		{input: "dance", expectErr: true},
+		{input: "browse//playful", expectErr: true},
+		{input: "search/happy", expectErr: true},
+		{input: "emotion", expectErr: true},
+		{input: "emotion/happy/playful", expectErr: true},
+		{input: "history?range=7", expectErr: true},
+		{input: "history?range=-1d", expectErr: true},
+		{input: "history?since=7d", expectErr: true},
+		{input: "browse?range=7d", expectErr: true},
+		{input: "compare//stuck", expectErr: true},
+		{input: "learn?range=7d", expectErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			route, err := ParseRoute(tc.input)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.want, route)
+				canonical := tc.canonical
+				if canonical == "" {
+					canonical = tc.input
+				}
+				assert.Equal(t, canonical, route.String())
+			}
+		})
+	}
+}
+
+// TestControllerRoute tests the route reported for the screen shown.
+func TestControllerRoute(t *testing.T) {
+	testCases := []struct {
+		name    string
+		actions func(c *AppController)
+		want    string
+	}{
+		{"Top level", func(c *AppController) {}, "browse"},
+		{"Along the path taken", func(c *AppController) {
+			selectID(c, "sad")
+			selectID(c, "frustrated")
+		}, "browse/sad/frustrated"},
+		{"Search", func(c *AppController) {
+			selectID(c, "happy")
+			c.ShowSearch()
+		}, "search"},
+		{"Logging leaves out a search", func(c *AppController) {
+			c.StartLogging()
+			selectID(c, "happy")
+			c.ShowSearch()
+		}, "log/happy"},
+		{"Mood meter", func(c *AppController) { c.ShowMoodMeter() }, "moodmeter"},
+		{"History", func(c *AppController) { c.ShowHistory() }, "history"},
+		{"Compare", func(c *AppController) { c.ShowCompare("lonely", "stuck") }, "compare/lonely/stuck"},
+		{"Learn", func(c *AppController) { c.ShowLearn("sad") }, "learn/sad"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, _ := newTestController()
+			tc.actions(c)
+			assert.Equal(t, tc.want, c.Route().String())
+		})
+	}
+}
+
+// TestControllerOpen tests opening routes, including one saved before the
+// dataset renamed an emotion.
+func TestControllerOpen(t *testing.T) {
+	emotions := stateEmotions()
+	emotions["silly"] = data.Emotion{ID: "silly", Name: "Silly", ParentID: "happy"}
+	emotions["cheeky"] = data.Emotion{ID: "cheeky", Name: "Cheeky", ParentID: "silly"}
+	delete(emotions, "playful")
+	resolver := core.NewIDResolver(data.EmotionData{
+		Emotions: emotions,
+		Aliases:  []data.IDAlias{{From: "playful", To: "silly"}},
+	})
+
+	testCases := []struct {
+		route     string
+		wantMode  AppMode
+		wantStack []Screen
+		details   []string // Path passed to ShowDetails
+		expectErr bool
+	}{
+		{route: "browse/happy/playful", wantMode: ModeBrowsing, wantStack: []Screen{{Kind: ScreenRoot}, emotionScreen("happy"), emotionScreen("happy", "silly")}},
+		{route: "log/sad/frustrated", wantMode: ModeLogging, wantStack: []Screen{{Kind: ScreenRoot}, emotionScreen("sad"), emotionScreen("sad", "frustrated")}},
+		{route: "moodmeter", wantMode: ModeLogging, wantStack: []Screen{{Kind: ScreenRoot}, {Kind: ScreenMoodMeter}}},
+		{route: "search", wantMode: ModeBrowsing, wantStack: []Screen{{Kind: ScreenRoot}, {Kind: ScreenSearch}}},
+		{route: "history?range=7d", wantMode: ModeBrowsing, wantStack: []Screen{{Kind: ScreenRoot}, {Kind: ScreenHistory, Days: 7}}},
+		{route: "emotion/frustrated", wantMode: ModeBrowsing, wantStack: []Screen{{Kind: ScreenRoot}, emotionScreen("angry"), emotionScreen("angry", "frustrated")}},
+		{route: "compare/playful/lonely/silly", wantMode: ModeBrowsing, wantStack: []Screen{{Kind: ScreenRoot}, {Kind: ScreenCompare, Path: []string{"silly", "lonely"}}}},
+		{route: "learn/playful", wantMode: ModeBrowsing, wantStack: []Screen{{Kind: ScreenRoot}, {Kind: ScreenLearn, Path: []string{"silly"}}}},
+		{route: "emotion/cheeky", wantMode: ModeBrowsing, wantStack: []Screen{{Kind: ScreenRoot}, emotionScreen("happy"), emotionScreen("happy", "silly")}, details: []string{"happy", "silly", "cheeky"}},
+
+		{route: "browse/gone", expectErr: true},
+		{route: "browse/playful", expectErr: true},       // Not a top-level emotion
+		{route: "browse/happy/content", expectErr: true}, // No sub-emotions to list
+		{route: "emotion/gone", expectErr: true},
+		{route: "compare/lonely/gone", expectErr: true},
+		{route: "learn/gone", expectErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.route, func(t *testing.T) {
+			c, rec := newTestController()
+			c.SetResolver(resolver)
+			c.StartLogging() // Browsing routes end the session
+			route, err := ParseRoute(tc.route)
+			if !assert.NoError(t, err) {
+				return
+			}
+			err = c.Open(route)
+			if tc.expectErr {
+				assert.Error(t, err)
+				assert.Equal(t, ModeLogging, c.State().Mode, "failed routes change nothing")
+				assert.Len(t, rec.states, 1)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.wantMode, c.State().Mode)
+				assert.Equal(t, tc.wantStack, c.Stack(tc.wantMode))
+				assert.Equal(t, c.State(), rec.states[len(rec.states)-1])
+				if tc.details == nil {
+					assert.Empty(t, rec.details)
+				} else {
+					assert.Equal(t, [][]string{tc.details}, rec.details)
+				}
+			}
+		})
+	}
+
+	t.Run("Round trip", func(t *testing.T) {
+		c, _ := newTestController()
+		selectID(c, "sad")
+		selectID(c, "frustrated")
+		route := c.Route()
+
+		restored, _ := newTestController()
+		assert.NoError(t, restored.Open(route))
+		assert.Equal(t, c.Stack(ModeBrowsing), restored.Stack(ModeBrowsing))
+	})
+}
+
+// TestRestorableRoute tests that sessions quit while logging are restored
+// as browsing.
+func TestRestorableRoute(t *testing.T) {
+	testCases := []struct {
+		route string
+		want  string
+	}{
+		{"log", "browse"},
+		{"log/sad/frustrated", "browse/sad/frustrated"},
+		{"moodmeter", "browse"},
+		{"browse/happy", "browse/happy"},
+		{"history?range=7d", "history?range=7d"},
+		{"learn/sad", "learn/sad"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.route, func(t *testing.T) {
+			route, err := ParseRoute(tc.route)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.want, route.Restorable().String())
+			}
+		})
+	}
+
+	t.Run("Quit mid-log", func(t *testing.T) {
+		c, _ := newTestController()
+		c.StartLogging()
+		selectID(c, "sad")
+		restored, _ := newTestController()
+		assert.NoError(t, restored.Open(c.Route().Restorable()))
+		assert.Equal(t, AppState{Mode: ModeBrowsing, Screen: emotionScreen("sad"), Depth: 2}, restored.State())
+	})
+}
@@ -0,0 +1,372 @@
+// internal/ui/shell.go
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"slices"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/itsforsxm123/emotion-explorer/internal/i18n"
+	"github.com/itsforsxm123/emotion-explorer/internal/journal"
+	"github.com/itsforsxm123/emotion-explorer/internal/learn"
+)
+
+// --- Main Window ---
+//
+// Shell fills the main window around an AppController: a back button above
+// the screen on top of the active stack, the window title, and the dialogs
+// the controller asks for (details, intensity ladders, save results). It
+// holds everything the GUI flows need, so they can be driven with Fyne's
+// test driver; the application adds the tray, files and settings around it.
+
+// ShellOptions configures a Shell. Nil callbacks are skipped.
+type ShellOptions struct {
+	AppName string // Window title; not translated
+
+	// Sort orders the emotion lists (e.g. in the user's sort mode); nil
+	// keeps dataset order.
+	Sort func(emotions []data.Emotion) []data.Emotion
+	// OnSaved is called after an entry was saved, e.g. to recount usage.
+	OnSaved func()
+	// OnCorruptJournal is called when an entry couldn't be saved because the
+	// journal is damaged (the file is left untouched), e.g. to offer a repair.
+	OnCorruptJournal func()
+}
+
+// Shell is the main window's content. Create it with NewShell.
+type Shell struct {
+	Controller *AppController // Modes, navigation stacks and actions
+
+	window  fyne.Window
+	options ShellOptions
+	back    *widget.Button  // Global back button; enabled while Back leads somewhere
+	content *fyne.Container // Holds the rendered screen (center of the border)
+
+	renderMu sync.Mutex // One render at a time; reloads render off the UI goroutine
+
+	mu       sync.Mutex
+	resolver *core.IDResolver // Dataset the screens are rendered from
+	learning *learnSession    // Quiz on screen, kept across re-renders
+}
+
+// learnSession is a quiz session and what it was started on.
+type learnSession struct {
+	resolver *core.IDResolver
+	scope    []string
+	session  *learn.Session
+}
+
+// NewShell lays out window around a new AppController on the dataset behind
+// resolver. Nothing is rendered until the controller reports a state, e.g.
+// from Controller.Refresh or Controller.Open.
+func NewShell(window fyne.Window, resolver *core.IDResolver, options ShellOptions) *Shell {
+	s := &Shell{window: window, options: options, resolver: resolver}
+	s.Controller = NewAppController(resolver, AppHooks{
+		OnChange:      s.render,
+		ShowDetails:   s.showDetails,
+		ConfirmLadder: s.confirmLadder,
+		Save:          s.Save,
+	})
+
+	s.back = widget.NewButtonWithIcon("", theme.NavigateBackIcon(), func() { // Use icon
+		slog.Debug("Back button clicked")
+		s.Controller.Back()
+	})
+	s.back.Disable() // Start disabled
+
+	// This container will hold the dynamic content (emotion lists)
+	s.content = container.NewMax() // Use Max layout to fill available space
+	window.SetContent(container.NewBorder(
+		container.NewHBox(s.back, layout.NewSpacer()), // Top: Back button aligned left
+		nil,       // Bottom
+		nil,       // Left
+		nil,       // Right
+		s.content, // Center: Dynamic content goes here
+	))
+	window.SetTitle(s.title(ModeBrowsing))
+	slog.Debug("Main layout setup complete")
+	return s
+}
+
+// SetResolver switches the shell and its controller to a reloaded or
+// different dataset. Call Controller.Refresh afterwards to re-render.
+func (s *Shell) SetResolver(resolver *core.IDResolver) {
+	s.mu.Lock()
+	s.resolver = resolver
+	s.mu.Unlock()
+	s.Controller.SetResolver(resolver)
+}
+
+// Resolver returns the dataset the screens are rendered from.
+func (s *Shell) Resolver() *core.IDResolver {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.resolver
+}
+
+// Content returns the view shown for the screen on top.
+func (s *Shell) Content() fyne.CanvasObject {
+	if len(s.content.Objects) == 0 {
+		return nil
+	}
+	return s.content.Objects[0]
+}
+
+// title returns the translated window title for a mode.
+func (s *Shell) title(mode AppMode) string {
+	if mode == ModeLogging {
+		return i18n.T("window.logging", s.options.AppName)
+	}
+	return s.options.AppName
+}
+
+// --- Rendering ---
+
+// render shows the screen on top of the active stack; it is the
+// controller's OnChange hook. Screens are rendered from the current data on
+// every change, so only the visible view exists. Changes can come from
+// several goroutines (e.g. a dataset reload while the user taps), so renders
+// take turns and each shows the controller's latest state rather than the
+// one it was called with, which may already be stale.
+func (s *Shell) render(AppState) {
+	s.renderMu.Lock()
+	defer s.renderMu.Unlock()
+	state := s.Controller.State()
+	if state.Screen.Kind != ScreenLearn {
+		s.mu.Lock()
+		s.learning = nil // Leaving the quiz ends its session
+		s.mu.Unlock()
+	}
+	view := s.renderScreen(state.Screen, state.Mode)
+	if view == nil {
+		slog.Error("Screen on top cannot be shown", "kind", state.Screen.Kind)
+		view = widget.NewLabel(i18n.T("view.noView"))
+	}
+
+	// Update the main content area
+	s.content.Objects = []fyne.CanvasObject{view} // Replace objects in Max container
+	s.content.Refresh()
+	FocusInitial(s.window.Canvas(), view) // Keyboard users start on the first card (or search box)
+
+	s.window.SetTitle(s.title(state.Mode))
+	if state.CanGoBack() {
+		s.back.Enable()
+	} else {
+		s.back.Disable()
+	}
+	slog.Debug("Main content area updated", "depth", state.Depth, "mode", state.Mode)
+}
+
+// renderScreen builds the view for a screen of a stack in the given mode.
+// Returns nil if what the screen showed no longer exists.
+func (s *Shell) renderScreen(screen Screen, mode AppMode) fyne.CanvasObject {
+	resolver := s.Resolver()
+	emotions := resolver.Emotions()
+	switch screen.Kind {
+	case ScreenRoot:
+		titleKey := "view.primary.title"
+		if mode == ModeLogging {
+			titleKey = "view.log.title"
+		}
+		return CreateEmotionListView(i18n.T(titleKey), nil, s.sort(core.GetRootEmotions(emotions)), s.Controller.Select)
+	case ScreenEmotion:
+		titleKey := "view.explore.title"
+		if mode == ModeLogging {
+			titleKey = "view.logPath.title"
+		}
+		return s.renderEmotionScreen(resolver, screen.Path, titleKey)
+	case ScreenSearch:
+		return CreateSearchView(emotions, s.Controller.Select)
+	case ScreenMoodMeter:
+		return CreateMoodMeterView(emotions, func(emotion data.Emotion, valence, arousal float64) {
+			s.Controller.LogMoodMeter(emotion, valence, arousal)
+		})
+	case ScreenHistory:
+		entries, err := journal.GetJournalEntries()
+		if err != nil {
+			slog.Error("Failed to reload journal entries for history", "err", err)
+			return widget.NewLabel(fmt.Sprintf("%s: %v", i18n.T("error.loadJournal"), err))
+		}
+		return CreateHistoryView(entries, screen.Days, resolver)
+	case ScreenCompare:
+		entries, err := journal.GetJournalEntries()
+		if err != nil {
+			slog.Error("Failed to reload journal entries for comparison", "err", err)
+			return widget.NewLabel(fmt.Sprintf("%s: %v", i18n.T("error.loadJournal"), err))
+		}
+		return CreateCompareView(screen.Path, entries, resolver, s.Controller.SetCompared)
+	case ScreenLearn:
+		session, err := s.learnSession(resolver, screen.Path)
+		if err != nil {
+			slog.Error("Failed to load learning progress", "err", err)
+			return widget.NewLabel(fmt.Sprintf("%s: %v", i18n.T("error.loadLearning"), err))
+		}
+		return CreateLearnView(session, emotions, time.Now, s.saveLearning)
+	}
+	return nil
+}
+
+// learnSession returns the quiz session on the words below scope, starting
+// one from the saved progress unless the screen is re-rendered on the same
+// dataset (e.g. after the journal changed), so the quiz goes on where it was.
+func (s *Shell) learnSession(resolver *core.IDResolver, scope []string) (*learn.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.learning != nil && s.learning.resolver == resolver && slices.Equal(s.learning.scope, scope) {
+		return s.learning.session, nil
+	}
+	progress, err := learn.Load()
+	if err != nil {
+		return nil, err
+	}
+	emotions := resolver.Emotions()
+	now := time.Now()
+	session := learn.NewSession(emotions, learn.Deck(emotions, scope), progress.Resolved(resolver), now, rand.New(rand.NewSource(now.UnixNano())))
+	s.learning = &learnSession{resolver: resolver, scope: scope, session: session}
+	slog.Debug("Started quiz session", "families", len(scope), "questions", session.Remaining())
+	return session, nil
+}
+
+// saveLearning saves the quiz progress after an answer.
+func (s *Shell) saveLearning(progress learn.Progress) {
+	if err := learn.Save(progress); err != nil {
+		slog.Error("Failed to save learning progress", "err", err)
+		dialog.ShowError(fmt.Errorf("%s: %w", i18n.T("error.saveLearning"), err), s.window)
+	}
+}
+
+// renderEmotionScreen shows the children of the emotion at the end of path
+// (IDs from the root down). The emotion is looked up by ID (following
+// aliases), so the screen survives dataset reloads as long as the emotion and
+// its children still exist. The title shows the path the user took, which
+// matters for emotions with several parents; a path the dataset no longer
+// has falls back to the primary ancestry.
+func (s *Shell) renderEmotionScreen(resolver *core.IDResolver, path []string, titleKey string) fyne.CanvasObject {
+	emotions := resolver.Emotions()
+	if len(path) == 0 {
+		return nil // No emotion to show
+	}
+	emotion, ok := resolver.Lookup(path[len(path)-1])
+	if !ok {
+		return nil
+	}
+	children := core.GetChildrenOf(emotion.ID, emotions)
+	if len(children) == 0 {
+		return nil
+	}
+	// The whole path, since hierarchies can be any number of levels deep
+	title := i18n.T(titleKey, AncestryPath(core.AncestryAlong(emotion.ID, path, emotions)))
+	view := CreateEmotionListView(title, &emotion, s.sort(children), s.Controller.Select)
+	if ladder := browsableLadder(emotion.ID, path, emotions); ladder != nil {
+		// Slide to a milder or stronger sibling without going back up
+		view = container.NewBorder(nil, container.NewPadded(CreateIntensityLadder(ladder, emotion.ID, func(picked data.Emotion) {
+			s.Controller.StepLadder(picked.ID)
+		})), nil, nil, view)
+	}
+	return view
+}
+
+// sort orders a list of emotions with the configured sort, if any.
+func (s *Shell) sort(emotions []data.Emotion) []data.Emotion {
+	if s.options.Sort == nil {
+		return emotions
+	}
+	return s.options.Sort(emotions)
+}
+
+// browsableLadder returns the intensity ladder of an emotion shown as a
+// list view, keeping only the words that have sub-emotions of their own
+// (each rung is a view). Returns nil if fewer than two such words remain.
+func browsableLadder(emotionID string, path []string, allEmotions map[string]data.Emotion) []data.Emotion {
+	ladder := core.IntensityLadder(emotionID, path, allEmotions)
+	browsable := make([]data.Emotion, 0, len(ladder))
+	for _, rung := range ladder {
+		if len(core.GetChildrenOf(rung.ID, allEmotions)) > 0 {
+			browsable = append(browsable, rung)
+		}
+	}
+	if len(browsable) < 2 {
+		return nil
+	}
+	return browsable
+}
+
+// --- Dialogs ---
+
+// showDetails shows the details dialog for an emotion reached by path. On
+// an intensity ladder the user can slide to milder and stronger words from
+// there. Compare opens the comparison with the word shown.
+func (s *Shell) showDetails(emotion data.Emotion, path []string) {
+	shown := emotion
+	message := widget.NewLabel(i18n.T("details.selected", DisplayName(emotion)))
+	items := []fyne.CanvasObject{message}
+	if ladder := core.IntensityLadder(emotion.ID, path, s.Resolver().Emotions()); ladder != nil {
+		items = append(items, widget.NewSeparator(), CreateIntensityLadder(ladder, emotion.ID, func(picked data.Emotion) {
+			shown = picked
+			message.SetText(i18n.T("details.selected", DisplayName(picked)))
+		}))
+	}
+	var details dialog.Dialog
+	items = append(items, widget.NewButton(i18n.T("details.compare"), func() {
+		details.Hide()
+		s.Controller.ShowCompare(shown.ID)
+	}))
+	details = dialog.NewCustom(i18n.T("details.title"), i18n.T("details.close"), container.NewVBox(items...), s.window)
+	details.Show()
+}
+
+// confirmLadder asks before logging an emotion on an intensity ladder,
+// offering the milder and stronger words. Confirming logs the word the user
+// settled on and returns to browsing; cancelling stays in logging mode.
+func (s *Shell) confirmLadder(emotion data.Emotion, path []string, ladder []data.Emotion) {
+	chosen := emotion
+	content := container.NewVBox(
+		widget.NewLabel(i18n.T("ladder.logHint")),
+		CreateIntensityLadder(ladder, emotion.ID, func(picked data.Emotion) { chosen = picked }),
+	)
+	dialog.ShowCustomConfirm(i18n.T("ladder.logTitle"), i18n.T("ladder.log"), i18n.T("ladder.cancel"), content, func(confirmed bool) {
+		if !confirmed {
+			slog.Debug("Logging cancelled on the intensity ladder", "emotionID", emotion.ID)
+			return
+		}
+		slog.Debug("Intensity ladder pick", "emotionID", chosen.ID, "startedAt", emotion.ID)
+		s.Controller.Log(chosen, core.LadderPath(path, chosen.ID))
+	}, s.window)
+}
+
+// Save saves an entry for emotion to the journal and tells the user how it
+// went; it is the controller's Save hook, and also logs entries that don't
+// come from the window (e.g. from another launch).
+func (s *Shell) Save(entry journal.LogEntry, emotion data.Emotion) error {
+	err := journal.SaveLogEntry(entry)
+	if errors.Is(err, journal.ErrCorruptJournal) {
+		// The damaged file was left untouched; offer to salvage it
+		slog.Error("Journal is corrupted; entry not saved", "err", err)
+		if s.options.OnCorruptJournal != nil {
+			s.options.OnCorruptJournal()
+		}
+	} else if err != nil {
+		slog.Error("Failed to save journal entry", "err", err)
+		dialog.ShowError(fmt.Errorf("%s: %w", i18n.T("error.saveJournal"), err), s.window)
+	} else {
+		slog.Debug("Journal entry saved", "emotionID", emotion.ID)
+		if s.options.OnSaved != nil {
+			s.options.OnSaved()
+		}
+		dialog.ShowInformation(i18n.T("logged.title"), i18n.T("logged.message", DisplayName(emotion)), s.window)
+	}
+	return err
+}
@@ -0,0 +1,327 @@
+// internal/ui/shell_test.go
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/itsforsxm123/emotion-explorer/internal/i18n"
+	"github.com/itsforsxm123/emotion-explorer/internal/journal"
+	"github.com/itsforsxm123/emotion-explorer/internal/learn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Test Harness ---
+
+// shellHarness drives a Shell in a test window, on stateEmotions and a
+// journal in a temp directory.
+type shellHarness struct {
+	t       *testing.T
+	window  fyne.Window
+	shell   *Shell
+	journal string // Journal file path
+	saved   int    // OnSaved calls
+	corrupt int    // OnCorruptJournal calls
+}
+
+// newShellHarness opens a shell at the top-level emotions.
+func newShellHarness(t *testing.T) *shellHarness {
+	t.Helper()
+	app := test.NewApp()
+	t.Cleanup(app.Quit)
+
+	previousLocale := i18n.Locale()
+	i18n.SetLocale("en")
+	t.Cleanup(func() { i18n.SetLocale(previousLocale) })
+
+	previousJournal := journal.FilePath()
+	h := &shellHarness{t: t, journal: filepath.Join(t.TempDir(), "journal.json")}
+	journal.SetFilePath(h.journal)
+	t.Cleanup(func() { journal.SetFilePath(previousJournal) })
+	previousProgress := learn.FilePath()
+	learn.SetFilePath(filepath.Join(t.TempDir(), "learning.json"))
+	t.Cleanup(func() { learn.SetFilePath(previousProgress) })
+
+	h.window = test.NewWindow(nil)
+	t.Cleanup(h.window.Close)
+	h.shell = NewShell(h.window, core.NewIDResolver(data.EmotionData{Emotions: stateEmotions()}), ShellOptions{
+		AppName:          "Emotion Explorer",
+		OnSaved:          func() { h.saved++ },
+		OnCorruptJournal: func() { h.corrupt++ },
+	})
+	h.window.Resize(fyne.NewSize(800, 600)) // Room for every card
+	h.shell.Controller.Refresh()
+	return h
+}
+
+// tapCard taps the card showing the emotion with the given display name.
+func (h *shellHarness) tapCard(name string) {
+	h.t.Helper()
+	var found *emotionCell
+	walkObjects(h.shell.Content(), func(obj fyne.CanvasObject) bool {
+		if cell, ok := obj.(*emotionCell); ok && obj.Visible() && cell.AccessibleName() == name {
+			found = cell
+		}
+		return found == nil
+	})
+	require.NotNil(h.t, found, "no card '%s' on screen", name)
+	test.Tap(found)
+}
+
+// tapButton taps the button with the given label on screen.
+func (h *shellHarness) tapButton(label string) {
+	h.t.Helper()
+	var found *widget.Button
+	walkObjects(h.shell.Content(), func(obj fyne.CanvasObject) bool {
+		if button, ok := obj.(*widget.Button); ok && button.Text == label {
+			found = button
+		}
+		return found == nil
+	})
+	require.NotNil(h.t, found, "no button '%s' on screen", label)
+	test.Tap(found)
+}
+
+// labels returns the texts of the labels on screen.
+func (h *shellHarness) labels() []string {
+	var texts []string
+	walkObjects(h.shell.Content(), func(obj fyne.CanvasObject) bool {
+		if label, ok := obj.(*widget.Label); ok {
+			texts = append(texts, label.Text)
+		}
+		return true
+	})
+	return texts
+}
+
+// tapDialogButton taps a button of the dialog on top of the window.
+func (h *shellHarness) tapDialogButton(label string) {
+	h.t.Helper()
+	overlay := h.window.Canvas().Overlays().Top()
+	require.NotNil(h.t, overlay, "no dialog open")
+	var found *widget.Button
+	walkObjects(overlay, func(obj fyne.CanvasObject) bool {
+		if button, ok := obj.(*widget.Button); ok && button.Text == label {
+			found = button
+		}
+		return found == nil
+	})
+	require.NotNil(h.t, found, "no button '%s' in the dialog", label)
+	test.Tap(found)
+}
+
+// dialogOpen reports whether a dialog is shown over the window.
+func (h *shellHarness) dialogOpen() bool {
+	return h.window.Canvas().Overlays().Top() != nil
+}
+
+// entries returns what the journal holds.
+func (h *shellHarness) entries() []journal.LogEntry {
+	h.t.Helper()
+	entries, err := journal.GetJournalEntries()
+	require.NoError(h.t, err)
+	return entries
+}
+
+// walkObjects visits obj and everything drawn inside it, depth first, until
+// visit returns false.
+func walkObjects(obj fyne.CanvasObject, visit func(obj fyne.CanvasObject) bool) bool {
+	if obj == nil {
+		return true
+	}
+	if !visit(obj) {
+		return false
+	}
+	var children []fyne.CanvasObject
+	switch o := obj.(type) {
+	case *fyne.Container:
+		children = o.Objects
+	case fyne.Widget:
+		children = test.WidgetRenderer(o).Objects()
+	}
+	for _, child := range children {
+		if !walkObjects(child, visit) {
+			return false
+		}
+	}
+	return true
+}
+
+// --- Flows ---
+
+// TestShellBrowsing tests browsing down and back with the back button.
+func TestShellBrowsing(t *testing.T) {
+	h := newShellHarness(t)
+	assert.Equal(t, "Emotion Explorer", h.window.Title())
+	assert.True(t, h.shell.back.Disabled(), "nothing to go back to")
+
+	h.tapCard("Happy")
+	h.tapCard("Playful")
+	assert.Equal(t, emotionScreen("happy", "playful"), h.shell.Controller.State().Screen)
+	assert.False(t, h.shell.back.Disabled())
+
+	h.tapCard("Cheeky") // A leaf shows its details
+	assert.True(t, h.dialogOpen())
+	h.tapDialogButton(i18n.T("details.close"))
+	assert.False(t, h.dialogOpen())
+	assert.Empty(t, h.entries(), "browsing never logs")
+
+	test.Tap(h.shell.back)
+	test.Tap(h.shell.back)
+	assert.Equal(t, Screen{Kind: ScreenRoot}, h.shell.Controller.State().Screen)
+	assert.True(t, h.shell.back.Disabled())
+}
+
+// TestShellCompare tests opening a comparison from the details dialog.
+func TestShellCompare(t *testing.T) {
+	h := newShellHarness(t)
+	h.tapCard("Happy")
+	h.tapCard("Playful")
+	h.tapCard("Cheeky")
+	h.tapDialogButton(i18n.T("details.compare"))
+	assert.False(t, h.dialogOpen())
+	assert.Equal(t, Screen{Kind: ScreenCompare, Path: []string{"cheeky"}}, h.shell.Controller.State().Screen)
+	assert.Equal(t, "compare/cheeky", h.shell.Controller.Route().String())
+
+	test.Tap(h.shell.back)
+	assert.Equal(t, emotionScreen("happy", "playful"), h.shell.Controller.State().Screen)
+}
+
+// TestShellLearn tests a quiz session on one family: answering, the
+// progress it saves, and the session surviving a re-render.
+func TestShellLearn(t *testing.T) {
+	h := newShellHarness(t)
+	h.shell.Controller.ShowLearn("sad") // Lonely, then Frustrated and Stuck (also Angry)
+	assert.Contains(t, h.labels(), i18n.T("learn.family", "Lonely"))
+	assert.Contains(t, h.labels(), i18n.T("learn.stats", 0, 0, 3, 3))
+
+	h.tapButton("Angry")
+	assert.Contains(t, h.labels(), i18n.T("learn.wrong", "Sad"))
+	assert.Contains(t, h.labels(), i18n.T("learn.path", "Sad › Lonely"))
+	progress, err := learn.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 0, progress.Review(learn.Card{Kind: learn.KindFamily, EmotionID: "lonely"}).Repetitions)
+	assert.False(t, progress.Review(learn.Card{Kind: learn.KindFamily, EmotionID: "lonely"}).IsNew(), "the answer was saved")
+
+	h.tapButton(i18n.T("learn.next"))
+	h.shell.Controller.Refresh() // E.g. the journal changed on disk
+	for _, name := range []string{"Frustrated", "Stuck"} {
+		assert.Contains(t, h.labels(), i18n.T("learn.family", name), "the session goes on after a re-render")
+		h.tapButton("Angry") // Their primary family
+		assert.Contains(t, h.labels(), i18n.T("learn.correct"))
+		h.tapButton(i18n.T("learn.next"))
+	}
+	assert.Contains(t, h.labels(), i18n.T("learn.family", "Lonely"), "missed words come back in the same session")
+	h.tapButton("Sad")
+	h.tapButton(i18n.T("learn.next"))
+	assert.Contains(t, h.labels(), i18n.T("learn.done", 3, 4))
+	assert.Contains(t, h.labels(), i18n.T("learn.stats", 0, 0, 0, 3))
+	assert.Equal(t, "learn/sad", h.shell.Controller.Route().String())
+}
+
+// TestShellLogging tests logging a leaf to the journal.
+func TestShellLogging(t *testing.T) {
+	h := newShellHarness(t)
+	h.tapCard("Happy")
+	h.shell.Controller.StartLogging()
+	assert.Equal(t, i18n.T("window.logging", "Emotion Explorer"), h.window.Title())
+	assert.True(t, h.shell.back.Disabled(), "logging starts at its root")
+
+	h.tapCard("Sad")
+	assert.False(t, h.shell.back.Disabled())
+	h.tapCard("Lonely")
+
+	entries := h.entries()
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "lonely", entries[0].EmotionID)
+	}
+	assert.Equal(t, 1, h.saved)
+	assert.True(t, h.dialogOpen(), "the user is told the entry was saved")
+	assert.Equal(t, "Emotion Explorer", h.window.Title())
+	assert.Equal(t, emotionScreen("happy"), h.shell.Controller.State().Screen, "back where browsing was")
+	assert.False(t, h.shell.back.Disabled())
+}
+
+// TestShellCancelLogging tests backing out of logging at its root.
+func TestShellCancelLogging(t *testing.T) {
+	h := newShellHarness(t)
+	h.shell.Controller.StartLogging()
+	h.tapCard("Angry")
+	test.Tap(h.shell.back)
+	assert.Equal(t, ModeLogging, h.shell.Controller.State().Mode)
+
+	h.shell.Controller.Back() // Escape or Backspace at the root
+	assert.Equal(t, ModeBrowsing, h.shell.Controller.State().Mode)
+	assert.Equal(t, "Emotion Explorer", h.window.Title())
+	assert.True(t, h.shell.back.Disabled())
+	assert.Empty(t, h.entries())
+}
+
+// TestShellLadderLogging tests confirming and cancelling the intensity
+// ladder shown before logging a ranked word.
+func TestShellLadderLogging(t *testing.T) {
+	h := newShellHarness(t)
+	h.shell.Controller.StartLogging()
+	h.tapCard("Angry")
+	h.tapCard("Annoyed")
+	h.tapDialogButton(i18n.T("ladder.cancel"))
+	assert.Equal(t, ModeLogging, h.shell.Controller.State().Mode, "cancelling keeps logging")
+	assert.Empty(t, h.entries())
+
+	h.tapCard("Furious")
+	h.tapDialogButton(i18n.T("ladder.log"))
+	entries := h.entries()
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "furious", entries[0].EmotionID)
+	}
+	assert.Equal(t, ModeBrowsing, h.shell.Controller.State().Mode)
+}
+
+// TestShellCorruptJournal tests that a damaged journal is left alone and
+// reported instead of being overwritten.
+func TestShellCorruptJournal(t *testing.T) {
+	h := newShellHarness(t)
+	garbage := []byte(`[{"emotionId": "happy"`)
+	require.NoError(t, os.WriteFile(h.journal, garbage, 0644))
+
+	h.shell.Controller.StartLogging()
+	h.tapCard("Sad")
+	h.tapCard("Lonely")
+	assert.Equal(t, 1, h.corrupt)
+	assert.Equal(t, 0, h.saved)
+	raw, err := os.ReadFile(h.journal)
+	require.NoError(t, err)
+	assert.Equal(t, garbage, raw)
+	assert.Equal(t, ModeBrowsing, h.shell.Controller.State().Mode)
+}
+
+// TestShellReloadWhileBrowsing reloads the dataset the way the file watcher
+// does, on another goroutine, while the user browses. Run with -race.
+func TestShellReloadWhileBrowsing(t *testing.T) {
+	h := newShellHarness(t)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 20 {
+			emotions := stateEmotions()
+			ConfigureColors(emotions, true)
+			h.shell.SetResolver(core.NewIDResolver(data.EmotionData{Emotions: emotions}))
+			h.shell.Controller.Refresh()
+		}
+	}()
+	for range 20 {
+		h.shell.Controller.Select(stateEmotions()["happy"])
+		EmotionColor(stateEmotions()["playful"])
+		describeEmotion(stateEmotions()["playful"])
+		h.shell.Controller.Back()
+	}
+	<-done
+	assert.Equal(t, Screen{Kind: ScreenRoot}, h.shell.Controller.State().Screen)
+}
@@ -1,3 +1,477 @@
+// internal/ui/state.go
 package ui
 
-// Application state management will go here
+import (
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/itsforsxm123/emotion-explorer/internal/journal"
+)
+
+// --- Application State ---
+//
+// AppController owns what the user is doing (browsing or logging), the
+// navigation stack of each mode and the actions that move between them,
+// without touching a single Fyne widget. Every transition is reported
+// through AppHooks.OnChange; the view layer renders the screen on top of the
+// active stack. Everything the controller can't do itself (dialogs, writing
+// the journal) goes through the other hooks, so the flows can be unit tested.
+
+// AppMode defines the current operational mode of the application.
+type AppMode int // Use int for enums, it's more idiomatic Go
+
+const (
+	ModeBrowsing AppMode = iota // Default mode: exploring emotions.
+	ModeLogging                 // Mode for selecting an emotion to log.
+)
+
+// String names the mode for logs.
+func (m AppMode) String() string {
+	if m == ModeLogging {
+		return "logging"
+	}
+	return "browsing"
+}
+
+// ScreenKind identifies what a screen on a navigation stack shows.
+type ScreenKind int
+
+const (
+	ScreenRoot      ScreenKind = iota // The top-level emotions
+	ScreenEmotion                     // The children of the emotion at the end of Screen.Path
+	ScreenSearch                      // Emotion search
+	ScreenMoodMeter                   // The valence/arousal plane (logging only)
+	ScreenHistory                     // The journal history (browsing only)
+	ScreenCompare                     // Emotions side by side (browsing only)
+	ScreenLearn                       // The vocabulary quiz (browsing only)
+)
+
+// Screen is one entry of a navigation stack: what to show, not how. The view
+// layer renders it from the current data, so screens survive dataset and
+// journal reloads, language changes, etc.
+type Screen struct {
+	Kind ScreenKind
+	Path []string // Emotion IDs from the root to the emotion shown, for ScreenEmotion; the compared emotions for ScreenCompare; the families quizzed for ScreenLearn (none: all)
+	Days int      // For ScreenHistory: only entries from the last Days days (0: all)
+}
+
+// AppState is a snapshot of the controller, as passed to AppHooks.OnChange.
+type AppState struct {
+	Mode   AppMode
+	Screen Screen // Top of the active stack; what the window shows
+	Depth  int    // Number of screens on the active stack
+}
+
+// CanGoBack reports whether Back leads to another screen of the same mode
+// (the back button is enabled). Back at the root of logging cancels logging.
+func (s AppState) CanGoBack() bool {
+	return s.Depth > 1
+}
+
+// AppHooks are the side effects the controller asks of the application.
+// Nil hooks are skipped.
+type AppHooks struct {
+	// OnChange is called after every transition with the new state.
+	OnChange func(state AppState)
+	// ShowDetails shows an emotion without sub-emotions selected while browsing.
+	ShowDetails func(emotion data.Emotion, path []string)
+	// ConfirmLadder lets the user adjust the intensity of an emotion selected
+	// while logging; the answer comes back through Log (or not at all if the
+	// user cancels). Without this hook the emotion is logged as selected.
+	ConfirmLadder func(emotion data.Emotion, path []string, ladder []data.Emotion)
+	// Save stores a journal entry and tells the user how it went.
+	Save func(entry journal.LogEntry, emotion data.Emotion) error
+}
+
+// AppController is the state machine behind the main window. It is safe for
+// concurrent use: file watchers and other launches act on it from their own
+// goroutines. Hooks are called without the lock held, so they may call back
+// into the controller.
+type AppController struct {
+	mu       sync.Mutex
+	hooks    AppHooks
+	resolver *core.IDResolver // Current dataset, with its ID aliases
+	mode     AppMode
+	browsing []Screen         // Stack for browsing screens; never empty
+	logging  []Screen         // Stack for logging screens; empty unless logging
+	now      func() time.Time // Timestamps new entries; replaced in tests
+}
+
+// NewAppController creates a controller browsing the top-level emotions of
+// the dataset behind resolver. Nothing is reported until the first action;
+// call Refresh to render the initial screen.
+func NewAppController(resolver *core.IDResolver, hooks AppHooks) *AppController {
+	return &AppController{
+		hooks:    hooks,
+		resolver: resolver,
+		mode:     ModeBrowsing,
+		browsing: []Screen{{Kind: ScreenRoot}},
+		now:      time.Now,
+	}
+}
+
+// State returns the current state.
+func (c *AppController) State() AppState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stateLocked()
+}
+
+// Stack returns a copy of the navigation stack of a mode, bottom first.
+func (c *AppController) Stack(mode AppMode) []Screen {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stack := c.browsing
+	if mode == ModeLogging {
+		stack = c.logging
+	}
+	return append([]Screen(nil), stack...)
+}
+
+// SetResolver switches to a reloaded or different dataset. Call Refresh
+// afterwards to drop screens whose emotions are gone and re-render.
+func (c *AppController) SetResolver(resolver *core.IDResolver) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.resolver = resolver
+}
+
+// Refresh reports the current state again so the view layer re-renders it,
+// e.g. after the dataset, the journal or the language changed. Screens whose
+// emotion no longer exists (or no longer has sub-emotions) are dropped with
+// everything above them, leaving the user at the closest surviving screen.
+func (c *AppController) Refresh() {
+	c.mu.Lock()
+	c.browsing = c.pruneLocked(c.browsing)
+	c.logging = c.pruneLocked(c.logging)
+	c.mu.Unlock()
+	c.emit()
+}
+
+// --- Navigation ---
+
+// Select handles an emotion selected in the current screen, in either mode:
+// emotions with sub-emotions open them, others are shown (browsing) or
+// logged (logging, after ConfirmLadder if the emotion is on an intensity
+// ladder).
+func (c *AppController) Select(emotion data.Emotion) {
+	c.mu.Lock()
+	mode := c.mode
+	emotions := c.resolver.Emotions()
+	path := c.selectionPathLocked(emotion)
+	children := core.GetChildrenOf(emotion.ID, emotions)
+	slog.Debug("Emotion selected", "emotionID", emotion.ID, "mode", mode, "children", len(children))
+	if len(children) > 0 {
+		c.pushLocked(Screen{Kind: ScreenEmotion, Path: path})
+		c.mu.Unlock()
+		c.emit()
+		return
+	}
+	c.mu.Unlock()
+
+	if mode == ModeBrowsing {
+		slog.Debug("Leaf emotion selected while browsing; showing details", "emotionID", emotion.ID)
+		if c.hooks.ShowDetails != nil {
+			c.hooks.ShowDetails(emotion, path)
+		}
+		return
+	}
+	if ladder := core.IntensityLadder(emotion.ID, path, emotions); ladder != nil && c.hooks.ConfirmLadder != nil {
+		c.hooks.ConfirmLadder(emotion, path, ladder) // Let the user adjust the intensity first
+		return
+	}
+	c.Log(emotion, path)
+}
+
+// Back returns to the previous screen of the active stack. Back at the root
+// of logging cancels logging; at the root of browsing it does nothing.
+func (c *AppController) Back() {
+	c.mu.Lock()
+	if c.mode == ModeLogging && len(c.logging) <= 1 {
+		c.mu.Unlock()
+		slog.Debug("Back at the root of logging; cancelling logging")
+		c.CancelLogging()
+		return
+	}
+	stack := c.activeLocked()
+	if len(*stack) <= 1 {
+		slog.Debug("Back requested at the root; nothing to pop", "depth", len(*stack))
+		c.mu.Unlock()
+		return
+	}
+	*stack = (*stack)[:len(*stack)-1]
+	slog.Debug("Popped screen", "depth", len(*stack), "mode", c.mode)
+	c.mu.Unlock()
+	c.emit()
+}
+
+// StepLadder swaps the emotion screen on top for a sibling on its intensity
+// ladder, so Back still leads to the parent. Does nothing unless an emotion
+// screen is on top.
+func (c *AppController) StepLadder(siblingID string) {
+	c.mu.Lock()
+	stack := *c.activeLocked()
+	top := &stack[len(stack)-1]
+	if top.Kind != ScreenEmotion {
+		c.mu.Unlock()
+		return
+	}
+	top.Path = core.LadderPath(top.Path, siblingID)
+	slog.Debug("Stepped along the intensity ladder", "emotionID", siblingID)
+	c.mu.Unlock()
+	c.emit()
+}
+
+// ShowSearch opens the emotion search on the active stack. Selecting a result
+// behaves exactly like selecting a card in the current mode.
+func (c *AppController) ShowSearch() {
+	c.mu.Lock()
+	c.pushLocked(Screen{Kind: ScreenSearch})
+	c.mu.Unlock()
+	c.emit()
+}
+
+// ShowHistory opens the journal history on the browsing stack, cancelling
+// an in-progress logging session first.
+func (c *AppController) ShowHistory() {
+	c.mu.Lock()
+	c.stopLoggingLocked()
+	c.pushLocked(Screen{Kind: ScreenHistory})
+	c.mu.Unlock()
+	c.emit()
+}
+
+// ShowCompare opens a comparison of emotions (by ID; any number, including
+// none to let the user pick) on the browsing stack, cancelling an
+// in-progress logging session first.
+func (c *AppController) ShowCompare(emotionIDs ...string) {
+	c.mu.Lock()
+	c.stopLoggingLocked()
+	c.pushLocked(Screen{Kind: ScreenCompare, Path: c.resolveIDsLocked(emotionIDs)})
+	c.mu.Unlock()
+	c.emit()
+}
+
+// ShowLearn opens the vocabulary quiz on the browsing stack, on the words
+// below scope (emotion IDs, e.g. families assigned as homework) or on the
+// whole dataset, cancelling an in-progress logging session first.
+func (c *AppController) ShowLearn(scope ...string) {
+	c.mu.Lock()
+	c.stopLoggingLocked()
+	c.pushLocked(Screen{Kind: ScreenLearn, Path: c.resolveIDsLocked(scope)})
+	c.mu.Unlock()
+	c.emit()
+}
+
+// SetCompared changes which emotions the comparison on top shows, e.g. when
+// the user adds or removes one. Does nothing unless a comparison is on top.
+func (c *AppController) SetCompared(emotionIDs []string) {
+	c.mu.Lock()
+	stack := *c.activeLocked()
+	top := &stack[len(stack)-1]
+	if top.Kind != ScreenCompare {
+		c.mu.Unlock()
+		return
+	}
+	top.Path = c.resolveIDsLocked(emotionIDs)
+	slog.Debug("Compared emotions changed", "count", len(top.Path))
+	c.mu.Unlock()
+	c.emit()
+}
+
+// resolveIDsLocked returns emotion IDs (e.g. to compare) as current IDs
+// (following aliases), without unknown IDs and repeats.
+func (c *AppController) resolveIDsLocked(emotionIDs []string) []string {
+	compared := make([]string, 0, len(emotionIDs))
+	for _, id := range emotionIDs {
+		if resolved, ok := c.resolver.Resolve(id); ok && !slices.Contains(compared, resolved) {
+			compared = append(compared, resolved)
+		}
+	}
+	return compared
+}
+
+// --- Logging ---
+
+// StartLogging switches to logging mode on a fresh stack starting at the
+// top-level emotions. Does nothing if already logging.
+func (c *AppController) StartLogging() {
+	c.mu.Lock()
+	if !c.startLoggingLocked() {
+		c.mu.Unlock()
+		slog.Debug("Already in logging mode")
+		return
+	}
+	c.mu.Unlock()
+	c.emit()
+}
+
+// ShowMoodMeter starts logging on the mood meter. Back leads to the word
+// list of the same logging session.
+func (c *AppController) ShowMoodMeter() {
+	c.mu.Lock()
+	c.startLoggingLocked()
+	c.pushLocked(Screen{Kind: ScreenMoodMeter})
+	c.mu.Unlock()
+	c.emit()
+}
+
+// CancelLogging abandons logging and returns to the browsing screen the user
+// left. Does nothing if not logging.
+func (c *AppController) CancelLogging() {
+	c.mu.Lock()
+	if !c.stopLoggingLocked() {
+		c.mu.Unlock()
+		slog.Debug("Already in browsing mode")
+		return
+	}
+	c.mu.Unlock()
+	c.emit()
+}
+
+// Log saves an entry for an emotion reached by path (nil if unknown) and
+// returns to browsing, whether or not saving worked; the Save hook reports
+// the outcome to the user and the error is returned for callers that report
+// it elsewhere.
+func (c *AppController) Log(emotion data.Emotion, path []string) error {
+	slog.Debug("Logging leaf emotion", "emotionID", emotion.ID)
+	return c.save(c.NewLogEntry(emotion, path), emotion)
+}
+
+// LogMoodMeter saves an entry for an emotion picked on the mood meter with
+// the point the user tapped, then returns to browsing like Log.
+func (c *AppController) LogMoodMeter(emotion data.Emotion, valence, arousal float64) error {
+	slog.Debug("Logging mood meter pick", "emotionID", emotion.ID, "valence", valence, "arousal", arousal)
+	entry := c.NewLogEntry(emotion, nil)
+	entry.Valence, entry.Arousal = &valence, &arousal
+	return c.save(entry, emotion)
+}
+
+// NewLogEntry builds a journal entry for an emotion logged now. The path is
+// only stored if it passes through an emotion with several parents, so
+// entries for plain trees stay as small as before.
+func (c *AppController) NewLogEntry(emotion data.Emotion, path []string) journal.LogEntry {
+	c.mu.Lock()
+	emotions := c.resolver.Emotions()
+	now := c.now()
+	c.mu.Unlock()
+
+	var stored []string
+	for _, step := range core.ResolvePath(path, emotions) {
+		if len(step.Parents()) > 1 {
+			stored = path // Which family the entry belongs to
+			break
+		}
+	}
+	return journal.LogEntry{
+		Timestamp:   now,
+		EmotionID:   emotion.ID,
+		EmotionName: emotion.Name, // Default-language name; history is rendered by ID
+		Notes:       "",           // Notes field exists but is empty for now
+		Path:        stored,
+	}
+}
+
+// save hands an entry to the Save hook and ends the logging session.
+func (c *AppController) save(entry journal.LogEntry, emotion data.Emotion) error {
+	var err error
+	if c.hooks.Save != nil {
+		err = c.hooks.Save(entry, emotion)
+	}
+	c.CancelLogging() // Return to browsing after attempting save
+	return err
+}
+
+// --- Helpers (call with c.mu held) ---
+
+// stateLocked builds the snapshot reported to OnChange.
+func (c *AppController) stateLocked() AppState {
+	stack := *c.activeLocked()
+	return AppState{Mode: c.mode, Screen: stack[len(stack)-1], Depth: len(stack)}
+}
+
+// activeLocked returns the navigation stack of the current mode.
+func (c *AppController) activeLocked() *[]Screen {
+	if c.mode == ModeLogging {
+		return &c.logging
+	}
+	return &c.browsing
+}
+
+// pushLocked adds a screen to the active stack.
+func (c *AppController) pushLocked(screen Screen) {
+	stack := c.activeLocked()
+	*stack = append(*stack, screen)
+	slog.Debug("Pushed screen", "depth", len(*stack), "mode", c.mode)
+}
+
+// startLoggingLocked enters logging mode, reporting false if already in it.
+func (c *AppController) startLoggingLocked() bool {
+	if c.mode == ModeLogging {
+		return false
+	}
+	slog.Info("Switching to logging mode")
+	c.mode = ModeLogging
+	c.logging = []Screen{{Kind: ScreenRoot}} // Start fresh at the top-level emotions
+	return true
+}
+
+// stopLoggingLocked returns to browsing mode, reporting false if not logging.
+func (c *AppController) stopLoggingLocked() bool {
+	if c.mode == ModeBrowsing {
+		return false
+	}
+	slog.Info("Switching to browsing mode")
+	c.mode = ModeBrowsing
+	c.logging = nil
+	return true
+}
+
+// selectionPathLocked returns the path to an emotion selected on the screen
+// on top: the screen's own path plus the emotion if it is one of its
+// children, or the emotion's primary ancestry (e.g. for search results).
+func (c *AppController) selectionPathLocked(emotion data.Emotion) []string {
+	stack := *c.activeLocked()
+	if path := stack[len(stack)-1].Path; len(path) > 0 && emotion.HasParent(path[len(path)-1]) {
+		return append(append([]string(nil), path...), emotion.ID)
+	}
+	return core.PathIDs(core.GetAncestry(emotion.ID, c.resolver.Emotions()))
+}
+
+// pruneLocked cuts a stack below its first screen that can't be shown with
+// the current dataset. The root screen always stays.
+func (c *AppController) pruneLocked(stack []Screen) []Screen {
+	for i, screen := range stack {
+		if i > 0 && !c.existsLocked(screen) {
+			slog.Info("Screen no longer exists; returning to its parent", "screen", i+1, "depth", len(stack))
+			return stack[:i]
+		}
+	}
+	return stack
+}
+
+// existsLocked reports whether a screen can still be shown: emotion screens
+// need their emotion (followed through aliases) and some sub-emotions. An
+// emotion screen without a path shows nothing and is gone too.
+func (c *AppController) existsLocked(screen Screen) bool {
+	if screen.Kind != ScreenEmotion {
+		return true
+	}
+	if len(screen.Path) == 0 {
+		return false
+	}
+	emotion, ok := c.resolver.Lookup(screen.Path[len(screen.Path)-1])
+	return ok && len(core.GetChildrenOf(emotion.ID, c.resolver.Emotions())) > 0
+}
+
+// emit reports the current state to OnChange.
+func (c *AppController) emit() {
+	if c.hooks.OnChange == nil {
+		return
+	}
+	c.hooks.OnChange(c.State())
+}
@@ -0,0 +1,325 @@
+// internal/ui/state_test.go
+package ui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/itsforsxm123/emotion-explorer/internal/journal"
+	"github.com/stretchr/testify/assert"
+)
+
+// stateEmotions is a small hierarchy with leaves, an intensity ladder under
+// Angry and Frustrated, which has sub-emotions and two parents.
+func stateEmotions() map[string]data.Emotion {
+	return map[string]data.Emotion{
+		"happy":      {ID: "happy", Name: "Happy", Position: 0},
+		"angry":      {ID: "angry", Name: "Angry", Position: 1},
+		"sad":        {ID: "sad", Name: "Sad", Position: 2},
+		"playful":    {ID: "playful", Name: "Playful", ParentID: "happy", Position: 0},
+		"content":    {ID: "content", Name: "Content", ParentID: "happy", Position: 1},
+		"cheeky":     {ID: "cheeky", Name: "Cheeky", ParentID: "playful"},
+		"annoyed":    {ID: "annoyed", Name: "Annoyed", ParentID: "angry", Intensity: 1, Position: 0},
+		"furious":    {ID: "furious", Name: "Furious", ParentID: "angry", Intensity: 2, Position: 1},
+		"frustrated": {ID: "frustrated", Name: "Frustrated", ParentID: "angry", ParentIDs: []string{"sad"}, Position: 2},
+		"stuck":      {ID: "stuck", Name: "Stuck", ParentID: "frustrated"},
+		"lonely":     {ID: "lonely", Name: "Lonely", ParentID: "sad", Position: 0},
+	}
+}
+
+// controllerRecorder records what a controller asked of the application.
+type controllerRecorder struct {
+	states  []AppState
+	details [][]string // Paths of the emotions shown
+	ladders []string   // Emotions offered on a ladder
+	saved   []journal.LogEntry
+	saveErr error // Returned by the Save hook
+}
+
+// newTestController creates a controller on stateEmotions that records its
+// hooks, with a fixed clock.
+func newTestController() (*AppController, *controllerRecorder) {
+	rec := &controllerRecorder{}
+	c := NewAppController(core.NewIDResolver(data.EmotionData{Emotions: stateEmotions()}), AppHooks{
+		OnChange:    func(state AppState) { rec.states = append(rec.states, state) },
+		ShowDetails: func(_ data.Emotion, path []string) { rec.details = append(rec.details, path) },
+		ConfirmLadder: func(emotion data.Emotion, _ []string, _ []data.Emotion) {
+			rec.ladders = append(rec.ladders, emotion.ID)
+		},
+		Save: func(entry journal.LogEntry, _ data.Emotion) error {
+			rec.saved = append(rec.saved, entry)
+			return rec.saveErr
+		},
+	})
+	c.now = func() time.Time { return time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC) }
+	return c, rec
+}
+
+// emotionScreen is the screen listing the children of the emotion at the end of path.
+func emotionScreen(path ...string) Screen {
+	return Screen{Kind: ScreenEmotion, Path: path}
+}
+
+// selectID selects an emotion of stateEmotions by ID.
+func selectID(c *AppController, id string) {
+	c.Select(stateEmotions()[id])
+}
+
+// TestAppControllerBrowsing tests navigating the browsing stack.
+func TestAppControllerBrowsing(t *testing.T) {
+	t.Run("Starts at the top-level emotions", func(t *testing.T) {
+		c, rec := newTestController()
+		state := c.State()
+		assert.Equal(t, AppState{Mode: ModeBrowsing, Screen: Screen{Kind: ScreenRoot}, Depth: 1}, state)
+		assert.False(t, state.CanGoBack())
+		assert.Empty(t, rec.states, "nothing is reported before the first action")
+		c.Refresh()
+		assert.Equal(t, []AppState{state}, rec.states)
+	})
+
+	t.Run("Selecting opens sub-emotions along the path taken", func(t *testing.T) {
+		c, rec := newTestController()
+		selectID(c, "happy")
+		selectID(c, "playful")
+		assert.Equal(t, []Screen{{Kind: ScreenRoot}, emotionScreen("happy"), emotionScreen("happy", "playful")}, c.Stack(ModeBrowsing))
+		assert.Len(t, rec.states, 2)
+		assert.True(t, rec.states[1].CanGoBack())
+	})
+
+	t.Run("Second parent is kept in the path", func(t *testing.T) {
+		c, _ := newTestController()
+		selectID(c, "sad")
+		selectID(c, "frustrated")
+		assert.Equal(t, emotionScreen("sad", "frustrated"), c.State().Screen)
+	})
+
+	t.Run("Leaves show their details", func(t *testing.T) {
+		c, rec := newTestController()
+		selectID(c, "happy")
+		selectID(c, "content")
+		assert.Equal(t, [][]string{{"happy", "content"}}, rec.details)
+		assert.Equal(t, 2, c.State().Depth, "no screen is pushed")
+		assert.Empty(t, rec.saved)
+	})
+
+	t.Run("Back pops and stops at the root", func(t *testing.T) {
+		c, rec := newTestController()
+		selectID(c, "happy")
+		c.Back()
+		assert.Equal(t, Screen{Kind: ScreenRoot}, c.State().Screen)
+		c.Back()
+		assert.Len(t, rec.states, 2, "back at the root changes nothing")
+		assert.Equal(t, ModeBrowsing, c.State().Mode)
+	})
+
+	t.Run("Ladder steps replace the screen on top", func(t *testing.T) {
+		c, _ := newTestController()
+		selectID(c, "happy")
+		selectID(c, "playful")
+		c.StepLadder("content")
+		assert.Equal(t, []Screen{{Kind: ScreenRoot}, emotionScreen("happy"), emotionScreen("happy", "content")}, c.Stack(ModeBrowsing))
+
+		c.Back()
+		c.Back()
+		c.StepLadder("angry")
+		assert.Equal(t, Screen{Kind: ScreenRoot}, c.State().Screen, "only emotion screens step")
+	})
+
+	t.Run("Search results use the primary ancestry", func(t *testing.T) {
+		c, _ := newTestController()
+		c.ShowSearch()
+		assert.Equal(t, Screen{Kind: ScreenSearch}, c.State().Screen)
+		selectID(c, "stuck")
+		selectID(c, "frustrated")
+		assert.Equal(t, emotionScreen("angry", "frustrated"), c.State().Screen)
+		assert.Equal(t, 3, c.State().Depth)
+	})
+
+	t.Run("History cancels logging", func(t *testing.T) {
+		c, _ := newTestController()
+		selectID(c, "happy")
+		c.StartLogging()
+		c.ShowHistory()
+		assert.Equal(t, AppState{Mode: ModeBrowsing, Screen: Screen{Kind: ScreenHistory}, Depth: 3}, c.State())
+		assert.Empty(t, c.Stack(ModeLogging))
+	})
+}
+
+// TestAppControllerLogging tests logging sessions and how they end.
+func TestAppControllerLogging(t *testing.T) {
+	t.Run("Logging starts fresh and leaves browsing alone", func(t *testing.T) {
+		c, rec := newTestController()
+		selectID(c, "happy")
+		c.StartLogging()
+		assert.Equal(t, AppState{Mode: ModeLogging, Screen: Screen{Kind: ScreenRoot}, Depth: 1}, c.State())
+		selectID(c, "sad")
+		assert.Equal(t, []Screen{{Kind: ScreenRoot}, emotionScreen("sad")}, c.Stack(ModeLogging))
+		assert.Equal(t, []Screen{{Kind: ScreenRoot}, emotionScreen("happy")}, c.Stack(ModeBrowsing))
+
+		c.StartLogging()
+		assert.Len(t, rec.states, 3, "starting again changes nothing")
+		assert.Equal(t, 2, c.State().Depth)
+	})
+
+	t.Run("Leaves are saved and browsing resumes", func(t *testing.T) {
+		c, rec := newTestController()
+		selectID(c, "happy")
+		c.StartLogging()
+		selectID(c, "sad")
+		selectID(c, "lonely")
+		if assert.Len(t, rec.saved, 1) {
+			assert.Equal(t, journal.LogEntry{
+				Timestamp:   time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+				EmotionID:   "lonely",
+				EmotionName: "Lonely",
+			}, rec.saved[0])
+		}
+		assert.Equal(t, AppState{Mode: ModeBrowsing, Screen: emotionScreen("happy"), Depth: 2}, c.State())
+		assert.Empty(t, c.Stack(ModeLogging))
+		assert.Empty(t, rec.details)
+	})
+
+	t.Run("Failed saves still end logging", func(t *testing.T) {
+		c, rec := newTestController()
+		rec.saveErr = errors.New("disk full")
+		c.StartLogging()
+		err := c.Log(stateEmotions()["content"], nil)
+		assert.EqualError(t, err, "disk full")
+		assert.Equal(t, ModeBrowsing, c.State().Mode)
+	})
+
+	t.Run("Leaves on a ladder are confirmed first", func(t *testing.T) {
+		c, rec := newTestController()
+		c.StartLogging()
+		selectID(c, "angry")
+		selectID(c, "annoyed")
+		assert.Equal(t, []string{"annoyed"}, rec.ladders)
+		assert.Empty(t, rec.saved)
+		assert.Equal(t, ModeLogging, c.State().Mode, "cancelling the ladder keeps logging")
+	})
+
+	t.Run("Back at the logging root cancels", func(t *testing.T) {
+		c, _ := newTestController()
+		c.StartLogging()
+		selectID(c, "sad")
+		c.Back()
+		assert.Equal(t, ModeLogging, c.State().Mode)
+		c.Back()
+		assert.Equal(t, AppState{Mode: ModeBrowsing, Screen: Screen{Kind: ScreenRoot}, Depth: 1}, c.State())
+	})
+
+	t.Run("Cancel outside logging does nothing", func(t *testing.T) {
+		c, rec := newTestController()
+		c.CancelLogging()
+		assert.Empty(t, rec.states)
+	})
+
+	t.Run("Mood meter", func(t *testing.T) {
+		c, rec := newTestController()
+		c.ShowMoodMeter()
+		assert.Equal(t, []Screen{{Kind: ScreenRoot}, {Kind: ScreenMoodMeter}}, c.Stack(ModeLogging))
+		assert.Len(t, rec.states, 1, "one change for starting and opening")
+
+		assert.NoError(t, c.LogMoodMeter(stateEmotions()["content"], 0.5, -0.25))
+		if assert.Len(t, rec.saved, 1) {
+			assert.Equal(t, 0.5, *rec.saved[0].Valence)
+			assert.Equal(t, -0.25, *rec.saved[0].Arousal)
+		}
+		assert.Equal(t, ModeBrowsing, c.State().Mode)
+	})
+
+	t.Run("Search while logging logs", func(t *testing.T) {
+		c, rec := newTestController()
+		c.StartLogging()
+		c.ShowSearch()
+		assert.Equal(t, []Screen{{Kind: ScreenRoot}, {Kind: ScreenSearch}}, c.Stack(ModeLogging))
+		selectID(c, "content")
+		assert.Len(t, rec.saved, 1)
+	})
+}
+
+// TestAppControllerCompare tests opening a comparison and changing what it
+// compares.
+func TestAppControllerCompare(t *testing.T) {
+	c, rec := newTestController()
+	c.StartLogging()
+	selectID(c, "angry")
+	c.ShowCompare("lonely", "gone", "lonely", "stuck") // Unknown and repeated IDs are dropped
+	assert.Equal(t, ModeBrowsing, c.State().Mode, "comparing ends logging")
+	assert.Empty(t, rec.saved)
+	assert.Equal(t, Screen{Kind: ScreenCompare, Path: []string{"lonely", "stuck"}}, c.State().Screen)
+
+	c.SetCompared([]string{"lonely", "stuck", "cheeky"})
+	assert.Equal(t, Screen{Kind: ScreenCompare, Path: []string{"lonely", "stuck", "cheeky"}}, c.State().Screen)
+	assert.Equal(t, 2, c.State().Depth, "changing the comparison replaces it")
+
+	c.Back()
+	c.SetCompared([]string{"happy"}) // Not on a comparison: ignored
+	assert.Equal(t, Screen{Kind: ScreenRoot}, c.State().Screen)
+
+	c.StartLogging()
+	c.ShowLearn("sad", "gone")
+	assert.Equal(t, AppState{Mode: ModeBrowsing, Screen: Screen{Kind: ScreenLearn, Path: []string{"sad"}}, Depth: 2}, c.State(), "the quiz ends logging too")
+}
+
+// TestNewLogEntry tests which paths are stored with journal entries.
+func TestNewLogEntry(t *testing.T) {
+	c, _ := newTestController()
+	testCases := []struct {
+		name string
+		path []string
+		want []string
+	}{
+		{"No path", nil, nil},
+		{"Plain tree", []string{"happy", "playful", "cheeky"}, nil},
+		{"Through an emotion with two parents", []string{"sad", "frustrated", "stuck"}, []string{"sad", "frustrated", "stuck"}},
+		{"Stale path", []string{"happy", "stuck"}, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			entry := c.NewLogEntry(stateEmotions()["stuck"], tc.path)
+			assert.Equal(t, tc.want, entry.Path)
+			assert.Equal(t, "stuck", entry.EmotionID)
+		})
+	}
+}
+
+// TestAppControllerRefresh tests dropping screens the dataset no longer has.
+func TestAppControllerRefresh(t *testing.T) {
+	c, rec := newTestController()
+	selectID(c, "happy")
+	selectID(c, "playful")
+	c.ShowSearch()
+	c.StartLogging()
+	selectID(c, "angry")
+	selectID(c, "frustrated")
+
+	emotions := stateEmotions()
+	delete(emotions, "frustrated")
+	delete(emotions, "stuck")
+	delete(emotions, "playful") // Renamed; the alias keeps its screen
+	emotions["silly"] = data.Emotion{ID: "silly", Name: "Silly", ParentID: "happy"}
+	emotions["cheeky"] = data.Emotion{ID: "cheeky", Name: "Cheeky", ParentID: "silly"}
+	c.SetResolver(core.NewIDResolver(data.EmotionData{
+		Emotions: emotions,
+		Aliases:  []data.IDAlias{{From: "playful", To: "silly"}},
+	}))
+	c.Refresh()
+
+	assert.Equal(t, []Screen{{Kind: ScreenRoot}, emotionScreen("happy"), emotionScreen("happy", "playful"), {Kind: ScreenSearch}}, c.Stack(ModeBrowsing))
+	assert.Equal(t, []Screen{{Kind: ScreenRoot}, emotionScreen("angry")}, c.Stack(ModeLogging))
+	assert.Equal(t, AppState{Mode: ModeLogging, Screen: emotionScreen("angry"), Depth: 2}, rec.states[len(rec.states)-1])
+}
+
+// TestAppControllerRefreshEmptyPath tests that an emotion screen without a
+// path is dropped rather than shown.
+func TestAppControllerRefreshEmptyPath(t *testing.T) {
+	c, rec := newTestController()
+	c.browsing = append(c.browsing, emotionScreen(), emotionScreen("happy"))
+	assert.NotPanics(t, c.Refresh)
+	assert.Equal(t, []Screen{{Kind: ScreenRoot}}, c.Stack(ModeBrowsing))
+	assert.Equal(t, AppState{Mode: ModeBrowsing, Screen: Screen{Kind: ScreenRoot}, Depth: 1}, rec.states[len(rec.states)-1])
+}
@@ -4,18 +4,24 @@ package ui
 import (
 	"fmt"
 	"image/color"
-	"log"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
 
 	"fyne.io/fyne/v2"
-	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
-	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/widget"
 
+	"github.com/itsforsxm123/emotion-explorer/internal/analytics"
+	"github.com/itsforsxm123/emotion-explorer/internal/core"
 	"github.com/itsforsxm123/emotion-explorer/internal/data" // Use your module path
+	"github.com/itsforsxm123/emotion-explorer/internal/i18n"
+	"github.com/itsforsxm123/emotion-explorer/internal/journal"
 )
 
-// CreateEmotionListView generates a generic UI container displaying items (tappable cards) for a list of emotions.
+// CreateEmotionListView generates a generic UI container displaying items (cards in a
+// virtualized EmotionGrid) for a list of emotions.
 // It supports an optional title and an optional parent context.
 // Back navigation is now handled globally by the main application structure.
 func CreateEmotionListView(
@@ -23,137 +29,187 @@ func CreateEmotionListView(
 	parent *data.Emotion, // Optional parent context (can be nil)
 	emotions []data.Emotion, // The list of emotions to display
 	onSelected func(selectedEmotion data.Emotion), // Callback when an item is clicked
-	// --- REMOVED goBack func() ---
-	// --- REMOVED backButtonLabel string ---
 ) fyne.CanvasObject {
+	slog.Debug("Creating emotion list view", "title", title, "emotions", len(emotions))
 
-	// --- Log statement updated ---
-	log.Printf("Creating generic list view: Title='%s', Parent='%v', #Emotions=%d",
-		title, parent, len(emotions))
-
-	// --- Content Items (Tappable Cards or Message) ---
-	var contentItems []fyne.CanvasObject
+	// --- Content Items (Emotion Grid or Message) ---
+	var content fyne.CanvasObject
 	if len(emotions) == 0 {
-		message := "No emotions found."
+		message := i18n.T("view.list.empty")
 		if parent != nil {
-			message = fmt.Sprintf("No specific sub-emotions listed under %s.", parent.Name)
+			message = i18n.T("view.list.emptyUnder", DisplayName(*parent))
 		}
-		contentItems = append(contentItems, widget.NewLabel(message))
-		log.Printf("Warning: CreateEmotionListView called with 0 emotions for parent '%v'.", parent)
+		content = container.NewVBox(widget.NewLabel(message))
+		slog.Warn("Emotion list view created without emotions", "title", title)
 	} else {
-		// Create card items for each emotion
-		for _, emotion := range emotions {
-			// --- Card Implementation (No changes needed here) ---
-			currentEmotion := emotion // Capture loop variable
-			emotionColor, err := parseHexColor(currentEmotion.Color)
-			if err != nil {
-				log.Printf("Warning: Failed to parse color '%s' for emotion '%s': %v. Using default.",
-					currentEmotion.Color, currentEmotion.Name, err)
-				emotionColor = color.NRGBA{R: 128, G: 128, B: 128, A: 255}
-			}
-			colorSwatch := canvas.NewRectangle(emotionColor)
-			swatchSize := float32(20)
-			colorSwatch.SetMinSize(fyne.NewSize(swatchSize, swatchSize))
-			nameLabel := widget.NewLabel(currentEmotion.Name)
-			nameLabel.Alignment = fyne.TextAlignLeading
-			nameLabel.TextStyle = fyne.TextStyle{Bold: true}
-			cardContent := container.NewHBox(
-				colorSwatch,
-				layout.NewSpacer(),
-				nameLabel,
-				layout.NewSpacer(),
-			)
-			cardVisual := widget.NewCard("", "", container.NewPadded(cardContent))
-			tapAction := func() {
-				parentName := "N/A"
-				if parent != nil {
-					parentName = parent.Name
-				}
-				log.Printf("Card '%s' (ID: %s, Parent: %s) clicked via TappableCard. Triggering onSelected callback.\n",
-					currentEmotion.Name, currentEmotion.ID, parentName)
-				if onSelected != nil {
-					onSelected(currentEmotion)
-				} else {
-					log.Println("Warning: onSelected callback is nil in CreateEmotionListView.")
-				}
-			}
-			tappableWrapper := NewTappableCard(cardVisual, tapAction)
-			contentItems = append(contentItems, tappableWrapper)
-			// --- Card Implementation End ---
-		}
+		// Cards are created only for the visible cells and recycled on scroll
+		content = NewEmotionGrid(emotions, onSelected)
 	}
 
-	// Use GridWrap layout for the emotion cards
-	contentGrid := container.NewGridWrap(fyne.NewSize(200, 60), contentItems...) // Adjust size as needed
-
 	// --- Assemble the View (Header/Content) ---
 	topItems := []fyne.CanvasObject{}
-	// --- REMOVED bottomItems declaration ---
 
 	// Add Header if title is provided
 	if title != "" {
-		headerLabel := widget.NewLabel(title)
-		headerLabel.TextStyle = fyne.TextStyle{Bold: true}
-		headerLabel.Alignment = fyne.TextAlignCenter
-		topItems = append(topItems, headerLabel, widget.NewSeparator())
+		topItems = append(topItems, newHeader(title)...)
 	}
 
-	// --- REMOVED Back Button Logic ---
-	// if goBack != nil { ... } block is removed
-
 	// Use a Border layout for structure: Header (Top), Content (Center)
-	// Wrap the content grid in a Scroll container
+	// The grid scrolls by itself
 	viewLayout := container.NewBorder(
 		container.NewVBox(topItems...), // Top: Header and separator (if any)
-		// --- REMOVED Bottom parameter (was container.NewVBox(bottomItems...)) ---
-		nil,                              // Bottom: Nothing here now
-		nil,                              // Left
-		nil,                              // Right
-		container.NewScroll(contentGrid), // Center: Scrollable grid of emotion cards
+		nil,                            // Bottom
+		nil,                            // Left
+		nil,                            // Right
+		content,                        // Center: Grid of emotion cards
 	)
 
 	return viewLayout
 }
 
-// parseHexColor function remains unchanged
-func parseHexColor(s string) (color.Color, error) {
-	// ... (implementation is the same) ...
-	var r, g, b uint8
-	var format string
+// newHeader creates the bold, centered title row used at the top of views.
+func newHeader(title string) []fyne.CanvasObject {
+	headerLabel := widget.NewLabel(title)
+	headerLabel.TextStyle = fyne.TextStyle{Bold: true}
+	headerLabel.Alignment = fyne.TextAlignCenter
+	return []fyne.CanvasObject{headerLabel, widget.NewSeparator()}
+}
 
-	if len(s) == 0 {
-		return color.Black, fmt.Errorf("empty color string")
+// CreateSearchView generates a view with a search box above a grid of matching
+// emotion cards. Results update as the user types; pressing Enter in the box
+// moves keyboard focus to the first result.
+func CreateSearchView(
+	allEmotions map[string]data.Emotion, // Emotions to search through
+	onSelected func(selectedEmotion data.Emotion), // Callback when a result is clicked
+) fyne.CanvasObject {
+	slog.Debug("Creating search view", "emotions", len(allEmotions))
+
+	resultsGrid := NewEmotionGrid(nil, onSelected)
+	noMatch := widget.NewLabel("")
+	noMatch.Hide()
+
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder(i18n.T("search.placeholder"))
+	searchEntry.OnChanged = func(query string) {
+		results := core.SearchEmotions(query, allEmotions)
+		if strings.TrimSpace(query) != "" && len(results) == 0 {
+			noMatch.SetText(i18n.T("search.noMatch", query))
+			noMatch.Show()
+		} else {
+			noMatch.Hide()
+		}
+		resultsGrid.SetEmotions(results)
+	}
+	searchEntry.OnSubmitted = func(string) {
+		if c := canvasFor(searchEntry); c != nil && len(resultsGrid.Emotions()) > 0 {
+			c.Focus(resultsGrid) // Continue with the first result
+		}
 	}
 
-	if s[0] == '#' {
-		s = s[1:] // Remove leading '#'
+	topItems := append(newHeader(i18n.T("search.title")), searchEntry)
+	return container.NewBorder(
+		container.NewVBox(topItems...), // Top: Header and search box
+		nil,                            // Bottom
+		nil,                            // Left
+		nil,                            // Right
+		container.NewStack(resultsGrid, container.NewVBox(noMatch)), // Center: Grid of results, or why there are none
+	)
+}
+
+// CreateHistoryView generates a read-only list of journal entries, newest first.
+// Entries are shown by emotion ID in the current language (legacy IDs are
+// resolved through the dataset's aliases); the name stored in the entry is
+// only used if the ID cannot be resolved. A second tab plots the entries on
+// the valence/arousal plane (see CreateMoodPlotView), a third follows them
+// along intensity ladders (see CreateEscalationView). If days is positive
+// only the entries of the last days days are shown.
+func CreateHistoryView(entries []journal.LogEntry, days int, resolver *core.IDResolver) fyne.CanvasObject {
+	title := i18n.T("history.title")
+	if days > 0 {
+		entries = analytics.Since(entries, time.Now().AddDate(0, 0, -days))
+		title = i18n.T("history.titleRange", days)
+	}
+	slog.Debug("Creating history view", "entries", len(entries), "days", days)
+
+	// Copy and sort so the caller's slice keeps its on-disk order
+	sorted := make([]journal.LogEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.After(sorted[j].Timestamp)
+	})
+
+	var content fyne.CanvasObject
+	if len(sorted) == 0 {
+		content = container.NewCenter(widget.NewLabel(i18n.T("history.empty")))
+	} else {
+		content = widget.NewList(
+			func() int { return len(sorted) },
+			func() fyne.CanvasObject { return widget.NewLabel("") },
+			func(id widget.ListItemID, item fyne.CanvasObject) {
+				item.(*widget.Label).SetText(formatHistoryLine(sorted[id], resolver))
+			},
+		)
 	}
 
-	switch len(s) {
-	case 6: // RRGGBB
-		format = "%02x%02x%02x"
-	case 3: // RGB (shorthand) - Expand to RRGGBB
-		format = "%1x%1x%1x" // Read single hex digits
-		_, err := fmt.Sscanf(s, format, &r, &g, &b)
-		if err != nil {
-			return color.Black, fmt.Errorf("invalid shorthand hex color format: %w", err)
+	top := newHeader(title)
+	if summary := historySummary(entries, resolver); summary != "" {
+		top = []fyne.CanvasObject{top[0], widget.NewLabel(summary), top[1]} // Between the title and the separator
+	}
+
+	tabs := container.NewAppTabs(
+		container.NewTabItem(i18n.T("history.tabEntries"), content),
+		container.NewTabItem(i18n.T("history.tabMood"), CreateMoodPlotView(analytics.MoodPoints(entries, resolver), resolver)),
+		container.NewTabItem(i18n.T("history.tabIntensity"), CreateEscalationView(analytics.Escalation(entries, resolver), resolver)),
+	)
+
+	return container.NewBorder(
+		container.NewVBox(top...), // Top: Header and summary
+		nil,                       // Bottom
+		nil,                       // Left
+		nil,                       // Right
+		tabs,                      // Center: List of entries, mood map, escalation
+	)
+}
+
+// historyTopFamilies is how many families the history summary names.
+const historyTopFamilies = 3
+
+// historySummary describes the entries in one line, e.g.
+// "30 entries · most logged: Happy (12), Sad (5), Angry (3)".
+// Returns "" if no entry resolves to an emotion.
+func historySummary(entries []journal.LogEntry, resolver *core.IDResolver) string {
+	summary := analytics.Summarize(entries, resolver)
+	families := make([]string, 0, historyTopFamilies)
+	for _, family := range summary.TopFamilies(historyTopFamilies) {
+		if emotion, ok := resolver.Lookup(family.EmotionID); ok {
+			families = append(families, fmt.Sprintf("%s (%d)", DisplayName(emotion), family.Count))
 		}
-		// Expand: e.g., F -> FF, A -> AA
-		r = r*16 + r
-		g = g*16 + g
-		b = b*16 + b
-		// Now format as RRGGBB for consistency in return type
-		format = "%02x%02x%02x"
-		s = fmt.Sprintf("%02x%02x%02x", r, g, b) // Recreate the 6-digit string
-	default:
-		return color.Black, fmt.Errorf("invalid hex color string length: %d", len(s))
 	}
+	if len(families) == 0 {
+		return ""
+	}
+	return i18n.T("history.summary", summary.Total, strings.Join(families, ", "))
+}
 
-	// Scan the 6-digit hex string
-	_, err := fmt.Sscanf(s, format, &r, &g, &b)
-	if err != nil {
-		return color.Black, fmt.Errorf("invalid hex color format: %w", err)
+// formatHistoryLine renders a journal entry as "YYYY-MM-DD HH:MM - Name - Notes".
+func formatHistoryLine(entry journal.LogEntry, resolver *core.IDResolver) string {
+	name := entry.EmotionName
+	if emotion, ok := resolver.Lookup(entry.EmotionID); ok {
+		name = DisplayName(emotion)
 	}
+	line := fmt.Sprintf("%s - %s", entry.Timestamp.Format("2006-01-02 15:04"), name)
+	if entry.Notes != "" {
+		line += " - " + entry.Notes
+	}
+	return line
+}
 
-	return color.NRGBA{R: r, G: g, B: b, A: 255}, nil // Return NRGBA (non-alpha-premultiplied) or RGBA
+// DisplayName returns an emotion's name in the active UI language.
+func DisplayName(emotion data.Emotion) string {
+	return emotion.LocalizedName(i18n.Chain())
+}
+
+// parseHexColor parses a dataset color; see data.ParseHexColor.
+func parseHexColor(s string) (color.Color, error) {
+	return data.ParseHexColor(s)
 }
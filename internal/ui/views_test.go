@@ -0,0 +1,99 @@
+// internal/ui/views_test.go
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/itsforsxm123/emotion-explorer/internal/core"
+	"github.com/itsforsxm123/emotion-explorer/internal/data"
+	"github.com/itsforsxm123/emotion-explorer/internal/i18n"
+	"github.com/itsforsxm123/emotion-explorer/internal/journal"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHistorySummary tests the one-line summary above the history list on a
+// two-level dataset.
+func TestHistorySummary(t *testing.T) {
+	resolver := core.NewIDResolver(data.EmotionData{Emotions: map[string]data.Emotion{
+		"calm":    {ID: "calm", Name: "Calm"},
+		"relaxed": {ID: "relaxed", Name: "Relaxed", ParentID: "calm"},
+		"upset":   {ID: "upset", Name: "Upset"},
+	}})
+	entries := []journal.LogEntry{{EmotionID: "relaxed"}, {EmotionID: "calm"}, {EmotionID: "upset"}, {EmotionID: "gone"}}
+
+	assert.Equal(t, "4 entries · most logged: Calm (2), Upset (1)", historySummary(entries, resolver))
+	assert.Equal(t, "", historySummary([]journal.LogEntry{{EmotionID: "gone"}}, resolver), "Nothing to summarize")
+}
+
+// TestCompareView tests the texts of the comparison view and adding and
+// removing emotions.
+func TestCompareView(t *testing.T) {
+	test.NewApp()
+	previousLocale := i18n.Locale()
+	i18n.SetLocale("en")
+	defer i18n.SetLocale(previousLocale)
+
+	valence := -0.6
+	emotions := stateEmotions()
+	lonely := emotions["lonely"]
+	lonely.Description = "Wanting company."
+	lonely.Valence = &valence
+	emotions["lonely"] = lonely
+	resolver := core.NewIDResolver(data.EmotionData{Emotions: emotions})
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 12, 0, 0, 0, time.Local) }
+	entries := []journal.LogEntry{
+		{EmotionID: "lonely", Timestamp: day(1)},
+		{EmotionID: "lonely", Timestamp: day(1)},
+		{EmotionID: "stuck", Timestamp: day(1)},
+		{EmotionID: "lonely", Timestamp: day(2)},
+	}
+
+	var changed [][]string
+	view := CreateCompareView([]string{"lonely", "stuck", "gone"}, entries, resolver, func(ids []string) { changed = append(changed, ids) })
+	texts := []string{}
+	var buttons []*widget.Button
+	walkObjects(view, func(obj fyne.CanvasObject) bool {
+		switch o := obj.(type) {
+		case *widget.Label:
+			texts = append(texts, o.Text)
+		case *widget.Button:
+			buttons = append(buttons, o)
+		}
+		return true
+	})
+	for _, want := range []string{
+		"Common ancestor: Sad",
+		"Path: Sad › Lonely",
+		"Path: Angry › Frustrated › Stuck",
+		"Wanting company.",
+		"No description.",
+		"Valence -0.60 · Arousal – · Dominance –",
+		"No dimensional coordinates.",
+		"Entries: 3 · Days logged: 2",
+		"Entries: 1 · Days logged: 1",
+		"Days also logged with Stuck: 1",
+		"Days also logged with Lonely: 1",
+	} {
+		assert.Contains(t, texts, want)
+	}
+
+	if assert.Len(t, buttons, 2, "one Remove button per known emotion") {
+		test.Tap(buttons[0])
+		assert.Equal(t, [][]string{{"stuck"}}, changed)
+	}
+
+	changed = nil
+	walkObjects(view, func(obj fyne.CanvasObject) bool {
+		if entry, ok := obj.(*widget.Entry); ok {
+			test.Type(entry, "chee")
+			entry.OnSubmitted(entry.Text)
+			return false
+		}
+		return true
+	})
+	assert.Equal(t, [][]string{{"lonely", "stuck", "cheeky"}}, changed)
+}
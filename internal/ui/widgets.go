@@ -2,46 +2,129 @@
 package ui
 
 import (
+	"image/color"
+
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
 
-// TappableCard is a simple custom widget that wraps any canvas object
-// and makes it respond to tap events.
-type TappableCard struct {
-	widget.BaseWidget                   // Embed BaseWidget
-	content           fyne.CanvasObject // The content to display (e.g., our card)
-	onTapped          func()            // The function to call when tapped
+// Accessible is implemented by custom widgets that describe themselves for
+// assistive technology. Fyne has no screen reader bridge yet, so this is the
+// contract such a bridge (or a test) can query; standard Fyne widgets expose
+// their text directly.
+type Accessible interface {
+	AccessibleName() string        // Short label, e.g. "Playful"
+	AccessibleRole() string        // Kind of control, e.g. RoleButton
+	AccessibleDescription() string // Extra context, e.g. "Emotion in Happy"
 }
 
-// NewTappableCard creates a new TappableCard instance.
-func NewTappableCard(content fyne.CanvasObject, onTapped func()) *TappableCard {
-	tc := &TappableCard{
-		content:  content,
-		onTapped: onTapped,
-	}
-	tc.ExtendBaseWidget(tc) // Important: Set up BaseWidget
-	return tc
+// RoleButton is the accessible role of widgets that perform an action when activated.
+const RoleButton = "button"
+
+// emotionCell is one recycled card of an EmotionGrid: the emotion color
+// fills it, the name is drawn in a color picked for contrast, and a focus
+// outline shows which card the keyboard is on. Tapping it selects the
+// emotion it currently shows.
+type emotionCell struct {
+	widget.BaseWidget
+
+	grid  *EmotionGrid
+	index int       // Position in the grid of the emotion shown
+	model cardModel // What the cell shows (see cardFor)
+
+	background *canvas.Rectangle
+	name       *canvas.Text
+	outline    *canvas.Rectangle
 }
 
-// CreateRenderer returns the renderer for this widget.
-// For TappableCard, the renderer simply displays the contained content.
-func (tc *TappableCard) CreateRenderer() fyne.WidgetRenderer {
-	// Use a SimpleRenderer that just renders the content object.
-	return widget.NewSimpleRenderer(tc.content)
+// newEmotionCell creates an empty cell for grid.
+func newEmotionCell(grid *EmotionGrid) *emotionCell {
+	cell := &emotionCell{
+		grid:       grid,
+		background: canvas.NewRectangle(color.Transparent),
+		name:       canvas.NewText("", color.Black),
+		outline:    canvas.NewRectangle(color.Transparent), // Outline only, the card stays visible
+	}
+	cell.background.CornerRadius = theme.InputRadiusSize()
+	cell.name.Alignment = fyne.TextAlignCenter
+	cell.name.TextStyle = fyne.TextStyle{Bold: true}
+	cell.outline.StrokeWidth = 2
+	cell.outline.Hide() // Only shown while focused
+	cell.ExtendBaseWidget(cell)
+	return cell
 }
 
-// Tapped is called when the TappableCard receives a tap event.
-func (tc *TappableCard) Tapped(_ *fyne.PointEvent) {
-	if tc.onTapped != nil {
-		tc.onTapped() // Execute the stored callback function
+// show points the cell at the emotion at index in its grid.
+func (c *emotionCell) show(index int, model cardModel, focused bool) {
+	c.index = index
+	c.model = model
+
+	c.background.FillColor = model.color
+	if ContrastAgainstTheme(model.color) < MinContrastLargeText {
+		// Outline cards that would blend into the window background
+		c.background.StrokeColor = theme.Color(theme.ColorNameForeground)
+		c.background.StrokeWidth = 1
+	} else {
+		c.background.StrokeWidth = 0
+	}
+	c.name.Text = model.name
+	c.name.Color = model.textColor
+	c.outline.StrokeColor = theme.Color(theme.ColorNameFocus)
+	if focused {
+		c.outline.Show()
+	} else {
+		c.outline.Hide()
 	}
+
+	c.background.Refresh()
+	c.name.Refresh()
+	c.outline.Refresh()
+}
+
+// CreateRenderer stacks the background, the centered name and the outline.
+func (c *emotionCell) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewStack(c.background, container.NewCenter(c.name), c.outline))
+}
+
+// MinSize makes every cell card-sized; the grid sizes its cells from it.
+func (c *emotionCell) MinSize() fyne.Size {
+	return cardSize
+}
+
+// Tapped selects the emotion the cell shows.
+func (c *emotionCell) Tapped(_ *fyne.PointEvent) {
+	c.grid.activate(c.index)
+}
+
+// AccessibleName returns the name of the emotion shown.
+func (c *emotionCell) AccessibleName() string {
+	return c.model.name
+}
+
+// AccessibleRole reports that a card behaves like a button.
+func (c *emotionCell) AccessibleRole() string {
+	return RoleButton
+}
+
+// AccessibleDescription returns where the emotion sits in the hierarchy.
+func (c *emotionCell) AccessibleDescription() string {
+	return c.model.description
 }
 
-// TappedSecondary is called for right-click or alternative tap events (optional).
-// We don't need it here, but it's part of the Tappable interface implicitly
-// via the functions checked by the event system. We don't need to implement it
-// if we only care about primary taps.
+// Ensure emotionCell implements the interfaces the event system checks for.
+var (
+	_ fyne.Tappable = (*emotionCell)(nil)
+	_ Accessible    = (*emotionCell)(nil)
+)
 
-// Ensure TappableCard implements the fyne.Tappable interface implicitly
-// by having the Tapped method. (No explicit 'implements' keyword in Go).
+// canvasFor returns the canvas an object is currently drawn on, or nil.
+func canvasFor(obj fyne.CanvasObject) fyne.Canvas {
+	app := fyne.CurrentApp()
+	if app == nil {
+		return nil
+	}
+	return app.Driver().CanvasForObject(obj)
+}
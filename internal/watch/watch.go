@@ -0,0 +1,176 @@
+// internal/watch/watch.go
+package watch
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultDelay is how long a file must be quiet before its change handler
+// runs. Editors and our own journal writes produce bursts of events
+// (truncate, write, chmod, rename) for a single logical save.
+const DefaultDelay = 250 * time.Millisecond
+
+// Watcher calls a handler when a watched file changes on disk.
+//
+// It watches each file's directory rather than the file itself, so changes
+// keep being noticed when an editor saves by writing a new file and
+// renaming it over the old one (which would end a watch on the file).
+type Watcher struct {
+	fsw   *fsnotify.Watcher
+	delay time.Duration
+
+	mu       sync.Mutex
+	handlers map[string]func()      // Cleaned file path -> change handler
+	timers   map[string]*time.Timer // Pending debounced calls per file
+	dirs     map[string]int         // Watched directory -> number of files in it
+	closed   bool
+	done     chan struct{}
+}
+
+// New starts a watcher that runs handlers once a file has been quiet for delay.
+func New(delay time.Duration) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("creating file watcher: %w", err)
+	}
+	w := &Watcher{
+		fsw:      fsw,
+		delay:    delay,
+		handlers: make(map[string]func()),
+		timers:   make(map[string]*time.Timer),
+		dirs:     make(map[string]int),
+		done:     make(chan struct{}),
+	}
+	go w.run()
+	return w, nil
+}
+
+// Watch registers onChange to be called (on the watcher's goroutine) after
+// path is created, written, renamed or removed. Watching a path again
+// replaces its handler. The file need not exist yet, but its directory must.
+func (w *Watcher) Watch(path string, onChange func()) error {
+	path = filepath.Clean(path)
+	dir := filepath.Dir(path)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return fmt.Errorf("watching '%s': watcher is closed", path)
+	}
+	if _, exists := w.handlers[path]; !exists {
+		if w.dirs[dir] == 0 {
+			if err := w.fsw.Add(dir); err != nil {
+				return fmt.Errorf("watching directory '%s': %w", dir, err)
+			}
+		}
+		w.dirs[dir]++
+	}
+	w.handlers[path] = onChange
+	slog.Debug("Watching file for changes", "path", path)
+	return nil
+}
+
+// Unwatch stops watching path. Unknown paths are ignored.
+func (w *Watcher) Unwatch(path string) error {
+	path = filepath.Clean(path)
+	dir := filepath.Dir(path)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, exists := w.handlers[path]; !exists || w.closed {
+		return nil
+	}
+	delete(w.handlers, path)
+	if timer := w.timers[path]; timer != nil {
+		timer.Stop()
+		delete(w.timers, path)
+	}
+	w.dirs[dir]--
+	if w.dirs[dir] > 0 {
+		return nil
+	}
+	delete(w.dirs, dir)
+	if err := w.fsw.Remove(dir); err != nil {
+		return fmt.Errorf("unwatching directory '%s': %w", dir, err)
+	}
+	return nil
+}
+
+// Close stops the watcher. Pending handler calls are dropped.
+func (w *Watcher) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	for path, timer := range w.timers {
+		timer.Stop()
+		delete(w.timers, path)
+	}
+	w.mu.Unlock()
+
+	err := w.fsw.Close()
+	<-w.done
+	return err
+}
+
+// run dispatches fsnotify events until the watcher is closed.
+func (w *Watcher) run() {
+	defer close(w.done)
+	for {
+		select {
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write) {
+				continue // Permission/attribute changes don't alter contents
+			}
+			w.schedule(filepath.Clean(event.Name))
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			slog.Warn("File watcher error", "err", err)
+		}
+	}
+}
+
+// schedule (re)starts the debounce timer for path if it is being watched.
+func (w *Watcher) schedule(path string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return
+	}
+	if _, watched := w.handlers[path]; !watched {
+		return // Another file in the same directory
+	}
+	if timer := w.timers[path]; timer != nil {
+		timer.Reset(w.delay)
+		return
+	}
+	w.timers[path] = time.AfterFunc(w.delay, func() { w.fire(path) })
+}
+
+// fire runs the handler for path once its debounce delay has passed.
+func (w *Watcher) fire(path string) {
+	w.mu.Lock()
+	delete(w.timers, path)
+	handler := w.handlers[path]
+	closed := w.closed
+	w.mu.Unlock()
+
+	if closed || handler == nil {
+		return
+	}
+	slog.Debug("Detected file change", "path", path)
+	handler()
+}
@@ -0,0 +1,114 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testDelay = 50 * time.Millisecond
+
+// waitFor polls cond until it holds or the timeout passes.
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return cond()
+}
+
+// TestWatchDebouncesBursts tests that a burst of writes produces a single call.
+func TestWatchDebouncesBursts(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "journal.json")
+	w, err := New(testDelay)
+	if err != nil {
+		t.Fatalf("New() returned an unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	var calls atomic.Int32
+	if err := w.Watch(path, func() { calls.Add(1) }); err != nil {
+		t.Fatalf("Watch() returned an unexpected error: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if err := os.WriteFile(path, []byte{byte('0' + i)}, 0644); err != nil {
+			t.Fatalf("WriteFile() failed: %v", err)
+		}
+		time.Sleep(testDelay / 5)
+	}
+
+	if !waitFor(t, time.Second, func() bool { return calls.Load() > 0 }) {
+		t.Fatalf("Handler was never called")
+	}
+	time.Sleep(3 * testDelay) // Give any extra calls a chance to arrive
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Expected 1 debounced call, got %d", got)
+	}
+}
+
+// TestWatchSurvivesAtomicSave tests that replacing the file via rename keeps being noticed.
+func TestWatchSurvivesAtomicSave(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "emotions.json")
+	os.WriteFile(path, []byte("v1"), 0644)
+
+	w, err := New(testDelay)
+	if err != nil {
+		t.Fatalf("New() returned an unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	var calls atomic.Int32
+	w.Watch(path, func() { calls.Add(1) })
+
+	for i := 1; i <= 2; i++ {
+		tmp := filepath.Join(dir, ".emotions.json.tmp")
+		os.WriteFile(tmp, []byte("v2"), 0644)
+		if err := os.Rename(tmp, path); err != nil {
+			t.Fatalf("Rename() failed: %v", err)
+		}
+		want := int32(i)
+		if !waitFor(t, time.Second, func() bool { return calls.Load() >= want }) {
+			t.Fatalf("Save #%d was not noticed (calls=%d)", i, calls.Load())
+		}
+	}
+}
+
+// TestWatchIgnoresOtherFiles tests filtering by file name and Unwatch.
+func TestWatchIgnoresOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	watched := filepath.Join(dir, "journal.json")
+	w, err := New(testDelay)
+	if err != nil {
+		t.Fatalf("New() returned an unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	var calls atomic.Int32
+	w.Watch(watched, func() { calls.Add(1) })
+
+	// Neighbours in the same directory, like the journal lock file
+	os.WriteFile(filepath.Join(dir, "journal.json.lock"), []byte("1"), 0644)
+	os.WriteFile(filepath.Join(dir, "settings.json"), []byte("{}"), 0644)
+	time.Sleep(4 * testDelay)
+	if got := calls.Load(); got != 0 {
+		t.Errorf("Expected no calls for other files, got %d", got)
+	}
+
+	if err := w.Unwatch(watched); err != nil {
+		t.Fatalf("Unwatch() returned an unexpected error: %v", err)
+	}
+	os.WriteFile(watched, []byte("[]"), 0644)
+	time.Sleep(4 * testDelay)
+	if got := calls.Load(); got != 0 {
+		t.Errorf("Expected no calls after Unwatch, got %d", got)
+	}
+}